@@ -0,0 +1,81 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/server"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+// 保活检查任务
+type keepaliveJob struct {
+	Phone string    `json:"phone"`
+	Next  time.Time `json:"next"`
+	Prev  time.Time `json:"prev"`
+}
+
+// 运维管理接口
+func registerAdmin(admin *gin.RouterGroup, serv *server.TCPServer) {
+	cache := storage.GetDeviceCache()
+
+	admin.GET("/sessions", func(c *gin.Context) {
+		sessions := storage.ListSessions()
+		res := make([]*model.SessionStats, 0, len(sessions))
+		for _, s := range sessions {
+			res = append(res, s.Stats())
+		}
+		c.JSON(http.StatusOK, gin.H{"stats": serv.Stats(), "sessions": res})
+	})
+
+	admin.DELETE("/sessions/:id", func(c *gin.Context) {
+		if err := serv.Kick(c.Param("id")); err != nil {
+			abortWithError(c, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	admin.DELETE("/devices/:phone/session", func(c *gin.Context) {
+		device, err := cache.GetDeviceByPhone(c.Param("phone"))
+		if err != nil {
+			abortWithError(c, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		if err := serv.Kick(device.SessionID); err != nil {
+			abortWithError(c, http.StatusNotFound, CodeNotFound, err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+
+	admin.GET("/keepalive/jobs", func(c *gin.Context) {
+		entries := protocol.NewKeepaliveTimer().Jobs()
+		res := make([]*keepaliveJob, 0, len(entries))
+		for _, e := range entries {
+			job, ok := e.Job.(*protocol.CheckDeviceJob)
+			if !ok {
+				continue
+			}
+			res = append(res, &keepaliveJob{Phone: job.JobID(), Next: e.Next, Prev: e.Prev})
+		}
+		c.JSON(http.StatusOK, res)
+	})
+
+	admin.DELETE("/keepalive/jobs/:phone", func(c *gin.Context) {
+		protocol.NewKeepaliveTimer().Cancel(c.Param("phone"))
+		c.Status(http.StatusNoContent)
+	})
+
+	admin.POST("/persistence/flush", func(c *gin.Context) {
+		if err := storage.FlushAll(); err != nil {
+			abortWithError(c, http.StatusInternalServerError, CodeInternal, err.Error())
+			return
+		}
+		c.Status(http.StatusNoContent)
+	})
+}
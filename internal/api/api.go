@@ -1,31 +1,126 @@
 package api
 
 import (
+	"net"
 	"net/http"
-	"os"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/zerolog/log"
+	"google.golang.org/grpc"
 
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
 	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
 	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
 	"github.com/fakeyanss/jt808-server-go/internal/server"
 	"github.com/fakeyanss/jt808-server-go/internal/storage"
+	"github.com/fakeyanss/jt808-server-go/internal/tracing"
+	"github.com/fakeyanss/jt808-server-go/pkg/routines"
 )
 
-func Run(serv *server.TCPServer, cfg *config.Config) {
+const readHeaderTimeout = 10 * time.Second
+
+// 启动api服务，阻塞直到任一server退出并返回其错误
+func Run(serv *server.TCPServer, cfg *config.Config, certReloader *server.CertReloader) error {
 	// web server structure
 	gin.SetMode(gin.ReleaseMode)
 	router := gin.Default()
+	router.Use(tracing.Middleware())
+	cache := storage.GetDeviceCache()
+
+	metrics.RegisterDeviceStatus(func() map[string]int {
+		res := make(map[string]int)
+		for status, cnt := range cache.CountByStatus() {
+			res[status.String()] = cnt
+		}
+		return res
+	})
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+	registerHealth(router, serv)
+
+	authenticator, err := NewAuthenticator(cfg.Server.Auth)
+	if err != nil {
+		return errors.Wrap(err, "Fail to init api authenticator")
+	}
+	var auditLog string
+	if cfg.Server.Auth != nil {
+		auditLog = cfg.Server.Auth.AuditLog
+	}
+	audit, err := newAuditor(auditLog)
+	if err != nil {
+		return errors.Wrap(err, "Fail to init audit log")
+	}
+	if defaultDatum, err = coord.ParseDatum(cfg.Server.Coordinate); err != nil {
+		return errors.Wrap(err, "Fail to parse coordinate datum")
+	}
+
+	authed := router.Group("/", authenticator.Middleware())
+
+	registerAdmin(authed.Group("/admin", requireRole(RoleAdmin)), serv)
+	openAPI := genOpenAPI(v1BasePath, registerV1(authed, serv, audit))
+	router.GET("/openapi.json", func(c *gin.Context) {
+		c.JSON(http.StatusOK, openAPI)
+	})
+
+	registerLegacy(authed, serv, audit)
+
+	var grpcServ *grpc.Server
+	if cfg.Server.Port.GRPCPort != "" {
+		grpcServ = newGRPCServer(serv, authenticator, audit, certReloader)
+	}
+	return serve(router, grpcServ, cfg, certReloader)
+}
+
+// 注册未分版本的旧接口，保留兼容，新功能请使用 /api/v1
+func registerLegacy(authed *gin.RouterGroup, serv *server.TCPServer, audit *auditor) {
+	viewer := authed.Group("/", requireRole(RoleViewer))
+	operator := authed.Group("/", requireRole(RoleOperator))
 	cache := storage.GetDeviceCache()
 	geoCache := storage.GetGeoCache()
 
-	router.GET("/device", func(c *gin.Context) {
-		c.JSON(http.StatusOK, cache.ListDevice())
+	viewer.GET("/device", func(c *gin.Context) {
+		q, err := parseDeviceQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		// 保持旧接口返回设备数组，分页结果见 /api/v1/devices
+		devices, err := cache.FilterDevice(q)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, devices)
+	})
+
+	viewer.GET("/device/count", func(c *gin.Context) {
+		q, err := parseDeviceQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		cnt, err := cache.CountDevice(q)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"count": cnt})
 	})
 
-	router.GET("/device/:phone/geo", func(c *gin.Context) {
+	viewer.GET("/device/nearby", func(c *gin.Context) {
+		q, err := parseNearbyQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, nearbyVehicles(c, cache, q))
+	})
+
+	viewer.GET("/device/:phone/geo", func(c *gin.Context) {
 		phone := c.Param("phone")
 
 		device, err := cache.GetDeviceByPhone(phone)
@@ -33,6 +128,9 @@ func Run(serv *server.TCPServer, cfg *config.Config) {
 			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
 			return
 		}
+		if !authorizeDevice(c, device) {
+			return
+		}
 
 		res := make(map[string]any)
 		err = mapstructure.Decode(device, &res)
@@ -42,6 +140,7 @@ func Run(serv *server.TCPServer, cfg *config.Config) {
 		}
 		gis, err := geoCache.GetGeoLatestByPhone(phone)
 		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"err": err.Error()})
 			return
 		}
 		res["gis"] = gis
@@ -49,54 +148,143 @@ func Run(serv *server.TCPServer, cfg *config.Config) {
 		c.JSON(http.StatusOK, res)
 	})
 
-	router.GET("/device/:phone/params", func(c *gin.Context) {
+	viewer.GET("/device/:phone/trips", func(c *gin.Context) {
+		device, err := cache.GetDeviceByPhone(c.Param("phone"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		if !authorizeDevice(c, device) {
+			return
+		}
+		q, err := parseTripQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, newTripList(storage.GetTripCache(), device.Phone, q))
+	})
+
+	viewer.GET("/device/:phone/track/export", func(c *gin.Context) {
+		device, err := cache.GetDeviceByPhone(c.Param("phone"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		if !authorizeDevice(c, device) {
+			return
+		}
+		q, err := parseTrackExportQuery(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		writeTrackExport(c, device, q)
+	})
+
+	operator.GET("/device/:phone/params", func(c *gin.Context) {
 		phone := c.Param("phone")
 		device, err := cache.GetDeviceByPhone(phone)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
 			return
 		}
+		if !authorizeDevice(c, device) {
+			return
+		}
 		session, err := storage.GetSession(device.SessionID)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
 		}
 		header := model.GenMsgHeader(device, 0x8104, session.GetNextSerialNum())
 		msg := model.Msg8104{
 			Header: header,
 		}
-		serv.Send(session.ID, &msg)
+		if err := audit.send(c.Request.Context(), callerOf(c), serv, session.ID, &msg); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"err": err.Error()})
+			return
+		}
 		// todo: read channel from process 0104 msg
 	})
 
-	router.PUT("/device/:phone/params", func(c *gin.Context) {
+	operator.PUT("/device/:phone/params", func(c *gin.Context) {
 		phone := c.Param("phone")
 		params := model.DeviceParams{}
 		if err := c.ShouldBind(&params); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
+		if err := params.Normalize(); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
 		}
 		device, err := cache.GetDeviceByPhone(phone)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
 			return
 		}
+		if !authorizeDevice(c, device) {
+			return
+		}
+		if err := checkParamVersions(&params, device); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
+		}
 		session, err := storage.GetSession(device.SessionID)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"err": err.Error()})
+			return
 		}
 		header := model.GenMsgHeader(device, 0x8103, session.GetNextSerialNum())
 		msg := model.Msg8103{
 			Header:     header,
 			Parameters: &params,
 		}
-		serv.Send(session.ID, &msg)
+		if err := audit.send(c.Request.Context(), callerOf(c), serv, session.ID, &msg); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"err": err.Error()})
+			return
+		}
 	})
+}
 
-	httpAddr := ":" + cfg.Server.Port.HTTPPort
+// 启动明文和TLS的http server及grpc server，各自使用不同端口，任一退出则返回其错误
+func serve(handler http.Handler, grpcServ *grpc.Server, cfg *config.Config, certReloader *server.CertReloader) error {
+	errCh := make(chan error, 3)
+	if cfg.Server.Port.HTTPPort != "" {
+		httpAddr := ":" + cfg.Server.Port.HTTPPort
+		routines.GoSafe(func() {
+			log.Debug().Msgf("Listening and serving HTTP on %s", httpAddr)
+			srv := &http.Server{Addr: httpAddr, Handler: handler, ReadHeaderTimeout: readHeaderTimeout}
+			errCh <- errors.Wrapf(srv.ListenAndServe(), "addr=%s", httpAddr)
+		})
+	}
+	if certReloader != nil && cfg.Server.Port.HTTPSPort != "" {
+		httpsAddr := ":" + cfg.Server.Port.HTTPSPort
+		routines.GoSafe(func() {
+			log.Debug().Msgf("Listening and serving HTTPS on %s", httpsAddr)
+			srv := &http.Server{
+				Addr:              httpsAddr,
+				Handler:           handler,
+				TLSConfig:         certReloader.APITLSConfig(),
+				ReadHeaderTimeout: readHeaderTimeout,
+			}
+			errCh <- errors.Wrapf(srv.ListenAndServeTLS("", ""), "addr=%s", httpsAddr) // 证书由TLSConfig提供
+		})
+	}
 
-	log.Debug().Msgf("Listening and serving HTTP on :%s", cfg.Server.Port.HTTPPort)
-	err := router.Run(httpAddr)
-	if err != nil {
-		log.Error().Err(err).Str("addr", httpAddr).Msg("Fail to run gin router")
-		os.Exit(1)
+	if grpcServ != nil {
+		grpcAddr := ":" + cfg.Server.Port.GRPCPort
+		routines.GoSafe(func() {
+			lis, err := net.Listen("tcp", grpcAddr)
+			if err != nil {
+				errCh <- errors.Wrapf(err, "addr=%s", grpcAddr)
+				return
+			}
+			log.Debug().Msgf("Listening and serving gRPC on %s", grpcAddr)
+			errCh <- errors.Wrapf(grpcServ.Serve(lis), "addr=%s", grpcAddr)
+		})
 	}
+
+	return <-errCh
 }
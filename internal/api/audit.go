@@ -0,0 +1,83 @@
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/server"
+)
+
+const (
+	auditMaxSizeMB  = 100
+	auditMaxBackups = 30
+)
+
+// 下发指令审计，记录谁向哪个终端下发了什么指令
+type auditor struct {
+	logger zerolog.Logger
+}
+
+// path为空时写入服务日志，否则按json行写入单独的滚动文件
+func newAuditor(path string) (*auditor, error) {
+	if path == "" {
+		return &auditor{logger: log.Logger.With().Str("log_type", "audit").Logger()}, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, errors.Wrap(err, "Fail to create audit log directory")
+	}
+	w := &lumberjack.Logger{Filename: path, MaxSize: auditMaxSizeMB, MaxBackups: auditMaxBackups}
+	return &auditor{logger: zerolog.New(w).With().Timestamp().Logger()}, nil
+}
+
+// 下发指令的调用方，用于审计
+type caller struct {
+	principal *Principal
+	clientIP  string
+	method    string
+	path      string
+}
+
+func callerOf(c *gin.Context) *caller {
+	return &caller{principal: principalOf(c), clientIP: c.ClientIP(), method: c.Request.Method, path: c.FullPath()}
+}
+
+// 下发消息到终端并记录审计日志
+func (a *auditor) send(ctx context.Context, who *caller, serv *server.TCPServer, sessionID string, msg model.JT808Msg) error {
+	err := serv.SendContext(ctx, sessionID, msg)
+	a.record(who, msg.GetHeader(), err)
+	return err
+}
+
+// 下发消息到终端并等待应答，记录审计日志
+func (a *auditor) request(ctx context.Context, who *caller, serv *server.TCPServer, sessionID string, msg model.JT808Msg) (model.JT808Msg, error) {
+	answer, err := serv.Request(ctx, sessionID, msg)
+	a.record(who, msg.GetHeader(), err)
+	return answer, err
+}
+
+func (a *auditor) record(who *caller, h *model.MsgHeader, err error) {
+	event := a.logger.Info()
+	if err != nil {
+		event = a.logger.Warn().Err(err)
+	}
+	event.
+		Str("principal", who.principal.Name).
+		Str("role", string(who.principal.Role)).
+		Str("client_ip", who.clientIP).
+		Str("method", who.method).
+		Str("path", who.path).
+		Str("phone", h.PhoneNumber).
+		Str("msg_id", metrics.MsgIDLabel(h.MsgID)).
+		Uint16("serial_number", h.SerialNumber).
+		Bool("success", err == nil).
+		Msg("Downlink command")
+}
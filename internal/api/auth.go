@@ -0,0 +1,212 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+var (
+	ErrMissingCredential = errors.New("Missing api key or bearer token")
+	ErrInvalidCredential = errors.New("Invalid api key or bearer token")
+	ErrInvalidRole       = errors.New("Invalid role")
+)
+
+const (
+	apiKeyHeader       = "X-API-Key"
+	principalCtxKey    = "jt808.principal"
+	anonymousPrincipal = "anonymous"
+)
+
+// 调用方角色，权限依次递增
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // 只读
+	RoleOperator Role = "operator" // 可下发指令
+	RoleAdmin    Role = "admin"    // 可使用运维接口
+)
+
+func (r Role) level() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleOperator:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// 已鉴权的调用方
+type Principal struct {
+	Name   string
+	Role   Role
+	Groups []string // 可访问的设备分组，为空表示不限制
+}
+
+func (p *Principal) HasRole(r Role) bool {
+	return p.Role.level() >= r.level()
+}
+
+// 判断调用方能否访问该设备。admin和未限制分组的调用方可访问所有设备
+func (p *Principal) CanAccessDevice(d *model.Device) bool {
+	if p.Role == RoleAdmin || len(p.Groups) == 0 {
+		return true
+	}
+	for _, g := range storage.GetGroupCache().GroupsOfDevice(d) {
+		for _, allowed := range p.Groups {
+			if g == allowed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 判断调用方能否管理该分组
+func (p *Principal) CanAccessGroup(name string) bool {
+	if p.Role == RoleAdmin || len(p.Groups) == 0 {
+		return true
+	}
+	for _, allowed := range p.Groups {
+		if allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
+type jwtClaims struct {
+	jwt.RegisteredClaims
+	Role   Role     `json:"role"`
+	Groups []string `json:"groups"`
+}
+
+// 支持API Key和JWT两种鉴权方式
+type Authenticator struct {
+	enable  bool
+	apiKeys []*config.APIKeyConf
+	secret  []byte
+	issuer  string
+}
+
+func NewAuthenticator(conf *config.AuthConf) (*Authenticator, error) {
+	a := &Authenticator{}
+	if conf == nil || !conf.Enable {
+		log.Warn().Msg("API authentication is DISABLED, every caller is treated as admin. " +
+			"Set server.auth.enable=true and configure apiKeys or jwt before exposing the api port")
+		return a, nil
+	}
+	a.enable = true
+	for _, k := range conf.APIKeys {
+		if Role(k.Role).level() == 0 {
+			return nil, errors.Wrapf(ErrInvalidRole, "api key %s, role %q", k.Name, k.Role)
+		}
+	}
+	a.apiKeys = conf.APIKeys
+	if conf.JWT != nil && conf.JWT.Secret != "" {
+		a.secret = []byte(conf.JWT.Secret)
+		a.issuer = conf.JWT.Issuer
+	}
+	return a, nil
+}
+
+// 从请求中解析调用方。未开启鉴权时返回admin角色的匿名调用方
+func (a *Authenticator) Authenticate(r *http.Request) (*Principal, error) {
+	return a.authenticate(r.Header.Get)
+}
+
+// 通过header取值方法解析调用方，http header和grpc metadata共用
+func (a *Authenticator) authenticate(header func(key string) string) (*Principal, error) {
+	if !a.enable {
+		return &Principal{Name: anonymousPrincipal, Role: RoleAdmin}, nil
+	}
+	if key := header(apiKeyHeader); key != "" {
+		return a.authAPIKey(key)
+	}
+	if auth := header("Authorization"); strings.HasPrefix(auth, "Bearer ") && a.secret != nil {
+		return a.authJWT(strings.TrimPrefix(auth, "Bearer "))
+	}
+	return nil, ErrMissingCredential
+}
+
+func (a *Authenticator) authAPIKey(key string) (*Principal, error) {
+	for _, k := range a.apiKeys {
+		if subtle.ConstantTimeCompare([]byte(k.Key), []byte(key)) == 1 {
+			return &Principal{Name: k.Name, Role: Role(k.Role), Groups: k.Groups}, nil
+		}
+	}
+	return nil, ErrInvalidCredential
+}
+
+func (a *Authenticator) authJWT(tokenStr string) (*Principal, error) {
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithExpirationRequired(), // 不接受永不过期的token
+	}
+	if a.issuer != "" {
+		opts = append(opts, jwt.WithIssuer(a.issuer))
+	}
+	claims := &jwtClaims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, func(*jwt.Token) (any, error) { return a.secret, nil }, opts...)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCredential, err.Error())
+	}
+	if claims.Role.level() == 0 {
+		return nil, errors.Wrapf(ErrInvalidRole, "role %q", claims.Role)
+	}
+	return &Principal{Name: claims.Subject, Role: claims.Role, Groups: claims.Groups}, nil
+}
+
+// 鉴权中间件，将调用方记录到gin context
+func (a *Authenticator) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := a.Authenticate(c.Request)
+		if err != nil {
+			respondError(c, err)
+			return
+		}
+		c.Set(principalCtxKey, p)
+		c.Next()
+	}
+}
+
+// 要求调用方至少具有role角色
+func requireRole(role Role) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !principalOf(c).HasRole(role) {
+			abortWithError(c, http.StatusForbidden, CodePermissionDenied, "Permission denied, require role "+string(role))
+			return
+		}
+		c.Next()
+	}
+}
+
+// 获取当前请求的调用方，未经过鉴权中间件时返回无权限的调用方
+func principalOf(c *gin.Context) *Principal {
+	if v, ok := c.Get(principalCtxKey); ok {
+		return v.(*Principal)
+	}
+	return &Principal{Name: anonymousPrincipal}
+}
+
+// 校验调用方能否访问设备，无权限时写入403并返回false
+func authorizeDevice(c *gin.Context, d *model.Device) bool {
+	if principalOf(c).CanAccessDevice(d) {
+		return true
+	}
+	abortWithError(c, http.StatusForbidden, CodePermissionDenied, "Permission denied for device "+d.Phone)
+	return false
+}
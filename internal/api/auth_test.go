@@ -0,0 +1,134 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+)
+
+func signTestJWT(t *testing.T, secret string, claims *jwtClaims) string {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func TestAuthenticator_Authenticate(t *testing.T) {
+	a, err := NewAuthenticator(&config.AuthConf{
+		Enable: true,
+		APIKeys: []*config.APIKeyConf{
+			{Name: "dashboard", Key: "viewer-key", Role: "viewer"},
+			{Name: "ops", Key: "operator-key", Role: "operator", Groups: []string{"fleet-a"}},
+		},
+		JWT: &config.JWTConf{Secret: "secret", Issuer: "jt808"},
+	})
+	require.NoError(t, err)
+
+	expiresAt := jwt.NewNumericDate(time.Now().Add(time.Hour))
+	tests := []struct {
+		name    string
+		header  map[string]string
+		want    *Principal
+		wantErr error
+	}{
+		{
+			name:    "no credential",
+			wantErr: ErrMissingCredential,
+		},
+		{
+			name:   "api key",
+			header: map[string]string{apiKeyHeader: "operator-key"},
+			want:   &Principal{Name: "ops", Role: RoleOperator, Groups: []string{"fleet-a"}},
+		},
+		{
+			name:    "wrong api key",
+			header:  map[string]string{apiKeyHeader: "bad-key"},
+			wantErr: ErrInvalidCredential,
+		},
+		{
+			name: "jwt",
+			header: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "secret", &jwtClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "alice", Issuer: "jt808", ExpiresAt: expiresAt},
+				Role:             RoleAdmin,
+			})},
+			want: &Principal{Name: "alice", Role: RoleAdmin},
+		},
+		{
+			name: "jwt wrong secret",
+			header: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "other", &jwtClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "alice", Issuer: "jt808", ExpiresAt: expiresAt},
+				Role:             RoleAdmin,
+			})},
+			wantErr: ErrInvalidCredential,
+		},
+		{
+			name: "jwt wrong issuer",
+			header: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "secret", &jwtClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "alice", Issuer: "other", ExpiresAt: expiresAt},
+				Role:             RoleAdmin,
+			})},
+			wantErr: ErrInvalidCredential,
+		},
+		{
+			name: "jwt unknown role",
+			header: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "secret", &jwtClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "alice", Issuer: "jt808", ExpiresAt: expiresAt},
+				Role:             "root",
+			})},
+			wantErr: ErrInvalidRole,
+		},
+		{
+			name: "jwt without exp",
+			header: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "secret", &jwtClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "alice", Issuer: "jt808"},
+				Role:             RoleViewer,
+			})},
+			wantErr: ErrInvalidCredential,
+		},
+		{
+			name: "jwt expired",
+			header: map[string]string{"Authorization": "Bearer " + signTestJWT(t, "secret", &jwtClaims{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: "alice", Issuer: "jt808", ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute))},
+				Role:             RoleViewer,
+			})},
+			wantErr: ErrInvalidCredential,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/device", nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			got, err := a.Authenticate(req)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestAuthenticator_Disabled(t *testing.T) {
+	a, err := NewAuthenticator(&config.AuthConf{})
+	require.NoError(t, err)
+	p, err := a.Authenticate(httptest.NewRequest(http.MethodGet, "/device", nil))
+	require.NoError(t, err)
+	assert.True(t, p.HasRole(RoleAdmin))
+}
+
+func TestPrincipal_HasRole(t *testing.T) {
+	operator := &Principal{Role: RoleOperator}
+	assert.True(t, operator.HasRole(RoleViewer))
+	assert.True(t, operator.HasRole(RoleOperator))
+	assert.False(t, operator.HasRole(RoleAdmin))
+	assert.False(t, (&Principal{}).HasRole(RoleViewer))
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+)
+
+// 返回坐标的默认坐标系，见配置server.coordinate。服务内部统一保存WGS-84坐标
+var defaultDatum = coord.WGS84
+
+var coordQueryParam = stringParam("coord", "坐标系，默认见配置server.coordinate",
+	string(coord.WGS84), string(coord.GCJ02), string(coord.BD09))
+
+func parseDatum(v string) (coord.Datum, error) {
+	if v == "" {
+		return defaultDatum, nil
+	}
+	d, err := coord.ParseDatum(v)
+	if err != nil {
+		return "", errors.Wrapf(ErrInvalidQuery, "coord=%s", v)
+	}
+	return d, nil
+}
+
+// 解析查询参数coord，失败时已写入错误响应
+func datumOf(c *gin.Context) (coord.Datum, bool) {
+	d, err := parseDatum(c.Query("coord"))
+	if err != nil {
+		respondError(c, err)
+		return "", false
+	}
+	return d, true
+}
+
+// 将WGS-84坐标转换为目标坐标系
+func toDatum(p geo.Point, d coord.Datum) geo.Point {
+	return coord.Convert(p, coord.WGS84, d)
+}
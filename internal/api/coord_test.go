@@ -0,0 +1,71 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func TestV1_Coord(t *testing.T) {
+	router, _ := newTestRouter(t)
+	phone := "13900000046"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, Status: model.DeviceStatusOnline})
+	t.Cleanup(func() {
+		storage.GetDeviceCache().DelDeviceByPhone(phone)
+		storage.GetGeoCache().DelGeoByPhone(phone)
+		storage.GetGeofenceCache().DelGeofence(46)
+	})
+	wgs := geo.Point{Lat: 39.908692, Lng: 116.397477}
+	storage.GetGeoCache().GetGeoRingByPhone(phone).Write(&model.DeviceGeo{
+		Phone:    phone,
+		Geo:      &model.GeoMeta{LocationStatus: 1},
+		Location: &model.Location{Latitude: wgs.Lat, Longitude: wgs.Lng},
+	})
+
+	tests := []struct {
+		name  string
+		query string
+		want  geo.Point
+		coord string
+	}{
+		{name: "default", query: "", want: wgs, coord: "wgs84"},
+		{name: "gcj02", query: "?coord=gcj02", want: coord.WGS84ToGCJ02(wgs), coord: "gcj02"},
+		{name: "bd09", query: "?coord=bd09", want: coord.Convert(wgs, coord.WGS84, coord.BD09), coord: "bd09"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(t, router, http.MethodGet, "/api/v1/devices/"+phone+"/location"+tt.query, "viewer-key", nil)
+			require.Equal(t, http.StatusOK, w.Code)
+			res := &LocationDTO{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+			assert.Equal(t, tt.coord, res.Coord)
+			assert.InDelta(t, tt.want.Lat, res.Latitude, 1e-9)
+			assert.InDelta(t, tt.want.Lng, res.Longitude, 1e-9)
+		})
+	}
+	w := doRequest(t, router, http.MethodGet, "/api/v1/devices/"+phone+"/location?coord=wgs", "viewer-key", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	// 围栏按请求的坐标系输入，保存为WGS-84
+	gcj := coord.WGS84ToGCJ02(wgs)
+	body, err := json.Marshal(&GeofenceDTO{Type: "circle", Center: &gcj, Radius: 100})
+	require.NoError(t, err)
+	w = doRequest(t, router, http.MethodPut, "/api/v1/geofences/46?coord=gcj02", "admin-key", body)
+	require.Equal(t, http.StatusOK, w.Code)
+	fence := &GeofenceDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), fence))
+	assert.InDelta(t, gcj.Lat, fence.Center.Lat, 1e-9)
+
+	f, err := storage.GetGeofenceCache().GetGeofence(46)
+	require.NoError(t, err)
+	assert.InDelta(t, wgs.Lat, f.Center.Lat, 1e-7)
+	assert.InDelta(t, wgs.Lng, f.Center.Lng, 1e-7)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+var ErrInvalidQuery = errors.New("Invalid query parameter")
+
+// 解析设备列表的查询参数:
+//
+//	status   设备状态 offline / online / sleeping
+//	version  协议版本 2011 / 2013 / 2019
+//	plate    车牌号前缀
+//	phone    手机号前缀
+//	from, to 最近通信时间范围，RFC3339格式，[from, to)
+//	region   行政区划代码前缀
+//	group    设备分组名
+//	sort     排序字段 phone / plate / lastComTime / status
+//	order    asc / desc
+//	cursor   上一页返回的nextCursor
+//	limit    每页条数
+func parseDeviceQuery(c *gin.Context) (*storage.DeviceQuery, error) {
+	return buildDeviceQuery(principalOf(c), c.Query)
+}
+
+// 按参数名取值构造设备查询，http query和grpc请求共用
+func buildDeviceQuery(p *Principal, get func(key string) string) (*storage.DeviceQuery, error) {
+	q := &storage.DeviceQuery{
+		PlatePrefix: get("plate"),
+		PhonePrefix: get("phone"),
+		RegionCode:  get("region"),
+		Group:       get("group"),
+		Filter:      p.CanAccessDevice,
+		SortBy:      get("sort"),
+		Cursor:      get("cursor"),
+	}
+	if v := get("status"); v != "" {
+		status, ok := model.ParseDeviceStatus(v)
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidQuery, "status=%s", v)
+		}
+		q.Status = &status
+	}
+	if v := get("version"); v != "" {
+		version, ok := model.ParseVersionType(v)
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidQuery, "version=%s", v)
+		}
+		q.VersionDesc = &version
+	}
+	var err error
+	if q.ComTimeFrom, err = parseQueryTime(get, "from"); err != nil {
+		return nil, err
+	}
+	if q.ComTimeTo, err = parseQueryTime(get, "to"); err != nil {
+		return nil, err
+	}
+	switch order := get("order"); order {
+	case "", "asc":
+	case "desc":
+		q.Desc = true
+	default:
+		return nil, errors.Wrapf(ErrInvalidQuery, "order=%s", order)
+	}
+	if v := get("limit"); v != "" {
+		if q.Limit, err = strconv.Atoi(v); err != nil || q.Limit <= 0 {
+			return nil, errors.Wrapf(ErrInvalidQuery, "limit=%s", v)
+		}
+	}
+	return q, nil
+}
+
+func parseQueryTime(get func(key string) string, key string) (time.Time, error) {
+	v := get(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(ErrInvalidQuery, "%s=%s", key, v)
+	}
+	return t, nil
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fakeyanss/jt808-server-go/internal/driving"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+// 平台侧驾驶规则的接口
+func (h *v1Handler) drivingOperations() []*operation {
+	return []*operation{
+		{
+			Method: http.MethodGet, Path: "/devices/:phone/driving-rule", Summary: "查询设备的平台侧超速、疲劳驾驶规则及状态",
+			Tag: "driving", Role: RoleViewer, Response: DrivingRuleDTO{}, Handler: h.getDrivingRule,
+		},
+		{
+			Method: http.MethodPut, Path: "/devices/:phone/driving-rule", Summary: "设置设备的驾驶规则覆盖项",
+			Tag: "driving", Role: RoleOperator, Request: model.DrivingRuleOverride{}, Response: DrivingRuleDTO{},
+			Handler: h.putDrivingRule,
+		},
+		{
+			Method: http.MethodDelete, Path: "/devices/:phone/driving-rule", Summary: "删除设备的驾驶规则覆盖项，恢复默认规则",
+			Tag: "driving", Role: RoleOperator, Status: http.StatusNoContent, Handler: h.deleteDrivingRule,
+		},
+	}
+}
+
+func newDrivingRuleDTO(phone string, o *model.DrivingRuleOverride) *DrivingRuleDTO {
+	res := &DrivingRuleDTO{
+		Enable:   driving.Enabled(),
+		Rule:     o.Apply(driving.DefaultRule()),
+		Override: o,
+	}
+	if st := driving.StatusOf(phone); st != nil {
+		res.Status = &DrivingStatusDTO{
+			AlarmSign:            st.Alarm,
+			ContinuousDrivingSec: int64(st.ContinuousDriving.Seconds()),
+			DailyDrivingSec:      int64(st.DailyDriving.Seconds()),
+		}
+	}
+	return res
+}
+
+func (h *v1Handler) getDrivingRule(c *gin.Context) {
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, newDrivingRuleDTO(d.Phone, h.drivingRuleCache.GetOverride(d.Phone)))
+}
+
+func (h *v1Handler) putDrivingRule(c *gin.Context) {
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	o := &model.DrivingRuleOverride{}
+	if err := c.ShouldBindJSON(o); err != nil {
+		badRequest(c, err)
+		return
+	}
+	h.drivingRuleCache.CacheOverride(d.Phone, o)
+	c.JSON(http.StatusOK, newDrivingRuleDTO(d.Phone, o))
+}
+
+func (h *v1Handler) deleteDrivingRule(c *gin.Context) {
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	h.drivingRuleCache.DelOverride(d.Phone)
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/driving"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func TestV1_DrivingRule(t *testing.T) {
+	router, _ := newTestRouter(t)
+	phone := "13900000045"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, Status: model.DeviceStatusOffline})
+	driving.Configure(&config.DrivingRuleConf{Enable: true, MaxSpeed: 120, OverspeedDuration: 10})
+	t.Cleanup(func() {
+		storage.GetDeviceCache().DelDeviceByPhone(phone)
+		storage.GetDrivingRuleCache().DelOverride(phone)
+		driving.Configure(nil)
+	})
+	path := "/api/v1/devices/" + phone + "/driving-rule"
+
+	w := doRequest(t, router, http.MethodGet, path, "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res := &DrivingRuleDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.True(t, res.Enable)
+	assert.Equal(t, uint32(120), res.Rule.MaxSpeed)
+	assert.Nil(t, res.Override)
+	assert.Nil(t, res.Status)
+
+	body := []byte(`{"maxSpeed":80}`)
+	w = doRequest(t, router, http.MethodPut, path, "viewer-key", body)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doRequest(t, router, http.MethodPut, path, "admin-key", body)
+	require.Equal(t, http.StatusOK, w.Code)
+	res = &DrivingRuleDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, model.DrivingRule{MaxSpeed: 80, OverspeedDuration: 10}, res.Rule)
+	assert.Equal(t, uint32(80), driving.RuleOf(phone).MaxSpeed)
+
+	w = doRequest(t, router, http.MethodDelete, path, "admin-key", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, uint32(120), driving.RuleOf(phone).MaxSpeed)
+}
@@ -0,0 +1,777 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/region"
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+var ErrInvalidParam = errors.New("Invalid device param")
+
+// 设备信息
+type DeviceDTO struct {
+	Phone           string    `json:"phone" description:"终端手机号，设备唯一标识"`
+	ID              string    `json:"id" description:"终端ID"`
+	Plate           string    `json:"plate" description:"车牌号"`
+	PlateColor      uint8     `json:"plateColor" description:"车牌颜色"`
+	Status          string    `json:"status" binding:"oneof=offline online sleeping"`
+	Version         string    `json:"version" binding:"oneof=2011 2013 2019" description:"JT808协议版本"`
+	ProtocolVersion uint8     `json:"protocolVersion" description:"2019版本协议版本号"`
+	RegionCode      string    `json:"regionCode" description:"GB/T 2260 行政区划代码"`
+	ManufacturerID  string    `json:"manufacturerId"`
+	DeviceMode      string    `json:"deviceMode"`
+	IMEI            string    `json:"imei"`
+	SoftwareVersion string    `json:"softwareVersion"`
+	TransProto      string    `json:"transProto"`
+	KeepaliveSec    int64     `json:"keepaliveSec" description:"保活时长，秒"`
+	LastComTime     time.Time `json:"lastComTime" description:"最近一次通信时间"`
+
+	Properties *DevicePropertiesDTO `json:"properties,omitempty" description:"终端属性，查询终端属性后返回"`
+}
+
+func newDeviceDTO(d *model.Device) *DeviceDTO {
+	return &DeviceDTO{
+		Phone:           d.Phone,
+		ID:              d.ID,
+		Plate:           d.Plate,
+		PlateColor:      d.PlateColor,
+		Status:          d.Status.String(),
+		Version:         d.VersionDesc.String(),
+		ProtocolVersion: d.ProtocolVersion,
+		RegionCode:      d.RegionCode(),
+		ManufacturerID:  d.ManufacturerID,
+		DeviceMode:      d.DeviceMode,
+		IMEI:            d.IMEI,
+		SoftwareVersion: d.SoftwareVersion,
+		TransProto:      string(d.TransProto),
+		KeepaliveSec:    int64(d.Keepalive / time.Second),
+		LastComTime:     d.LastestComTime,
+		Properties:      newDevicePropertiesDTO(d.Properties),
+	}
+}
+
+// 终端属性
+type DevicePropertiesDTO struct {
+	TerminalTypes   []string  `json:"terminalTypes" description:"终端类型：passenger dangerousGoods freight taxi videoRecording split trailer"`
+	ManufacturerID  string    `json:"manufacturerId"`
+	TerminalModel   string    `json:"terminalModel"`
+	TerminalID      string    `json:"terminalId"`
+	ICCID           string    `json:"iccid"`
+	HardwareVersion string    `json:"hardwareVersion"`
+	FirmwareVersion string    `json:"firmwareVersion"`
+	GNSS            []string  `json:"gnss" description:"支持的定位系统：GPS BDS GLONASS Galileo"`
+	Comm            []string  `json:"comm" description:"支持的通信方式：GPRS CDMA TD-SCDMA WCDMA CDMA2000 TD-LTE other"`
+	UpdatedAt       time.Time `json:"updatedAt" description:"终端应答时间"`
+}
+
+func newDevicePropertiesDTO(p *model.DeviceProperties) *DevicePropertiesDTO {
+	if p == nil {
+		return nil
+	}
+	return &DevicePropertiesDTO{
+		TerminalTypes:   p.TerminalTypes(),
+		ManufacturerID:  p.ManufacturerID,
+		TerminalModel:   p.TerminalModel,
+		TerminalID:      p.TerminalID,
+		ICCID:           p.ICCID,
+		HardwareVersion: p.HardwareVersion,
+		FirmwareVersion: p.FirmwareVersion,
+		GNSS:            p.GNSS(),
+		Comm:            p.Comm(),
+		UpdatedAt:       p.UpdatedAt,
+	}
+}
+
+type DevicePageDTO struct {
+	Devices    []*DeviceDTO `json:"devices"`
+	Total      int          `json:"total" description:"满足条件的设备总数"`
+	NextCursor string       `json:"nextCursor" description:"下一页游标，为空表示没有下一页"`
+}
+
+func newDevicePageDTO(page *storage.DevicePage) *DevicePageDTO {
+	res := &DevicePageDTO{Devices: make([]*DeviceDTO, 0, len(page.Devices)), Total: page.Total, NextCursor: page.NextCursor}
+	for _, d := range page.Devices {
+		res.Devices = append(res.Devices, newDeviceDTO(d))
+	}
+	return res
+}
+
+type CountDTO struct {
+	Count int `json:"count"`
+}
+
+// 设备最新位置
+type LocationDTO struct {
+	Phone     string    `json:"phone"`
+	Time      time.Time `json:"time" description:"定位时间"`
+	Latitude  float64   `json:"latitude"`
+	Longitude float64   `json:"longitude"`
+	Altitude  uint16    `json:"altitude" description:"海拔，米"`
+	Speed     float64   `json:"speed" description:"速度，km/h"`
+	Direction uint16    `json:"direction" description:"方向，0-359，正北为0，顺时针"`
+	ACC       bool      `json:"acc" description:"ACC是否开"`
+	Located   bool      `json:"located" description:"是否已定位"`
+	Driving   bool      `json:"driving" description:"是否行驶中"`
+	Coord     string    `json:"coord" description:"经纬度的坐标系"`
+
+	Region  *region.AdministrativeRegion `json:"region,omitempty" description:"所在行政区，配置行政区划边界数据后按位置逆地理编码"`
+	Quality []string                     `json:"quality,omitempty" description:"数据质量过滤标记的问题: unfixed / satellites / speed / future / stale，为空表示有效"`
+}
+
+func newLocationDTO(g *model.DeviceGeo, datum coord.Datum) *LocationDTO {
+	res := &LocationDTO{Phone: g.Phone, Time: g.Time, Coord: string(datum), Region: g.Region, Quality: g.Quality}
+	if g.Location != nil {
+		p := toDatum(geo.Point{Lat: g.Location.Latitude, Lng: g.Location.Longitude}, datum)
+		res.Latitude = p.Lat
+		res.Longitude = p.Lng
+		res.Altitude = g.Location.Altitude
+	}
+	if g.Drive != nil {
+		res.Speed = g.Drive.Speed
+		res.Direction = g.Drive.Direction
+	}
+	if g.Geo != nil {
+		res.ACC = g.Geo.ACCStatus == 1
+		res.Located = g.Geo.LocationStatus == 1
+		res.Driving = g.Geo.DrivingStatus == 1
+	}
+	return res
+}
+
+// 附近或矩形范围内的车辆
+type VehicleLocationDTO struct {
+	Phone    string       `json:"phone"`
+	Plate    string       `json:"plate"`
+	Status   string       `json:"status"`
+	Distance float64      `json:"distance" description:"到查询中心的距离，米，矩形查询时为0"`
+	Location *LocationDTO `json:"location" description:"最后一次定位的位置"`
+}
+
+type VehicleLocationListDTO struct {
+	Vehicles []*VehicleLocationDTO `json:"vehicles"`
+}
+
+// 终端参数项
+type ParamItem struct {
+	ID    uint32 `json:"id,omitempty" description:"参数ID，与name二选一"`
+	Name  string `json:"name,omitempty" description:"参数名，与id二选一，见 /api/v1/params/schema"`
+	Value any    `json:"value" binding:"required" description:"参数值，数值型参数传数字，bcd型传数字字符串，raw型及未知参数传hex字符串，其余传字符串"`
+}
+
+// 设置终端参数请求
+type SetParamsRequest struct {
+	Params []*ParamItem `json:"params" binding:"required,min=1,max=255,dive"`
+}
+
+// 校验参数值，不校验终端的协议版本
+func (r *SetParamsRequest) validate() error {
+	_, err := r.toDeviceParams(nil)
+	return err
+}
+
+// 转换为终端参数，d不为空时校验该终端的协议版本是否支持各参数
+func (r *SetParamsRequest) toDeviceParams(d *model.Device) (*model.DeviceParams, error) {
+	params := &model.DeviceParams{ParamCnt: uint8(len(r.Params))}
+	if d != nil {
+		params.DevicePhone = d.Phone
+	}
+	for _, p := range r.Params {
+		if p.ID == 0 && p.Name == "" {
+			return nil, errors.Wrap(ErrInvalidParam, "id or name is required")
+		}
+		param, err := model.NewParamData(p.ID, p.Name, p.Value)
+		if err != nil {
+			return nil, errors.Wrap(ErrInvalidParam, err.Error())
+		}
+		params.Params = append(params.Params, param)
+	}
+	if d != nil {
+		if err := checkParamVersions(params, d); err != nil {
+			return nil, err
+		}
+	}
+	return params, nil
+}
+
+// 校验终端的协议版本是否支持各参数
+func checkParamVersions(params *model.DeviceParams, d *model.Device) error {
+	for _, p := range params.Params {
+		if !model.ParamSupportsVersion(p.ParamID, d.VersionDesc) {
+			return errors.Wrapf(ErrInvalidParam, "param 0x%04x is not supported by %s version terminal", p.ParamID, d.VersionDesc)
+		}
+	}
+	return nil
+}
+
+// 查询终端参数请求，id和name均为空时查询全部参数(0x8104)，否则查询指定参数(0x8106)
+type QueryParamsRequest struct {
+	IDs   []uint32 `json:"ids" binding:"max=255" description:"参数ID列表"`
+	Names []string `json:"names" binding:"max=255" description:"参数名列表"`
+}
+
+func (r *QueryParamsRequest) paramIDs() ([]uint32, error) {
+	if r == nil {
+		return nil, nil
+	}
+	ids := append([]uint32{}, r.IDs...)
+	for _, name := range r.Names {
+		s, ok := model.LookupParamByName(name)
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidParam, "param name %s is not supported", name)
+		}
+		ids = append(ids, s.ID)
+	}
+	if len(ids) > 255 {
+		return nil, errors.Wrap(ErrInvalidParam, "too many params")
+	}
+	return ids, nil
+}
+
+// 终端参数定义
+type ParamSchemaDTO struct {
+	ID       uint32   `json:"id"`
+	HexID    string   `json:"hexId" description:"十六进制参数ID，如0x0001"`
+	Name     string   `json:"name"`
+	Desc     string   `json:"desc"`
+	Type     string   `json:"type" binding:"oneof=uint8 uint16 uint32 string bcd raw"`
+	Unit     string   `json:"unit,omitempty"`
+	Min      *uint32  `json:"min,omitempty" description:"数值型参数的最小值"`
+	Max      *uint32  `json:"max,omitempty" description:"数值型参数的最大值"`
+	Protocol string   `json:"protocol" binding:"oneof=JT808 JT1078"`
+	Versions []string `json:"versions" description:"支持的JT808协议版本"`
+}
+
+func newParamSchemaDTO(s *model.ParamSchema) *ParamSchemaDTO {
+	res := &ParamSchemaDTO{
+		ID:       s.ID,
+		HexID:    fmt.Sprintf("0x%04x", s.ID),
+		Name:     s.Name,
+		Desc:     s.Desc,
+		Type:     string(s.Type),
+		Unit:     s.Unit,
+		Protocol: s.Protocol,
+		Versions: s.Versions,
+	}
+	if s.Range != nil {
+		res.Min, res.Max = &s.Range.Min, &s.Range.Max
+	}
+	return res
+}
+
+// 文本信息下发请求
+type TextCommand struct {
+	Flag     uint8  `json:"flag" description:"标志位，bit0紧急，bit2终端显示，bit3 TTS播读"`
+	TextType uint8  `json:"textType" description:"文本类型，2019版本，1通知，2服务"`
+	Text     string `json:"text" binding:"required,max=1024"`
+}
+
+// 临时位置跟踪控制请求
+type TrackingCommand struct {
+	Interval uint16 `json:"interval" description:"汇报间隔，秒，为0时停止跟踪"`
+	Duration uint32 `json:"duration" description:"跟踪有效期，秒"`
+}
+
+// 终端控制请求，command为upgrade、connectServer时须填写对应参数
+type ControlCommand struct {
+	Command string                      `json:"command" binding:"required,oneof=upgrade connectServer powerOff reset factoryReset closeDataComm closeWireless"`
+	Upgrade *model.WirelessUpgradeParam `json:"upgrade"`
+	Connect *model.ConnectServerParam   `json:"connect"`
+}
+
+var controlCommandWords = map[string]uint8{
+	"upgrade":       model.ControlWirelessUpgrade,
+	"connectServer": model.ControlConnectServer,
+	"powerOff":      model.ControlPowerOff,
+	"reset":         model.ControlReset,
+	"factoryReset":  model.ControlFactoryReset,
+	"closeDataComm": model.ControlCloseDataComm,
+	"closeWireless": model.ControlCloseWireless,
+}
+
+// 校验并返回命令字
+func (r *ControlCommand) commandWord() (uint8, error) {
+	word, ok := controlCommandWords[r.Command]
+	if !ok {
+		return 0, errors.Wrapf(ErrInvalidCommand, "control command %s is not supported", r.Command)
+	}
+	switch {
+	case word == model.ControlWirelessUpgrade && (r.Upgrade == nil || r.Upgrade.URL == ""):
+		return 0, errors.Wrap(ErrInvalidCommand, "upgrade.url is required")
+	case word == model.ControlConnectServer && r.Connect == nil:
+		return 0, errors.Wrap(ErrInvalidCommand, "connect is required")
+	case word == model.ControlConnectServer && r.Connect.Control > model.ConnectToOriginal:
+		return 0, errors.Wrap(ErrInvalidCommand, "connect.control must be 0 or 1")
+	case word == model.ControlConnectServer && r.Connect.Control == model.ConnectToSpecified && r.Connect.Address == "":
+		return 0, errors.Wrap(ErrInvalidCommand, "connect.address is required")
+	}
+	var fields map[string]string
+	switch word {
+	case model.ControlWirelessUpgrade:
+		u := r.Upgrade
+		fields = map[string]string{
+			"upgrade.url": u.URL, "upgrade.apn": u.APN, "upgrade.dialUser": u.DialUser, "upgrade.dialPassword": u.DialPassword,
+			"upgrade.address": u.Address, "upgrade.manufacturerId": u.ManufacturerID,
+			"upgrade.hardwareVersion": u.HardwareVersion, "upgrade.firmwareVersion": u.FirmwareVersion,
+		}
+	case model.ControlConnectServer:
+		cs := r.Connect
+		fields = map[string]string{
+			"connect.authCode": cs.AuthCode, "connect.apn": cs.APN, "connect.dialUser": cs.DialUser,
+			"connect.dialPassword": cs.DialPassword, "connect.address": cs.Address,
+		}
+	}
+	// 命令参数以分号分隔，参数值中不能包含分号
+	for name, v := range fields {
+		if strings.Contains(v, model.ControlParamSep) {
+			return 0, errors.Wrapf(ErrInvalidCommand, "%s must not contain %q", name, model.ControlParamSep)
+		}
+	}
+	return word, nil
+}
+
+// 单个设备的下发结果
+type CommandResultDTO struct {
+	Phone        string     `json:"phone"`
+	MsgID        string     `json:"msgId,omitempty"`
+	SerialNumber uint16     `json:"serialNumber,omitempty" description:"下发消息的流水号"`
+	Success      bool       `json:"success" description:"是否已下发到终端，等待应答时需收到终端应答"`
+	Error        string     `json:"error,omitempty"`
+	Answer       *AnswerDTO `json:"answer,omitempty" description:"终端应答，仅等待应答时返回"`
+}
+
+// 终端应答
+type AnswerDTO struct {
+	MsgID      string               `json:"msgId" description:"应答消息ID"`
+	Result     *uint8               `json:"result,omitempty" description:"通用应答结果，0成功/确认，1失败，2消息有误，3不支持"`
+	Params     []*ParamItem         `json:"params,omitempty" description:"查询终端参数应答的参数列表"`
+	Properties *DevicePropertiesDTO `json:"properties,omitempty" description:"查询终端属性应答"`
+	Areas      []*TerminalAreaDTO   `json:"areas,omitempty" description:"查询区域或线路数据应答"`
+}
+
+func newAnswerDTO(msg model.JT808Msg) *AnswerDTO {
+	res := &AnswerDTO{MsgID: metrics.MsgIDLabel(msg.GetHeader().MsgID)}
+	switch m := msg.(type) {
+	case *model.Msg0001:
+		res.Result = &m.Result
+	case *model.Msg0104:
+		if m.Parameters != nil {
+			for _, p := range m.Parameters.Params {
+				res.Params = append(res.Params, &ParamItem{ID: p.ParamID, Name: p.ParamName, Value: p.ParamValue})
+			}
+		}
+	case *model.Msg0107:
+		res.Properties = newDevicePropertiesDTO(m.Properties)
+	case *model.Msg0608:
+		res.Areas = make([]*TerminalAreaDTO, 0)
+		for _, a := range m.Areas() {
+			res.Areas = append(res.Areas, &TerminalAreaDTO{Fence: newGeofenceDTO(a.Fence), Attrs: model.AreaAttrNames(a.Attr)})
+		}
+	}
+	return res
+}
+
+// 设备分组
+type GroupDTO struct {
+	Name   string          `json:"name"`
+	Phones []string        `json:"phones" description:"静态成员手机号"`
+	Rules  []*GroupRuleDTO `json:"rules" binding:"dive" description:"动态规则，满足任一规则的设备属于该分组"`
+}
+
+type GroupRuleDTO struct {
+	PlatePrefix    string `json:"platePrefix"`
+	RegionCode     string `json:"regionCode" binding:"omitempty,numeric,max=6"`
+	ManufacturerID string `json:"manufacturerId"`
+}
+
+func newGroupDTO(g *model.DeviceGroup) *GroupDTO {
+	res := &GroupDTO{Name: g.Name, Phones: g.Phones, Rules: make([]*GroupRuleDTO, 0, len(g.Rules))}
+	if res.Phones == nil {
+		res.Phones = []string{}
+	}
+	for _, r := range g.Rules {
+		res.Rules = append(res.Rules, &GroupRuleDTO{PlatePrefix: r.PlatePrefix, RegionCode: r.RegionCode, ManufacturerID: r.ManufacturerID})
+	}
+	return res
+}
+
+func (g *GroupDTO) toModel(name string) *model.DeviceGroup {
+	res := &model.DeviceGroup{Name: name, Phones: g.Phones}
+	for _, r := range g.Rules {
+		res.Rules = append(res.Rules, &model.GroupRule{PlatePrefix: r.PlatePrefix, RegionCode: r.RegionCode, ManufacturerID: r.ManufacturerID})
+	}
+	return res
+}
+
+type GroupDevicesDTO struct {
+	Page     *DevicePageDTO `json:"page"`
+	ByStatus map[string]int `json:"byStatus" description:"分组内各状态的设备数"`
+}
+
+// 分组批量下发请求，按type填写对应的指令内容
+type GroupCommandRequest struct {
+	Type        string              `json:"type" binding:"required,oneof=params queryParams queryProperties text tracking control"`
+	Params      *SetParamsRequest   `json:"params"`
+	QueryParams *QueryParamsRequest `json:"queryParams" description:"为空时查询全部参数"`
+	Text        *TextCommand        `json:"text"`
+	Tracking    *TrackingCommand    `json:"tracking"`
+	Control     *ControlCommand     `json:"control"`
+}
+
+type BatchResultDTO struct {
+	Total     int                 `json:"total"`
+	Succeeded int                 `json:"succeeded"`
+	Failed    int                 `json:"failed"`
+	Results   []*CommandResultDTO `json:"results"`
+}
+
+// 升级包
+type FirmwareDTO struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	UpgradeType    uint8     `json:"upgradeType" description:"升级类型，0终端，12道路运输证IC卡读卡器，52北斗卫星定位模块"`
+	ManufacturerID string    `json:"manufacturerId"`
+	Version        string    `json:"version"`
+	Size           int       `json:"size" description:"字节数"`
+	MD5            string    `json:"md5"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+func newFirmwareDTO(fw *model.Firmware) *FirmwareDTO {
+	return &FirmwareDTO{
+		ID:             fw.ID,
+		Name:           fw.Name,
+		UpgradeType:    fw.UpgradeType,
+		ManufacturerID: fw.ManufacturerID,
+		Version:        fw.Version,
+		Size:           fw.Size,
+		MD5:            fw.MD5,
+		CreatedAt:      fw.CreatedAt,
+	}
+}
+
+// 创建升级任务，升级设备为分组成员与phones的并集
+type CreateUpgradeRequest struct {
+	FirmwareID       string   `json:"firmwareId" binding:"required"`
+	Group            string   `json:"group"`
+	Phones           []string `json:"phones"`
+	BatchSize        int      `json:"batchSize" binding:"min=0,max=1000" description:"每批升级的设备数，默认10"`
+	BatchIntervalSec int      `json:"batchIntervalSec" binding:"min=0" description:"上一批下发完成后间隔多少秒下发下一批"`
+	MaxFailures      int      `json:"maxFailures" binding:"min=0" description:"失败设备数超过该值时自动暂停，0表示不限制"`
+}
+
+type DeviceUpgradeDTO struct {
+	Phone          string    `json:"phone"`
+	State          string    `json:"state" binding:"oneof=pending sending sent succeeded failed canceled"`
+	SentFragments  int       `json:"sentFragments" description:"已确认的分包数"`
+	TotalFragments int       `json:"totalFragments"`
+	Error          string    `json:"error,omitempty"`
+	UpdatedAt      time.Time `json:"updatedAt"`
+}
+
+type UpgradeTaskDTO struct {
+	ID               string              `json:"id"`
+	FirmwareID       string              `json:"firmwareId"`
+	State            string              `json:"state" binding:"oneof=running paused aborted completed"`
+	BatchSize        int                 `json:"batchSize"`
+	BatchIntervalSec int                 `json:"batchIntervalSec"`
+	MaxFailures      int                 `json:"maxFailures"`
+	Operator         string              `json:"operator"`
+	Total            int                 `json:"total"`
+	ByState          map[string]int      `json:"byState" description:"各升级状态的设备数"`
+	Devices          []*DeviceUpgradeDTO `json:"devices,omitempty" description:"仅查询单个任务时返回"`
+	CreatedAt        time.Time           `json:"createdAt"`
+	UpdatedAt        time.Time           `json:"updatedAt"`
+}
+
+func newUpgradeTaskDTO(t *model.UpgradeTask, withDevices bool) *UpgradeTaskDTO {
+	res := &UpgradeTaskDTO{
+		ID:               t.ID,
+		FirmwareID:       t.FirmwareID,
+		State:            string(t.State),
+		BatchSize:        t.BatchSize,
+		BatchIntervalSec: int(t.BatchInterval / time.Second),
+		MaxFailures:      t.MaxFailures,
+		Operator:         t.Operator,
+		Total:            len(t.Devices),
+		ByState:          make(map[string]int),
+		CreatedAt:        t.CreatedAt,
+		UpdatedAt:        t.UpdatedAt,
+	}
+	for st, cnt := range t.CountByState() {
+		res.ByState[string(st)] = cnt
+	}
+	if !withDevices {
+		return res
+	}
+	res.Devices = make([]*DeviceUpgradeDTO, 0, len(t.Devices))
+	for _, d := range t.Devices {
+		res.Devices = append(res.Devices, &DeviceUpgradeDTO{
+			Phone:          d.Phone,
+			State:          string(d.State),
+			SentFragments:  d.SentFragments,
+			TotalFragments: d.TotalFragments,
+			Error:          d.Error,
+			UpdatedAt:      d.UpdatedAt,
+		})
+	}
+	return res
+}
+
+// 平台侧电子围栏，按type填写对应的形状字段
+type GeofenceDTO struct {
+	ID       uint32             `json:"id"`
+	Name     string             `json:"name"`
+	Type     string             `json:"type" binding:"required,oneof=circle rectangle polygon route"`
+	Center   *geo.Point         `json:"center,omitempty" description:"圆心，圆形区域"`
+	Radius   float64            `json:"radius,omitempty" binding:"min=0" description:"半径，米，圆形区域"`
+	Points   []geo.Point        `json:"points,omitempty" description:"矩形为左上、右下两点，多边形为顶点，路线为拐点"`
+	Segments []*RouteSegmentDTO `json:"segments,omitempty" binding:"dive" description:"路线的路段，第i段连接第i和第i+1个拐点"`
+	MaxSpeed float64            `json:"maxSpeed" binding:"min=0" description:"最高速度，km/h，0表示不限速"`
+	Phones   []string           `json:"phones" description:"适用的设备"`
+	Groups   []string           `json:"groups" description:"适用的设备分组"`
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type RouteSegmentDTO struct {
+	Width    float64 `json:"width" binding:"gt=0" description:"路段宽度，米"`
+	MaxSpeed float64 `json:"maxSpeed" binding:"min=0" description:"路段最高速度，km/h，0表示使用路线的maxSpeed"`
+}
+
+func newGeofenceDTO(f *model.Geofence) *GeofenceDTO {
+	res := &GeofenceDTO{
+		ID:        f.ID,
+		Name:      f.Name,
+		Type:      string(f.Type),
+		Center:    f.Center,
+		Radius:    f.Radius,
+		Points:    f.Points,
+		MaxSpeed:  f.MaxSpeed,
+		Phones:    f.Phones,
+		Groups:    f.Groups,
+		CreatedAt: f.CreatedAt,
+		UpdatedAt: f.UpdatedAt,
+	}
+	for _, s := range f.Segments {
+		res.Segments = append(res.Segments, &RouteSegmentDTO{Width: s.Width, MaxSpeed: s.MaxSpeed})
+	}
+	if res.Phones == nil {
+		res.Phones = []string{}
+	}
+	if res.Groups == nil {
+		res.Groups = []string{}
+	}
+	return res
+}
+
+// 转换围栏的坐标系，返回新的DTO
+func (f *GeofenceDTO) convert(from, to coord.Datum) *GeofenceDTO {
+	if from == to {
+		return f
+	}
+	res := *f
+	if f.Center != nil {
+		center := coord.Convert(*f.Center, from, to)
+		res.Center = &center
+	}
+	res.Points = make([]geo.Point, 0, len(f.Points))
+	for _, p := range f.Points {
+		res.Points = append(res.Points, coord.Convert(p, from, to))
+	}
+	return &res
+}
+
+func (f *GeofenceDTO) toModel(id uint32) *model.Geofence {
+	res := &model.Geofence{
+		ID:       id,
+		Name:     f.Name,
+		Type:     model.GeofenceType(f.Type),
+		Center:   f.Center,
+		Radius:   f.Radius,
+		Points:   f.Points,
+		MaxSpeed: f.MaxSpeed,
+		Phones:   f.Phones,
+		Groups:   f.Groups,
+	}
+	for _, s := range f.Segments {
+		res.Segments = append(res.Segments, &model.RouteSegment{Width: s.Width, MaxSpeed: s.MaxSpeed})
+	}
+	return res
+}
+
+// 设备适用的围栏及当前是否在围栏内
+type DeviceGeofenceDTO struct {
+	Fence  *GeofenceDTO `json:"fence"`
+	Inside bool         `json:"inside" description:"是否在区域内，路线表示未偏离路线"`
+}
+
+// 将平台侧围栏下发到终端的附加设置，形状、限速、名称取自围栏
+type AreaSyncRequest struct {
+	Action            string   `json:"action" binding:"omitempty,oneof=update append modify" description:"圆形、矩形区域的设置属性，update清除终端同类区域后设置，append追加，modify修改，默认append"`
+	Attrs             []string `json:"attrs" binding:"dive,oneof=enterAlarmDriver enterAlarmPlatform exitAlarmDriver exitAlarmPlatform noDoorOpen closeComm collectGNSS" description:"区域属性，路线仅支持进出报警"`
+	StartTime         string   `json:"startTime" binding:"omitempty,numeric,len=12" description:"起始时间YYMMDDhhmmss，与endTime同时设置时按时间生效"`
+	EndTime           string   `json:"endTime" binding:"omitempty,numeric,len=12" description:"结束时间YYMMDDhhmmss"`
+	OverspeedDuration uint8    `json:"overspeedDuration" description:"超速持续时间，秒"`
+	NightMaxSpeed     uint16   `json:"nightMaxSpeed" description:"夜间最高速度，km/h，2019版本"`
+}
+
+var areaActions = map[string]uint8{
+	"":       model.AreaActionAppend,
+	"update": model.AreaActionUpdate,
+	"append": model.AreaActionAppend,
+	"modify": model.AreaActionModify,
+}
+
+func (r *AreaSyncRequest) options() (*model.AreaOptions, error) {
+	attr, err := model.ParseAreaAttrs(r.Attrs)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AreaOptions{
+		Action:            areaActions[r.Action],
+		Attr:              attr,
+		StartTime:         r.StartTime,
+		EndTime:           r.EndTime,
+		OverspeedDuration: r.OverspeedDuration,
+		NightMaxSpeed:     r.NightMaxSpeed,
+	}, nil
+}
+
+// 删除或查询终端上的区域
+type AreaIDsRequest struct {
+	Type string   `json:"type" binding:"required,oneof=circle rectangle polygon route"`
+	IDs  []uint32 `json:"ids" binding:"max=125" description:"区域或路线ID，为空表示该类型的全部区域"`
+}
+
+// 终端上的区域
+type TerminalAreaDTO struct {
+	Fence *GeofenceDTO `json:"fence"`
+	Attrs []string     `json:"attrs" description:"区域属性"`
+}
+
+// 行程，时长均为秒
+type TripDTO struct {
+	StartTime     time.Time      `json:"startTime"`
+	EndTime       time.Time      `json:"endTime"`
+	StartLocation geo.Point      `json:"startLocation"`
+	EndLocation   geo.Point      `json:"endLocation"`
+	Distance      float64        `json:"distance" description:"按轨迹点累计的距离，米"`
+	Mileage       *float64       `json:"mileage,omitempty" description:"按里程表(附加信息0x01)计算的里程，km"`
+	DurationSec   int64          `json:"durationSec"`
+	MaxSpeed      float64        `json:"maxSpeed" description:"最高速度，km/h"`
+	AvgSpeed      float64        `json:"avgSpeed" description:"平均速度，km/h"`
+	IdleSec       int64          `json:"idleSec" description:"怠速时长，ACC开且静止"`
+	Stops         []*TripStopDTO `json:"stops"`
+	Points        int            `json:"points" description:"轨迹点数"`
+	Ongoing       bool           `json:"ongoing" description:"是否为进行中的行程"`
+}
+
+type TripStopDTO struct {
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime"`
+	DurationSec int64     `json:"durationSec"`
+	Location    geo.Point `json:"location"`
+	Idling      bool      `json:"idling" description:"停车期间ACC是否一直开启"`
+}
+
+func newTripDTO(t *model.Trip, ongoing bool, datum coord.Datum) *TripDTO {
+	res := &TripDTO{
+		StartTime:     t.StartTime,
+		EndTime:       t.EndTime,
+		StartLocation: toDatum(t.StartLocation, datum),
+		EndLocation:   toDatum(t.EndLocation, datum),
+		Distance:      t.Distance,
+		Mileage:       t.Mileage,
+		DurationSec:   int64(t.Duration() / time.Second),
+		MaxSpeed:      t.MaxSpeed,
+		AvgSpeed:      t.AvgSpeed(),
+		IdleSec:       int64(t.IdleDuration / time.Second),
+		Stops:         make([]*TripStopDTO, 0, len(t.Stops)),
+		Points:        t.Points,
+		Ongoing:       ongoing,
+	}
+	for _, s := range t.Stops {
+		res.Stops = append(res.Stops, &TripStopDTO{
+			StartTime:   s.StartTime,
+			EndTime:     s.EndTime,
+			DurationSec: int64(s.Duration() / time.Second),
+			Location:    toDatum(s.Location, datum),
+			Idling:      s.Idling,
+		})
+	}
+	return res
+}
+
+// 行程外的怠速，ACC开且静止
+type IdleDTO struct {
+	StartTime   time.Time `json:"startTime"`
+	EndTime     time.Time `json:"endTime"`
+	DurationSec int64     `json:"durationSec"`
+	Location    geo.Point `json:"location"`
+}
+
+func newIdleDTO(p *model.IdlePeriod, datum coord.Datum) *IdleDTO {
+	return &IdleDTO{
+		StartTime:   p.StartTime,
+		EndTime:     p.EndTime,
+		DurationSec: int64(p.Duration() / time.Second),
+		Location:    toDatum(p.Location, datum),
+	}
+}
+
+// 单日行程汇总，时长均为秒
+type TripDayDTO struct {
+	Date     string  `json:"date" description:"行程或怠速开始日期，YYYY-MM-DD"`
+	Trips    int     `json:"trips"`
+	Distance float64 `json:"distance" description:"米"`
+	Mileage  float64 `json:"mileage" description:"km，仅统计上报里程的行程"`
+	DriveSec int64   `json:"driveSec"`
+	IdleSec  int64   `json:"idleSec" description:"行程内外的怠速时长"`
+	Stops    int     `json:"stops"`
+	StopSec  int64   `json:"stopSec"`
+	MaxSpeed float64 `json:"maxSpeed"`
+}
+
+func newTripDayDTO(s *model.TripDaySummary) *TripDayDTO {
+	return &TripDayDTO{
+		Date:     s.Date,
+		Trips:    s.Trips,
+		Distance: s.Distance,
+		Mileage:  s.Mileage,
+		DriveSec: int64(s.DriveDuration / time.Second),
+		IdleSec:  int64(s.IdleDuration / time.Second),
+		Stops:    s.Stops,
+		StopSec:  int64(s.StopDuration / time.Second),
+		MaxSpeed: s.MaxSpeed,
+	}
+}
+
+type TripListDTO struct {
+	Trips []*TripDTO    `json:"trips"`
+	Idles []*IdleDTO    `json:"idles" description:"行程外的怠速"`
+	Days  []*TripDayDTO `json:"days"`
+}
+
+// 设备的平台侧驾驶规则及状态
+type DrivingRuleDTO struct {
+	Enable   bool                       `json:"enable" description:"是否开启平台侧判断，见配置server.drivingRule"`
+	Rule     model.DrivingRule          `json:"rule" description:"设备生效的规则"`
+	Override *model.DrivingRuleOverride `json:"override" description:"设备覆盖项，为null表示使用默认规则"`
+	Status   *DrivingStatusDTO          `json:"status" description:"驾驶状态，没有汇报过位置时为null"`
+}
+
+type DrivingStatusDTO struct {
+	AlarmSign            uint32 `json:"alarmSign" description:"平台侧判断的报警标志位"`
+	ContinuousDrivingSec int64  `json:"continuousDrivingSec"`
+	DailyDrivingSec      int64  `json:"dailyDrivingSec"`
+}
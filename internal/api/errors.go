@@ -0,0 +1,92 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/export"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/server"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+// 错误码
+const (
+	CodeInvalidArgument  = "INVALID_ARGUMENT"
+	CodeUnauthenticated  = "UNAUTHENTICATED"
+	CodePermissionDenied = "PERMISSION_DENIED"
+	CodeNotFound         = "NOT_FOUND"
+	CodeDeviceNotFound   = "DEVICE_NOT_FOUND"
+	CodeGroupNotFound    = "GROUP_NOT_FOUND"
+	CodeLocationNotFound = "LOCATION_NOT_FOUND"
+	CodeFirmwareNotFound = "FIRMWARE_NOT_FOUND"
+	CodeUpgradeNotFound  = "UPGRADE_NOT_FOUND"
+	CodeGeofenceNotFound = "GEOFENCE_NOT_FOUND"
+	CodeConflict         = "CONFLICT"
+	CodeDeviceOffline    = "DEVICE_OFFLINE"
+	CodeSendFailed       = "SEND_FAILED"
+	CodeNoAnswer         = "NO_ANSWER"
+	CodeInternal         = "INTERNAL"
+)
+
+// 统一的错误响应
+type ErrorResponse struct {
+	Error *APIError `json:"error"`
+}
+
+type APIError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func abortWithError(c *gin.Context, status int, code, msg string) {
+	c.AbortWithStatusJSON(status, &ErrorResponse{Error: &APIError{Code: code, Message: msg}})
+}
+
+// 按错误类型返回对应的状态码和错误码
+func respondError(c *gin.Context, err error) {
+	status, code := classifyError(err)
+	abortWithError(c, status, code, err.Error())
+}
+
+// 错误类型对应的http状态码和错误码
+func classifyError(err error) (status int, code string) {
+	status, code = http.StatusInternalServerError, CodeInternal
+	switch {
+	case errors.Is(err, ErrInvalidQuery), errors.Is(err, ErrInvalidCommand), errors.Is(err, ErrInvalidParam),
+		errors.Is(err, ErrInvalidFirmware), errors.Is(err, model.ErrInvalidGeofence),
+		errors.Is(err, storage.ErrInvalidCursor), errors.Is(err, storage.ErrInvalidSortBy),
+		errors.Is(err, export.ErrUnknownFormat):
+		status, code = http.StatusBadRequest, CodeInvalidArgument
+	case errors.Is(err, ErrMissingCredential), errors.Is(err, ErrInvalidCredential), errors.Is(err, ErrInvalidRole):
+		status, code = http.StatusUnauthorized, CodeUnauthenticated
+	case errors.Is(err, storage.ErrDeviceNotFound):
+		status, code = http.StatusNotFound, CodeDeviceNotFound
+	case errors.Is(err, storage.ErrGroupNotFound):
+		status, code = http.StatusNotFound, CodeGroupNotFound
+	case errors.Is(err, storage.ErrGisNotFound):
+		status, code = http.StatusNotFound, CodeLocationNotFound
+	case errors.Is(err, storage.ErrFirmwareNotFound):
+		status, code = http.StatusNotFound, CodeFirmwareNotFound
+	case errors.Is(err, storage.ErrUpgradeTaskNotFound):
+		status, code = http.StatusNotFound, CodeUpgradeNotFound
+	case errors.Is(err, storage.ErrGeofenceNotFound):
+		status, code = http.StatusNotFound, CodeGeofenceNotFound
+	case errors.Is(err, ErrFirmwareInUse), errors.Is(err, ErrUpgradeTaskState):
+		status, code = http.StatusConflict, CodeConflict
+	case errors.Is(err, storage.ErrSessionClosed):
+		status, code = http.StatusConflict, CodeDeviceOffline
+	case errors.Is(err, model.ErrOutboundQueueFull), errors.Is(err, model.ErrSessionWriterDone), errors.Is(err, ErrSendFailed):
+		status, code = http.StatusServiceUnavailable, CodeSendFailed
+	case errors.Is(err, server.ErrNoAnswer):
+		status, code = http.StatusGatewayTimeout, CodeNoAnswer
+	}
+	return status, code
+}
+
+// 请求体校验失败
+func badRequest(c *gin.Context, err error) {
+	abortWithError(c, http.StatusBadRequest, CodeInvalidArgument, err.Error())
+}
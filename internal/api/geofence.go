@@ -0,0 +1,235 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/geofence"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+// 平台侧电子围栏的接口
+func (h *v1Handler) geofenceOperations() []*operation {
+	return []*operation{
+		{
+			Method: http.MethodGet, Path: "/geofences", Summary: "查询电子围栏列表", Tag: "geofence", Role: RoleViewer,
+			Query: []*queryParam{coordQueryParam}, Response: []*GeofenceDTO{}, Handler: h.listGeofences,
+		},
+		{
+			Method: http.MethodGet, Path: "/geofences/:id", Summary: "查询电子围栏", Tag: "geofence", Role: RoleViewer,
+			Query: []*queryParam{coordQueryParam}, Response: GeofenceDTO{}, Handler: h.getGeofence,
+		},
+		{
+			Method: http.MethodPut, Path: "/geofences/:id", Summary: "创建或更新电子围栏", Tag: "geofence", Role: RoleAdmin,
+			Query: []*queryParam{coordQueryParam}, Request: GeofenceDTO{}, Response: GeofenceDTO{}, Handler: h.putGeofence,
+		},
+		{
+			Method: http.MethodDelete, Path: "/geofences/:id", Summary: "删除电子围栏", Tag: "geofence", Role: RoleAdmin,
+			Status: http.StatusNoContent, Handler: h.deleteGeofence,
+		},
+		{
+			Method: http.MethodGet, Path: "/devices/:phone/geofences", Summary: "查询设备适用的电子围栏及是否在围栏内",
+			Tag: "geofence", Role: RoleViewer, Query: []*queryParam{coordQueryParam}, Response: []*DeviceGeofenceDTO{},
+			Handler: h.listDeviceGeofences,
+		},
+		{
+			Method: http.MethodPost, Path: "/devices/:phone/geofences/:id/sync", Summary: "将电子围栏下发到终端(0x8600/0x8602/0x8604/0x8606)",
+			Tag: "geofence", Role: RoleOperator, Query: commandQueryParams, Request: AreaSyncRequest{}, Optional: true,
+			Response: CommandResultDTO{}, Handler: h.syncGeofence,
+		},
+		{
+			Method: http.MethodPost, Path: "/devices/:phone/areas/delete", Summary: "删除终端区域或路线(0x8601/0x8603/0x8605/0x8607)",
+			Tag: "geofence", Role: RoleOperator, Query: commandQueryParams, Request: AreaIDsRequest{},
+			Response: CommandResultDTO{}, Handler: h.deleteAreas,
+		},
+		{
+			Method: http.MethodPost, Path: "/devices/:phone/areas/query", Summary: "查询终端区域或路线(0x8608)，仅2019版本",
+			Tag: "geofence", Role: RoleOperator, Query: commandQueryParams, Request: AreaIDsRequest{},
+			Response: CommandResultDTO{}, Handler: h.queryAreas,
+		},
+	}
+}
+
+// 解析路径中的围栏ID，失败时已写入错误响应
+func geofenceID(c *gin.Context) (uint32, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		respondError(c, errors.Wrapf(model.ErrInvalidGeofence, "invalid id %q", c.Param("id")))
+		return 0, false
+	}
+	return uint32(id), true
+}
+
+func (h *v1Handler) listGeofences(c *gin.Context) {
+	datum, ok := datumOf(c)
+	if !ok {
+		return
+	}
+	res := make([]*GeofenceDTO, 0)
+	for _, f := range h.geofenceCache.ListGeofence() {
+		res = append(res, newGeofenceDTO(f).convert(coord.WGS84, datum))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *v1Handler) getGeofence(c *gin.Context) {
+	id, ok := geofenceID(c)
+	if !ok {
+		return
+	}
+	datum, ok := datumOf(c)
+	if !ok {
+		return
+	}
+	f, err := h.geofenceCache.GetGeofence(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newGeofenceDTO(f).convert(coord.WGS84, datum))
+}
+
+func (h *v1Handler) putGeofence(c *gin.Context) {
+	id, ok := geofenceID(c)
+	if !ok {
+		return
+	}
+	datum, ok := datumOf(c)
+	if !ok {
+		return
+	}
+	req := &GeofenceDTO{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	f := req.convert(datum, coord.WGS84).toModel(id)
+	if err := f.Validate(); err != nil {
+		respondError(c, err)
+		return
+	}
+	f.CreatedAt = time.Now()
+	f.UpdatedAt = f.CreatedAt
+	if old, err := h.geofenceCache.GetGeofence(id); err == nil {
+		f.CreatedAt = old.CreatedAt
+	}
+	h.geofenceCache.CacheGeofence(f)
+	c.JSON(http.StatusOK, newGeofenceDTO(f).convert(coord.WGS84, datum))
+}
+
+func (h *v1Handler) deleteGeofence(c *gin.Context) {
+	id, ok := geofenceID(c)
+	if !ok {
+		return
+	}
+	if err := h.geofenceCache.DelGeofence(id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *v1Handler) listDeviceGeofences(c *gin.Context) {
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	datum, ok := datumOf(c)
+	if !ok {
+		return
+	}
+	inside := geofence.Inside(d.Phone)
+	res := make([]*DeviceGeofenceDTO, 0)
+	for _, f := range h.geofenceCache.GeofencesOfDevice(d) {
+		res = append(res, &DeviceGeofenceDTO{Fence: newGeofenceDTO(f).convert(coord.WGS84, datum), Inside: inside[f.ID]})
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *v1Handler) syncGeofence(c *gin.Context) {
+	req := &AreaSyncRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(req); err != nil {
+			badRequest(c, err)
+			return
+		}
+	}
+	opt, err := req.options()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	id, ok := geofenceID(c)
+	if !ok {
+		return
+	}
+	f, err := h.geofenceCache.GetGeofence(id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	gen := func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+		return model.NewAreaSetting(f, opt, func(msgID uint16) *model.MsgHeader {
+			return model.GenMsgHeader(d, msgID, serialNumber)
+		})
+	}
+	// 按设备的协议版本提前编码，发现围栏无法转换或超过单包长度的情况
+	msg, err := gen(d, 0)
+	if err == nil {
+		_, err = msg.Encode()
+	}
+	if err != nil {
+		respondError(c, errors.Wrap(ErrInvalidCommand, err.Error()))
+		return
+	}
+	h.sendCommand(c, d, gen)
+}
+
+func (h *v1Handler) deleteAreas(c *gin.Context) {
+	req := &AreaIDsRequest{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	h.sendCommand(c, d, func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+		return model.NewAreaDelete(model.GeofenceType(req.Type), req.IDs, func(msgID uint16) *model.MsgHeader { // 类型已校验
+			return model.GenMsgHeader(d, msgID, serialNumber)
+		})
+	})
+}
+
+func (h *v1Handler) queryAreas(c *gin.Context) {
+	req := &AreaIDsRequest{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	if d.VersionDesc != model.Version2019 {
+		respondError(c, errors.Wrap(ErrInvalidCommand, "query areas requires 2019 version"))
+		return
+	}
+	h.sendCommand(c, d, func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+		return &model.Msg8608{
+			Header:   model.GenMsgHeader(d, 0x8608, serialNumber),
+			AreaType: model.AreaTypeOf(model.GeofenceType(req.Type)),
+			IDs:      req.IDs,
+		}, nil
+	})
+}
@@ -0,0 +1,86 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func TestV1_Geofence(t *testing.T) {
+	router, _ := newTestRouter(t)
+	phone := "13900000042"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, Status: model.DeviceStatusOffline})
+	t.Cleanup(func() { storage.GetDeviceCache().DelDeviceByPhone(phone) })
+
+	circle := []byte(`{"name":"depot","type":"circle","center":{"lat":22.54,"lng":114.05},"radius":200,"phones":["` + phone + `"]}`)
+	w := doRequest(t, router, http.MethodPut, "/api/v1/geofences/42", "viewer-key", circle)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doRequest(t, router, http.MethodPut, "/api/v1/geofences/abc", "admin-key", circle)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doRequest(t, router, http.MethodPut, "/api/v1/geofences/42", "admin-key", []byte(`{"type":"circle","radius":200}`))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doRequest(t, router, http.MethodPut, "/api/v1/geofences/42", "admin-key", circle)
+	require.Equal(t, http.StatusOK, w.Code)
+	t.Cleanup(func() { storage.GetGeofenceCache().DelGeofence(42) })
+	fence := &GeofenceDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), fence))
+	assert.Equal(t, uint32(42), fence.ID)
+	assert.Equal(t, []string{}, fence.Groups)
+
+	w = doRequest(t, router, http.MethodGet, "/api/v1/devices/"+phone+"/geofences", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res := []*DeviceGeofenceDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.Len(t, res, 1)
+	assert.Equal(t, "depot", res[0].Fence.Name)
+	assert.False(t, res[0].Inside)
+
+	w = doRequest(t, router, http.MethodDelete, "/api/v1/geofences/42", "admin-key", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = doRequest(t, router, http.MethodGet, "/api/v1/geofences/42", "viewer-key", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), CodeGeofenceNotFound)
+}
+
+func TestV1_AreaSync(t *testing.T) {
+	router, _ := newTestRouter(t)
+	phone := "13900000043"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, VersionDesc: model.Version2013, Status: model.DeviceStatusOffline})
+	t.Cleanup(func() { storage.GetDeviceCache().DelDeviceByPhone(phone) })
+	polygon := &model.Geofence{ID: 43, Type: model.GeofencePolygon, Phones: []string{phone}}
+	for i := 0; i < 130; i++ {
+		polygon.Points = append(polygon.Points, geo.Point{Lat: float64(i) / 1000, Lng: float64(i*i) / 1000})
+	}
+	storage.GetGeofenceCache().CacheGeofence(polygon)
+	t.Cleanup(func() { storage.GetGeofenceCache().DelGeofence(43) })
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "fence not found", path: "/geofences/44/sync", wantStatus: http.StatusNotFound, wantCode: CodeGeofenceNotFound},
+		{name: "invalid attr", path: "/geofences/43/sync", body: `{"attrs":["south"]}`, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidArgument},
+		{name: "body too long", path: "/geofences/43/sync", wantStatus: http.StatusBadRequest, wantCode: CodeInvalidArgument},
+		{name: "invalid type", path: "/areas/delete", body: `{"type":"line"}`, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidArgument},
+		{name: "device offline", path: "/areas/delete", body: `{"type":"polygon","ids":[43]}`, wantStatus: http.StatusConflict, wantCode: CodeDeviceOffline},
+		{name: "query requires 2019", path: "/areas/query", body: `{"type":"polygon"}`, wantStatus: http.StatusBadRequest, wantCode: CodeInvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(t, router, http.MethodPost, "/api/v1/devices/"+phone+tt.path, "admin-key", []byte(tt.body))
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Contains(t, w.Body.String(), tt.wantCode)
+		})
+	}
+}
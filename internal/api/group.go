@@ -0,0 +1,211 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/server"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+// 批量下发的并发数
+const batchSendConcurrency = 32
+
+// 等待终端应答的超时
+const (
+	defaultAnswerTimeout = 10 * time.Second
+	maxAnswerTimeout     = 60 * time.Second
+)
+
+var (
+	ErrInvalidCommand = errors.New("Invalid command")
+	ErrSendFailed     = errors.New("Fail to send command")
+)
+
+// 分组批量下发的指令类型
+const (
+	CommandParams          = "params"          // 设置终端参数 0x8103
+	CommandQueryParams     = "queryParams"     // 查询终端参数 0x8104，指定参数时为0x8106
+	CommandQueryProperties = "queryProperties" // 查询终端属性 0x8107
+	CommandText            = "text"            // 文本信息下发 0x8300
+	CommandTracking        = "tracking"        // 临时位置跟踪控制 0x8202
+	CommandControl         = "control"         // 终端控制 0x8105
+)
+
+// 根据序列号生成发往设备的消息，指令不适用于该设备时返回错误
+type msgBuilder func(d *model.Device, serialNumber uint16) (model.JT808Msg, error)
+
+// 根据指令生成消息构造方法
+func (cmd *GroupCommandRequest) builder() (msgBuilder, error) {
+	switch cmd.Type {
+	case CommandParams:
+		if cmd.Params == nil {
+			return nil, errors.Wrap(ErrInvalidCommand, "params is required")
+		}
+		if err := cmd.Params.validate(); err != nil {
+			return nil, err
+		}
+		return func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+			params, err := cmd.Params.toDeviceParams(d) // 参数已校验，此处按设备的协议版本校验
+			if err != nil {
+				return nil, err
+			}
+			return &model.Msg8103{Header: model.GenMsgHeader(d, 0x8103, serialNumber), Parameters: params}, nil
+		}, nil
+	case CommandQueryParams:
+		ids, err := cmd.QueryParams.paramIDs()
+		if err != nil {
+			return nil, err
+		}
+		return queryParamsBuilder(ids), nil
+	case CommandQueryProperties:
+		return func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+			return &model.Msg8107{Header: model.GenMsgHeader(d, 0x8107, serialNumber)}, nil
+		}, nil
+	case CommandText:
+		if cmd.Text == nil {
+			return nil, errors.Wrap(ErrInvalidCommand, "text is required")
+		}
+		return func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+			return &model.Msg8300{
+				Header:   model.GenMsgHeader(d, 0x8300, serialNumber),
+				Flag:     cmd.Text.Flag,
+				TextType: cmd.Text.TextType,
+				Text:     cmd.Text.Text,
+			}, nil
+		}, nil
+	case CommandTracking:
+		if cmd.Tracking == nil {
+			return nil, errors.Wrap(ErrInvalidCommand, "tracking is required")
+		}
+		return func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+			return &model.Msg8202{
+				Header:   model.GenMsgHeader(d, 0x8202, serialNumber),
+				Interval: cmd.Tracking.Interval,
+				Duration: cmd.Tracking.Duration,
+			}, nil
+		}, nil
+	case CommandControl:
+		if cmd.Control == nil {
+			return nil, errors.Wrap(ErrInvalidCommand, "control is required")
+		}
+		return controlBuilder(cmd.Control)
+	default:
+		return nil, errors.Wrapf(ErrInvalidCommand, "type=%s", cmd.Type)
+	}
+}
+
+// 查询终端参数，ids为空时查询全部参数
+func queryParamsBuilder(ids []uint32) msgBuilder {
+	if len(ids) == 0 {
+		return func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+			return &model.Msg8104{Header: model.GenMsgHeader(d, 0x8104, serialNumber)}, nil
+		}
+	}
+	return func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+		return &model.Msg8106{Header: model.GenMsgHeader(d, 0x8106, serialNumber), ParamCnt: uint8(len(ids)), ParamIDs: ids}, nil
+	}
+}
+
+// 终端控制，参数已按命令字校验
+func controlBuilder(cmd *ControlCommand) (msgBuilder, error) {
+	word, err := cmd.commandWord()
+	if err != nil {
+		return nil, err
+	}
+	return func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+		m := &model.Msg8105{Header: model.GenMsgHeader(d, 0x8105, serialNumber), Command: word}
+		switch word {
+		case model.ControlWirelessUpgrade:
+			m.Upgrade = cmd.Upgrade
+		case model.ControlConnectServer:
+			m.Connect = cmd.Connect
+		}
+		return m, nil
+	}, nil
+}
+
+// 等待终端应答的超时，timeoutSec<=0时使用默认值，超过最大值时取最大值
+func answerTimeout(timeoutSec int) time.Duration {
+	timeout := defaultAnswerTimeout
+	if timeoutSec > 0 {
+		timeout = time.Duration(timeoutSec) * time.Second
+	}
+	if timeout > maxAnswerTimeout {
+		timeout = maxAnswerTimeout
+	}
+	return timeout
+}
+
+// 向单个设备下发消息。设备离线返回ErrSessionClosed，其他下发失败返回ErrSendFailed。
+// wait为true时等待终端应答直到ctx结束，已下发但未收到应答视为失败，返回server.ErrNoAnswer
+func sendOne(ctx context.Context, who *caller, serv *server.TCPServer, audit *auditor, d *model.Device, build msgBuilder, wait bool) (*CommandResultDTO, error) {
+	res := &CommandResultDTO{Phone: d.Phone}
+	session, err := storage.GetSession(d.SessionID)
+	if err != nil {
+		res.Error = err.Error()
+		return res, err
+	}
+	msg, err := build(d, session.GetNextSerialNum())
+	if err != nil {
+		res.Error = err.Error()
+		return res, err
+	}
+	h := msg.GetHeader()
+	res.MsgID = metrics.MsgIDLabel(h.MsgID)
+	res.SerialNumber = h.SerialNumber
+	var answer model.JT808Msg
+	if wait {
+		answer, err = audit.request(ctx, who, serv, session.ID, msg)
+	} else {
+		err = audit.send(ctx, who, serv, session.ID, msg)
+	}
+	if err != nil {
+		res.Error = err.Error()
+		switch {
+		case errors.Is(err, server.ErrNoAnswer), errors.Is(err, storage.ErrSessionClosed):
+			return res, err
+		default:
+			return res, errors.Wrap(ErrSendFailed, err.Error())
+		}
+	}
+	res.Success = true
+	if answer != nil {
+		res.Answer = newAnswerDTO(answer)
+	}
+	return res, nil
+}
+
+// 并发下发，结果与devices顺序一致
+func batchSend(ctx context.Context, who *caller, serv *server.TCPServer, audit *auditor, devices []*model.Device, build msgBuilder) *BatchResultDTO {
+	results := make([]*CommandResultDTO, len(devices))
+	sem := make(chan struct{}, batchSendConcurrency)
+	var wg sync.WaitGroup
+	for i, d := range devices {
+		i, d := i, d
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			results[i], _ = sendOne(ctx, who, serv, audit, d, build, false)
+		}()
+	}
+	wg.Wait()
+
+	res := &BatchResultDTO{Total: len(results), Results: results}
+	for _, r := range results {
+		if r.Success {
+			res.Succeeded++
+		}
+	}
+	res.Failed = res.Total - res.Succeeded
+	return res
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func TestV1_GroupCommandAllMembers(t *testing.T) {
+	router, _ := newTestRouter(t)
+	n := storage.MaxPageLimit + 5 // 超过单页上限的成员数
+	cache := storage.GetDeviceCache()
+	for i := 0; i < n; i++ {
+		cache.CacheDevice(&model.Device{
+			Phone:  fmt.Sprintf("1390035%04d", i),
+			Plate:  fmt.Sprintf("测G%05d", i),
+			Status: model.DeviceStatusOffline,
+		})
+	}
+	storage.GetGroupCache().CacheGroup(&model.DeviceGroup{Name: "big-fleet", Rules: []*model.GroupRule{{PlatePrefix: "测G"}}})
+	t.Cleanup(func() {
+		for i := 0; i < n; i++ {
+			cache.DelDeviceByPhone(fmt.Sprintf("1390035%04d", i))
+		}
+		_ = storage.GetGroupCache().DelGroup("big-fleet")
+	})
+
+	body := []byte(`{"type":"text","text":{"text":"hello"}}`)
+	w := doRequest(t, router, http.MethodPost, "/api/v1/groups/big-fleet/commands", "admin-key", body)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	res := &BatchResultDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Equal(t, n, res.Total)
+	assert.Equal(t, n, res.Failed) // 设备均离线
+	assert.Equal(t, "13900350000", res.Results[0].Phone)
+	assert.False(t, res.Results[0].Success)
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"net"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	jt808v1 "github.com/fakeyanss/jt808-server-go/api/jt808/v1"
+	"github.com/fakeyanss/jt808-server-go/internal/event"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/server"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+// gRPC服务，接口定义见 api/jt808/v1/device_service.proto
+const grpcMethod = "GRPC" // 审计日志中的method
+
+// 各方法要求的最低角色
+var grpcMethodRoles = map[string]Role{
+	jt808v1.DeviceService_ListDevices_FullMethodName: RoleViewer,
+	jt808v1.DeviceService_GetDevice_FullMethodName:   RoleViewer,
+	jt808v1.DeviceService_SendCommand_FullMethodName: RoleOperator,
+	jt808v1.DeviceService_Subscribe_FullMethodName:   RoleViewer,
+}
+
+// 与/api/v1共用存储和下发逻辑
+type grpcService struct {
+	jt808v1.UnimplementedDeviceServiceServer
+
+	serv        *server.TCPServer
+	audit       *auditor
+	deviceCache *storage.DeviceCache
+	groupCache  *storage.GroupCache
+}
+
+func newGRPCServer(serv *server.TCPServer, a *Authenticator, audit *auditor, certReloader *server.CertReloader) *grpc.Server {
+	opts := []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(a.unaryInterceptor),
+		grpc.ChainStreamInterceptor(a.streamInterceptor),
+	}
+	if certReloader != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(certReloader.APITLSConfig())))
+	}
+	gs := grpc.NewServer(opts...)
+	jt808v1.RegisterDeviceServiceServer(gs, &grpcService{
+		serv:        serv,
+		audit:       audit,
+		deviceCache: storage.GetDeviceCache(),
+		groupCache:  storage.GetGroupCache(),
+	})
+	return gs
+}
+
+func (s *grpcService) ListDevices(ctx context.Context, req *jt808v1.ListDevicesRequest) (*jt808v1.DevicePage, error) {
+	q, err := buildDeviceQuery(grpcPrincipalOf(ctx), listDevicesParam(req))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	page, err := s.deviceCache.QueryDevice(q)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toPBDevicePage(newDevicePageDTO(page)), nil
+}
+
+// 查询设备并校验权限
+func (s *grpcService) device(ctx context.Context, phone string) (*model.Device, error) {
+	d, err := s.deviceCache.GetDeviceByPhone(phone)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if !grpcPrincipalOf(ctx).CanAccessDevice(d) {
+		return nil, status.Error(codes.PermissionDenied, "Permission denied for device "+phone)
+	}
+	return d, nil
+}
+
+func (s *grpcService) GetDevice(ctx context.Context, req *jt808v1.GetDeviceRequest) (*jt808v1.Device, error) {
+	d, err := s.device(ctx, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	return toPBDevice(newDeviceDTO(d)), nil
+}
+
+func (s *grpcService) SendCommand(ctx context.Context, req *jt808v1.SendCommandRequest) (*jt808v1.CommandResult, error) {
+	if req.Command == nil {
+		return nil, grpcError(errors.Wrap(ErrInvalidCommand, "command is required"))
+	}
+	cmd, err := fromPBCommand(req.Command)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	// 与http接口的ShouldBindJSON使用相同的binding校验规则
+	if err := binding.Validator.ValidateStruct(cmd); err != nil {
+		return nil, status.Error(codes.InvalidArgument, CodeInvalidArgument+": "+err.Error())
+	}
+	build, err := cmd.builder()
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	d, err := s.device(ctx, req.Phone)
+	if err != nil {
+		return nil, err
+	}
+	if req.WaitAnswer {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, answerTimeout(int(req.TimeoutSec)))
+		defer cancel()
+	}
+	res, err := sendOne(ctx, grpcCallerOf(ctx), s.serv, s.audit, d, build, req.WaitAnswer)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return toPBCommandResult(res), nil
+}
+
+func (s *grpcService) Subscribe(req *jt808v1.SubscribeRequest, stream jt808v1.DeviceService_SubscribeServer) error {
+	ctx := stream.Context()
+	p := grpcPrincipalOf(ctx)
+	if req.Group != "" {
+		if !p.CanAccessGroup(req.Group) {
+			return status.Error(codes.PermissionDenied, "Permission denied for group "+req.Group)
+		}
+		if _, err := s.groupCache.GetGroup(req.Group); err != nil {
+			return grpcError(err)
+		}
+	}
+	datum, err := parseDatum(req.Coord)
+	if err != nil {
+		return status.Errorf(codes.InvalidArgument, "Invalid coord %q", req.Coord)
+	}
+	types := make(map[event.Type]bool)
+	for _, t := range req.Types {
+		switch event.Type(t) {
+		case event.TypeLocation, event.TypeAlarm, event.TypeStatus, event.TypeGeofence:
+			types[event.Type(t)] = true
+		default:
+			return status.Errorf(codes.InvalidArgument, "Invalid event type %q", t)
+		}
+	}
+	phones := make(map[string]bool)
+	for _, phone := range req.Phones {
+		phones[phone] = true
+	}
+
+	// 类型和手机号在发布时过滤，设备权限和分组需要查缓存，在本协程中过滤
+	sub := event.Subscribe(func(e *event.Event) bool {
+		return (len(types) == 0 || types[e.Type]) && (len(phones) == 0 || phones[e.Phone])
+	}, 0)
+	defer sub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e := <-sub.C:
+			if !s.visible(p, req.Group, e) {
+				continue
+			}
+			if err := stream.Send(toPBEvent(e, datum)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// 判断调用方能否收到该事件
+func (s *grpcService) visible(p *Principal, group string, e *event.Event) bool {
+	d, err := s.deviceCache.GetDeviceByPhone(e.Phone)
+	if err != nil {
+		return false // 设备已注销
+	}
+	if !p.CanAccessDevice(d) {
+		return false
+	}
+	if group == "" {
+		return true
+	}
+	g, err := s.groupCache.GetGroup(group)
+	return err == nil && g.Match(d)
+}
+
+type grpcPrincipalCtxKey struct{}
+
+// 从metadata解析调用方，metadata的key与http header相同(不区分大小写)
+func (a *Authenticator) grpcAuthenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	p, err := a.authenticate(func(key string) string {
+		if v := md.Get(key); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	if role, ok := grpcMethodRoles[fullMethod]; !ok || !p.HasRole(role) {
+		return nil, status.Errorf(codes.PermissionDenied, "Permission denied, require role %s", role)
+	}
+	return context.WithValue(ctx, grpcPrincipalCtxKey{}, p), nil
+}
+
+func (a *Authenticator) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.grpcAuthenticate(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (a *Authenticator) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.grpcAuthenticate(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authedStream{ServerStream: ss, ctx: ctx})
+}
+
+// 携带调用方的ServerStream
+type authedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authedStream) Context() context.Context {
+	return s.ctx
+}
+
+// 获取当前调用的调用方，未经过鉴权拦截器时返回无权限的调用方
+func grpcPrincipalOf(ctx context.Context) *Principal {
+	if p, ok := ctx.Value(grpcPrincipalCtxKey{}).(*Principal); ok {
+		return p
+	}
+	return &Principal{Name: anonymousPrincipal}
+}
+
+func grpcCallerOf(ctx context.Context) *caller {
+	who := &caller{principal: grpcPrincipalOf(ctx), method: grpcMethod}
+	who.path, _ = grpc.Method(ctx)
+	if pr, ok := peer.FromContext(ctx); ok {
+		who.clientIP = pr.Addr.String()
+		if host, _, err := net.SplitHostPort(who.clientIP); err == nil {
+			who.clientIP = host
+		}
+	}
+	return who
+}
+
+// 错误码对应的gRPC状态码
+var grpcCodes = map[string]codes.Code{
+	CodeInvalidArgument:  codes.InvalidArgument,
+	CodeUnauthenticated:  codes.Unauthenticated,
+	CodePermissionDenied: codes.PermissionDenied,
+	CodeNotFound:         codes.NotFound,
+	CodeDeviceNotFound:   codes.NotFound,
+	CodeGroupNotFound:    codes.NotFound,
+	CodeLocationNotFound: codes.NotFound,
+	CodeFirmwareNotFound: codes.NotFound,
+	CodeUpgradeNotFound:  codes.NotFound,
+	CodeGeofenceNotFound: codes.NotFound,
+	CodeConflict:         codes.FailedPrecondition,
+	CodeDeviceOffline:    codes.FailedPrecondition,
+	CodeSendFailed:       codes.Unavailable,
+	CodeNoAnswer:         codes.DeadlineExceeded,
+	CodeInternal:         codes.Internal,
+}
+
+// 按错误类型转换为gRPC状态，与http接口的错误码一致
+func grpcError(err error) error {
+	_, code := classifyError(err)
+	return status.Error(grpcCodes[code], code+": "+err.Error())
+}
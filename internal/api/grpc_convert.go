@@ -0,0 +1,350 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	jt808v1 "github.com/fakeyanss/jt808-server-go/api/jt808/v1"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/region"
+	"github.com/fakeyanss/jt808-server-go/internal/event"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+// protobuf消息与http接口DTO之间的转换，gRPC和http共用DTO上的校验和下发逻辑
+
+// 按http query参数名取ListDevicesRequest的字段
+func listDevicesParam(r *jt808v1.ListDevicesRequest) func(key string) string {
+	return func(key string) string {
+		switch key {
+		case "status":
+			return r.Status
+		case "version":
+			return r.Version
+		case "plate":
+			return r.Plate
+		case "phone":
+			return r.Phone
+		case "from":
+			return r.From
+		case "to":
+			return r.To
+		case "region":
+			return r.Region
+		case "group":
+			return r.Group
+		case "sort":
+			return r.Sort
+		case "order":
+			return r.Order
+		case "cursor":
+			return r.Cursor
+		case "limit":
+			if r.Limit != 0 {
+				return strconv.Itoa(int(r.Limit))
+			}
+		}
+		return ""
+	}
+}
+
+// 零值时间不返回
+func pbTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// 参数值转为protobuf的Value，数值统一为number
+func pbValue(v any) *structpb.Value {
+	switch n := v.(type) {
+	case uint8:
+		return structpb.NewNumberValue(float64(n))
+	case uint16:
+		return structpb.NewNumberValue(float64(n))
+	}
+	res, err := structpb.NewValue(v)
+	if err != nil {
+		return structpb.NewStringValue(fmt.Sprint(v))
+	}
+	return res
+}
+
+// 校验protobuf中的无符号整数不超过协议字段的取值范围
+func checkUint(field string, v uint32, max uint32) error {
+	if v > max {
+		return errors.Wrapf(ErrInvalidCommand, "%s must be less than or equal to %d", field, max)
+	}
+	return nil
+}
+
+func toPBDevice(d *DeviceDTO) *jt808v1.Device {
+	return &jt808v1.Device{
+		Phone:           d.Phone,
+		Id:              d.ID,
+		Plate:           d.Plate,
+		PlateColor:      uint32(d.PlateColor),
+		Status:          d.Status,
+		Version:         d.Version,
+		ProtocolVersion: uint32(d.ProtocolVersion),
+		RegionCode:      d.RegionCode,
+		ManufacturerId:  d.ManufacturerID,
+		DeviceMode:      d.DeviceMode,
+		Imei:            d.IMEI,
+		SoftwareVersion: d.SoftwareVersion,
+		TransProto:      d.TransProto,
+		KeepaliveSec:    d.KeepaliveSec,
+		LastComTime:     pbTime(d.LastComTime),
+		Properties:      toPBDeviceProperties(d.Properties),
+	}
+}
+
+func toPBDeviceProperties(p *DevicePropertiesDTO) *jt808v1.DeviceProperties {
+	if p == nil {
+		return nil
+	}
+	return &jt808v1.DeviceProperties{
+		TerminalTypes:   p.TerminalTypes,
+		ManufacturerId:  p.ManufacturerID,
+		TerminalModel:   p.TerminalModel,
+		TerminalId:      p.TerminalID,
+		Iccid:           p.ICCID,
+		HardwareVersion: p.HardwareVersion,
+		FirmwareVersion: p.FirmwareVersion,
+		Gnss:            p.GNSS,
+		Comm:            p.Comm,
+		UpdatedAt:       pbTime(p.UpdatedAt),
+	}
+}
+
+func toPBDevicePage(page *DevicePageDTO) *jt808v1.DevicePage {
+	res := &jt808v1.DevicePage{
+		Devices:    make([]*jt808v1.Device, 0, len(page.Devices)),
+		Total:      int32(page.Total),
+		NextCursor: page.NextCursor,
+	}
+	for _, d := range page.Devices {
+		res.Devices = append(res.Devices, toPBDevice(d))
+	}
+	return res
+}
+
+// 转换为分组下发使用的指令请求，整数超出协议字段范围时返回ErrInvalidCommand
+func fromPBCommand(c *jt808v1.Command) (*GroupCommandRequest, error) {
+	res := &GroupCommandRequest{Type: c.Type}
+	if c.Params != nil {
+		res.Params = &SetParamsRequest{}
+		for _, p := range c.Params.Params {
+			item := &ParamItem{ID: p.Id, Name: p.Name}
+			if p.Value != nil {
+				item.Value = p.Value.AsInterface()
+			}
+			res.Params.Params = append(res.Params.Params, item)
+		}
+	}
+	if c.QueryParams != nil {
+		res.QueryParams = &QueryParamsRequest{IDs: c.QueryParams.Ids, Names: c.QueryParams.Names}
+	}
+	if t := c.Text; t != nil {
+		if err := checkUint("text.flag", t.Flag, math.MaxUint8); err != nil {
+			return nil, err
+		}
+		if err := checkUint("text.textType", t.TextType, math.MaxUint8); err != nil {
+			return nil, err
+		}
+		res.Text = &TextCommand{Flag: uint8(t.Flag), TextType: uint8(t.TextType), Text: t.Text}
+	}
+	if t := c.Tracking; t != nil {
+		if err := checkUint("tracking.interval", t.Interval, math.MaxUint16); err != nil {
+			return nil, err
+		}
+		res.Tracking = &TrackingCommand{Interval: uint16(t.Interval), Duration: t.Duration}
+	}
+	if ctl := c.Control; ctl != nil {
+		var err error
+		res.Control = &ControlCommand{Command: ctl.Command}
+		if res.Control.Upgrade, err = fromPBUpgradeParam(ctl.Upgrade); err != nil {
+			return nil, err
+		}
+		if res.Control.Connect, err = fromPBConnectParam(ctl.Connect); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+func fromPBUpgradeParam(p *jt808v1.WirelessUpgradeParam) (*model.WirelessUpgradeParam, error) {
+	if p == nil {
+		return nil, nil
+	}
+	for field, v := range map[string]uint32{
+		"control.upgrade.tcpPort":   p.TcpPort,
+		"control.upgrade.udpPort":   p.UdpPort,
+		"control.upgrade.timeLimit": p.TimeLimit,
+	} {
+		if err := checkUint(field, v, math.MaxUint16); err != nil {
+			return nil, err
+		}
+	}
+	return &model.WirelessUpgradeParam{
+		URL:             p.Url,
+		APN:             p.Apn,
+		DialUser:        p.DialUser,
+		DialPassword:    p.DialPassword,
+		Address:         p.Address,
+		TCPPort:         uint16(p.TcpPort),
+		UDPPort:         uint16(p.UdpPort),
+		ManufacturerID:  p.ManufacturerId,
+		HardwareVersion: p.HardwareVersion,
+		FirmwareVersion: p.FirmwareVersion,
+		TimeLimit:       uint16(p.TimeLimit),
+	}, nil
+}
+
+func fromPBConnectParam(p *jt808v1.ConnectServerParam) (*model.ConnectServerParam, error) {
+	if p == nil {
+		return nil, nil
+	}
+	if err := checkUint("control.connect.control", p.Control, math.MaxUint8); err != nil {
+		return nil, err
+	}
+	for field, v := range map[string]uint32{
+		"control.connect.tcpPort":   p.TcpPort,
+		"control.connect.udpPort":   p.UdpPort,
+		"control.connect.timeLimit": p.TimeLimit,
+	} {
+		if err := checkUint(field, v, math.MaxUint16); err != nil {
+			return nil, err
+		}
+	}
+	return &model.ConnectServerParam{
+		Control:      uint8(p.Control),
+		AuthCode:     p.AuthCode,
+		APN:          p.Apn,
+		DialUser:     p.DialUser,
+		DialPassword: p.DialPassword,
+		Address:      p.Address,
+		TCPPort:      uint16(p.TcpPort),
+		UDPPort:      uint16(p.UdpPort),
+		TimeLimit:    uint16(p.TimeLimit),
+	}, nil
+}
+
+func toPBCommandResult(r *CommandResultDTO) *jt808v1.CommandResult {
+	return &jt808v1.CommandResult{
+		Phone:        r.Phone,
+		MsgId:        r.MsgID,
+		SerialNumber: uint32(r.SerialNumber),
+		Success:      r.Success,
+		Error:        r.Error,
+		Answer:       toPBAnswer(r.Answer),
+	}
+}
+
+func toPBAnswer(a *AnswerDTO) *jt808v1.Answer {
+	if a == nil {
+		return nil
+	}
+	res := &jt808v1.Answer{MsgId: a.MsgID, Properties: toPBDeviceProperties(a.Properties)}
+	if a.Result != nil {
+		result := uint32(*a.Result)
+		res.Result = &result
+	}
+	for _, p := range a.Params {
+		res.Params = append(res.Params, &jt808v1.ParamItem{Id: p.ID, Name: p.Name, Value: pbValue(p.Value)})
+	}
+	for _, area := range a.Areas {
+		res.Areas = append(res.Areas, &jt808v1.TerminalArea{Fence: toPBGeofence(area.Fence), Attrs: area.Attrs})
+	}
+	return res
+}
+
+func toPBPoint(p geo.Point) *jt808v1.Point {
+	return &jt808v1.Point{Lat: p.Lat, Lng: p.Lng}
+}
+
+func toPBGeofence(f *GeofenceDTO) *jt808v1.Geofence {
+	if f == nil {
+		return nil
+	}
+	res := &jt808v1.Geofence{
+		Id:        f.ID,
+		Name:      f.Name,
+		Type:      f.Type,
+		Radius:    f.Radius,
+		MaxSpeed:  f.MaxSpeed,
+		Phones:    f.Phones,
+		Groups:    f.Groups,
+		CreatedAt: pbTime(f.CreatedAt),
+		UpdatedAt: pbTime(f.UpdatedAt),
+	}
+	if f.Center != nil {
+		res.Center = toPBPoint(*f.Center)
+	}
+	for _, p := range f.Points {
+		res.Points = append(res.Points, toPBPoint(p))
+	}
+	for _, s := range f.Segments {
+		res.Segments = append(res.Segments, &jt808v1.RouteSegment{Width: s.Width, MaxSpeed: s.MaxSpeed})
+	}
+	return res
+}
+
+func toPBRegion(r *region.AdministrativeRegion) *jt808v1.Region {
+	if r == nil {
+		return nil
+	}
+	return &jt808v1.Region{
+		Code:     r.Code,
+		Name:     r.Name,
+		Province: r.Province,
+		City:     r.City,
+		District: r.District,
+		FullName: r.FullName,
+	}
+}
+
+func toPBLocation(l *LocationDTO) *jt808v1.Location {
+	return &jt808v1.Location{
+		Phone:     l.Phone,
+		Time:      pbTime(l.Time),
+		Latitude:  l.Latitude,
+		Longitude: l.Longitude,
+		Altitude:  uint32(l.Altitude),
+		Speed:     l.Speed,
+		Direction: uint32(l.Direction),
+		Acc:       l.ACC,
+		Located:   l.Located,
+		Driving:   l.Driving,
+		Coord:     l.Coord,
+		Region:    toPBRegion(l.Region),
+		Quality:   l.Quality,
+	}
+}
+
+func toPBEvent(e *event.Event, datum coord.Datum) *jt808v1.Event {
+	res := &jt808v1.Event{
+		Type:       string(e.Type),
+		Phone:      e.Phone,
+		Time:       pbTime(e.Time),
+		AlarmSign:  e.AlarmSign,
+		Server:     e.Server,
+		Status:     e.Status,
+		PrevStatus: e.PrevStatus,
+	}
+	if e.Geo != nil {
+		res.Location = toPBLocation(newLocationDTO(e.Geo, datum))
+	}
+	if e.Fence != nil {
+		res.Fence = &jt808v1.FenceInfo{Id: e.Fence.ID, Name: e.Fence.Name, Action: string(e.Fence.Action), MaxSpeed: e.Fence.MaxSpeed}
+	}
+	return res
+}
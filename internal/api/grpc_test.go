@@ -0,0 +1,210 @@
+package api
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	jt808v1 "github.com/fakeyanss/jt808-server-go/api/jt808/v1"
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/event"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func newTestGRPCClient(t *testing.T) jt808v1.DeviceServiceClient {
+	a, err := NewAuthenticator(&config.AuthConf{
+		Enable: true,
+		APIKeys: []*config.APIKeyConf{
+			{Name: "dashboard", Key: "viewer-key", Role: "viewer"},
+			{Name: "ops", Key: "operator-key", Role: "operator"},
+		},
+	})
+	require.NoError(t, err)
+	audit, err := newAuditor("")
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	gs := newGRPCServer(nil, a, audit, nil)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return jt808v1.NewDeviceServiceClient(conn)
+}
+
+func TestGRPC_Unary(t *testing.T) {
+	client := newTestGRPCClient(t)
+	phone := "13900000001"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, Plate: "粤B00001", Status: model.DeviceStatusOnline})
+	t.Cleanup(func() { storage.GetDeviceCache().DelDeviceByPhone(phone) })
+
+	tests := []struct {
+		name     string
+		apiKey   string
+		call     func(ctx context.Context) error
+		wantCode codes.Code
+	}{
+		{
+			name: "unauthenticated",
+			call: func(ctx context.Context) error {
+				_, err := client.GetDevice(ctx, &jt808v1.GetDeviceRequest{Phone: phone})
+				return err
+			},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name: "get device", apiKey: "viewer-key",
+			call: func(ctx context.Context) error {
+				res, err := client.GetDevice(ctx, &jt808v1.GetDeviceRequest{Phone: phone})
+				if err == nil {
+					assert.Equal(t, phone, res.Phone)
+					assert.Equal(t, "online", res.Status)
+				}
+				return err
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "device not found", apiKey: "viewer-key",
+			call: func(ctx context.Context) error {
+				_, err := client.GetDevice(ctx, &jt808v1.GetDeviceRequest{Phone: "13900000009"})
+				return err
+			},
+			wantCode: codes.NotFound,
+		},
+		{
+			name: "list devices", apiKey: "viewer-key",
+			call: func(ctx context.Context) error {
+				res, err := client.ListDevices(ctx, &jt808v1.ListDevicesRequest{Phone: phone})
+				if err == nil {
+					assert.EqualValues(t, 1, res.Total)
+					require.Len(t, res.Devices, 1)
+					assert.Equal(t, "粤B00001", res.Devices[0].Plate)
+				}
+				return err
+			},
+			wantCode: codes.OK,
+		},
+		{
+			name: "invalid query", apiKey: "viewer-key",
+			call: func(ctx context.Context) error {
+				_, err := client.ListDevices(ctx, &jt808v1.ListDevicesRequest{Status: "lost"})
+				return err
+			},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name: "permission denied", apiKey: "viewer-key",
+			call: func(ctx context.Context) error {
+				_, err := client.SendCommand(ctx, &jt808v1.SendCommandRequest{Phone: phone})
+				return err
+			},
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.apiKey != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, apiKeyHeader, tt.apiKey)
+			}
+			assert.Equal(t, tt.wantCode, status.Code(tt.call(ctx)))
+		})
+	}
+}
+
+func TestGRPC_SendCommandValidation(t *testing.T) {
+	client := newTestGRPCClient(t)
+	phone := "13900000003"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, Status: model.DeviceStatusOffline})
+	t.Cleanup(func() { storage.GetDeviceCache().DelDeviceByPhone(phone) })
+
+	tests := []struct {
+		name     string
+		command  *jt808v1.Command
+		wantCode codes.Code
+	}{
+		{name: "missing command", wantCode: codes.InvalidArgument},
+		{name: "unknown type", command: &jt808v1.Command{Type: "reboot"}, wantCode: codes.InvalidArgument},
+		{name: "empty text", command: &jt808v1.Command{Type: "text", Text: &jt808v1.TextCommand{}}, wantCode: codes.InvalidArgument},
+		{
+			name:     "text too long",
+			command:  &jt808v1.Command{Type: "text", Text: &jt808v1.TextCommand{Text: strings.Repeat("a", 1025)}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "flag out of range",
+			command:  &jt808v1.Command{Type: "text", Text: &jt808v1.TextCommand{Flag: 256, Text: "hello"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "empty params",
+			command:  &jt808v1.Command{Type: "params", Params: &jt808v1.SetParams{}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "unknown control command",
+			command:  &jt808v1.Command{Type: "control", Control: &jt808v1.ControlCommand{Command: "selfDestruct"}},
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "valid command to offline device",
+			command:  &jt808v1.Command{Type: "text", Text: &jt808v1.TextCommand{Text: "hello"}},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyHeader, "operator-key")
+			_, err := client.SendCommand(ctx, &jt808v1.SendCommandRequest{Phone: phone, Command: tt.command})
+			assert.Equal(t, tt.wantCode, status.Code(err), err)
+		})
+	}
+}
+
+func TestGRPC_Subscribe(t *testing.T) {
+	client := newTestGRPCClient(t)
+	phone := "13900000002"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone})
+	t.Cleanup(func() { storage.GetDeviceCache().DelDeviceByPhone(phone) })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, apiKeyHeader, "viewer-key")
+	stream, err := client.Subscribe(ctx, &jt808v1.SubscribeRequest{Types: []string{string(event.TypeStatus)}, Phones: []string{phone}})
+	require.NoError(t, err)
+
+	// 订阅建立前发布的事件会丢失，持续发布直到收到
+	go func() {
+		for ctx.Err() == nil {
+			event.PublishLocation(phone, 0, &model.DeviceGeo{})
+			event.PublishStatus("13900000009", model.DeviceStatusOffline, model.DeviceStatusOnline)
+			event.PublishStatus(phone, model.DeviceStatusOffline, model.DeviceStatusOnline)
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+
+	res, err := stream.Recv()
+	require.NoError(t, err)
+	assert.Equal(t, string(event.TypeStatus), res.Type)
+	assert.Equal(t, phone, res.Phone)
+	assert.Equal(t, "online", res.Status)
+	assert.Equal(t, "offline", res.PrevStatus)
+}
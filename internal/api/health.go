@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fakeyanss/jt808-server-go/internal/server"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+const readinessCheckTimeout = 2 * time.Second
+
+// 就绪检查项，返回nil表示就绪
+type ReadinessCheck func(ctx context.Context) error
+
+var (
+	readinessChecks   = make(map[string]ReadinessCheck)
+	readinessChecksMu sync.Mutex
+)
+
+// 注册就绪检查项。对外的数据下游(sink)应注册连通性检查，不可达时/readyz返回503
+func RegisterReadinessCheck(name string, check ReadinessCheck) {
+	readinessChecksMu.Lock()
+	defer readinessChecksMu.Unlock()
+	readinessChecks[name] = check
+}
+
+func registerHealth(router *gin.Engine, serv *server.TCPServer) {
+	RegisterReadinessCheck("listener", func(context.Context) error { return serv.Ready() })
+	RegisterReadinessCheck("persistence", func(context.Context) error { return storage.CheckPersistence() })
+
+	// 存活检查，进程能响应即可
+	router.GET("/healthz", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	router.GET("/readyz", func(c *gin.Context) {
+		results, ready := runReadinessChecks(c.Request.Context())
+		code := http.StatusOK
+		status := "ok"
+		if !ready {
+			code = http.StatusServiceUnavailable
+			status = "unavailable"
+		}
+		c.JSON(code, gin.H{"status": status, "checks": results})
+	})
+}
+
+// 并发执行所有检查项，返回各项结果
+func runReadinessChecks(ctx context.Context) (map[string]string, bool) {
+	readinessChecksMu.Lock()
+	checks := make(map[string]ReadinessCheck, len(readinessChecks))
+	for name, check := range readinessChecks {
+		checks[name] = check
+	}
+	readinessChecksMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, readinessCheckTimeout)
+	defer cancel()
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		results = make(map[string]string, len(checks))
+		ready   = true
+	)
+	for name, check := range checks {
+		name, check := name, check
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res := "ok"
+			if err := check(ctx); err != nil {
+				res = err.Error()
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			results[name] = res
+			if res != "ok" {
+				ready = false
+			}
+		}()
+	}
+	wg.Wait()
+	return results, ready
+}
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/spatial"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+const (
+	defaultNearbyRadius = 5000   // 米
+	maxNearbyRadius     = 200000 // 米
+	defaultVehicleLimit = 100
+	maxVehicleLimit     = 1000
+)
+
+var nearbyQueryParams = []*queryParam{
+	numberParam("lat", "中心点纬度，按coord指定的坐标系"),
+	numberParam("lng", "中心点经度，按coord指定的坐标系"),
+	intParam("radius", "半径，米，默认5000，最大200000"),
+	intParam("limit", "最多返回的车辆数，默认100，最大1000"),
+	coordQueryParam,
+}
+
+var withinQueryParams = []*queryParam{
+	numberParam("minLat", "最小纬度"),
+	numberParam("minLng", "最小经度，大于maxLng时表示范围跨越180度经线"),
+	numberParam("maxLat", "最大纬度"),
+	numberParam("maxLng", "最大经度"),
+	intParam("limit", "最多返回的车辆数，默认100，最大1000"),
+	coordQueryParam,
+}
+
+// 按位置查询车辆的接口
+func (h *v1Handler) nearbyOperations() []*operation {
+	return []*operation{
+		{
+			Method: http.MethodGet, Path: "/devices/nearby", Summary: "查询附近的车辆，按距离升序", Tag: "device",
+			Role: RoleViewer, Query: nearbyQueryParams, Response: VehicleLocationListDTO{}, Handler: h.nearbyDevices,
+		},
+		{
+			Method: http.MethodGet, Path: "/devices/within", Summary: "查询矩形范围内的车辆", Tag: "device",
+			Role: RoleViewer, Query: withinQueryParams, Response: VehicleLocationListDTO{}, Handler: h.devicesWithin,
+		},
+	}
+}
+
+func parseQueryFloat(c *gin.Context, key string, min, max float64) (float64, error) {
+	v := c.Query(key)
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || math.IsNaN(f) || f < min || f > max {
+		return 0, errors.Wrapf(ErrInvalidQuery, "%s=%s", key, v)
+	}
+	return f, nil
+}
+
+func parseQueryInt(c *gin.Context, key string, def, max int) (int, error) {
+	v := c.Query(key)
+	if v == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil || i <= 0 || i > max {
+		return 0, errors.Wrapf(ErrInvalidQuery, "%s=%s", key, v)
+	}
+	return i, nil
+}
+
+// 解析查询中的经纬度并转换为WGS-84
+func parseQueryPoint(c *gin.Context, latKey, lngKey string, datum coord.Datum) (geo.Point, error) {
+	lat, err := parseQueryFloat(c, latKey, -90, 90)
+	if err != nil {
+		return geo.Point{}, err
+	}
+	lng, err := parseQueryFloat(c, lngKey, -180, 180)
+	if err != nil {
+		return geo.Point{}, err
+	}
+	return coord.Convert(geo.Point{Lat: lat, Lng: lng}, datum, coord.WGS84), nil
+}
+
+// 只保留缓存中存在且调用方有权限访问的设备，记录设备信息用于返回。在释放空间索引锁后按结果顺序调用
+func vehicleFilter(c *gin.Context, cache *storage.DeviceCache, devices map[string]*model.Device) func(phone string) bool {
+	p := principalOf(c)
+	return func(phone string) bool {
+		d, err := cache.GetDeviceByPhone(phone)
+		if err != nil || !p.CanAccessDevice(d) {
+			return false
+		}
+		devices[phone] = d
+		return true
+	}
+}
+
+func newVehicleLocationListDTO(results []*spatial.Result, devices map[string]*model.Device, datum coord.Datum) *VehicleLocationListDTO {
+	res := &VehicleLocationListDTO{Vehicles: make([]*VehicleLocationDTO, 0, len(results))}
+	for _, r := range results {
+		d := devices[r.Geo.Phone]
+		res.Vehicles = append(res.Vehicles, &VehicleLocationDTO{
+			Phone:    d.Phone,
+			Plate:    d.Plate,
+			Status:   d.Status.String(),
+			Distance: math.Round(r.Distance*10) / 10,
+			Location: newLocationDTO(r.Geo, datum),
+		})
+	}
+	return res
+}
+
+// 附近车辆查询条件，center为WGS-84坐标
+type nearbyQuery struct {
+	center geo.Point
+	radius int
+	limit  int
+	datum  coord.Datum
+}
+
+func parseNearbyQuery(c *gin.Context) (*nearbyQuery, error) {
+	datum, err := parseDatum(c.Query("coord"))
+	if err != nil {
+		return nil, err
+	}
+	center, err := parseQueryPoint(c, "lat", "lng", datum)
+	if err != nil {
+		return nil, err
+	}
+	radius, err := parseQueryInt(c, "radius", defaultNearbyRadius, maxNearbyRadius)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := parseQueryInt(c, "limit", defaultVehicleLimit, maxVehicleLimit)
+	if err != nil {
+		return nil, err
+	}
+	return &nearbyQuery{center: center, radius: radius, limit: limit, datum: datum}, nil
+}
+
+// 查询附近调用方有权限访问的车辆
+func nearbyVehicles(c *gin.Context, cache *storage.DeviceCache, q *nearbyQuery) *VehicleLocationListDTO {
+	devices := make(map[string]*model.Device)
+	results := spatial.Nearby(q.center, float64(q.radius), q.limit, vehicleFilter(c, cache, devices))
+	return newVehicleLocationListDTO(results, devices, q.datum)
+}
+
+func (h *v1Handler) nearbyDevices(c *gin.Context) {
+	q, err := parseNearbyQuery(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, nearbyVehicles(c, h.deviceCache, q))
+}
+
+func (h *v1Handler) devicesWithin(c *gin.Context) {
+	datum, ok := datumOf(c)
+	if !ok {
+		return
+	}
+	min, err := parseQueryPoint(c, "minLat", "minLng", datum)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	max, err := parseQueryPoint(c, "maxLat", "maxLng", datum)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	// minLng大于maxLng表示矩形跨越180度经线
+	if min.Lat > max.Lat {
+		respondError(c, errors.Wrap(ErrInvalidQuery, "minLat must not be greater than maxLat"))
+		return
+	}
+	limit, err := parseQueryInt(c, "limit", defaultVehicleLimit, maxVehicleLimit)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	devices := make(map[string]*model.Device)
+	leftTop, rightBottom := geo.Point{Lat: max.Lat, Lng: min.Lng}, geo.Point{Lat: min.Lat, Lng: max.Lng}
+	results := spatial.Within(leftTop, rightBottom, limit, vehicleFilter(c, h.deviceCache, devices))
+	c.JSON(http.StatusOK, newVehicleLocationListDTO(results, devices, datum))
+}
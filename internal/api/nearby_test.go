@@ -0,0 +1,99 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/spatial"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func TestV1_NearbyDevices(t *testing.T) {
+	router, _ := newTestRouter(t)
+	now := time.Now()
+	locs := map[string][2]float64{
+		"13900000491": {39.91, 116.4},
+		"13900000492": {39.9, 116.44},
+		"13900000493": {39.9, 116.6},
+		"13900000494": {39.9, 116.4}, // 不在设备缓存中
+		"13900000495": {0, 179.99},
+		"13900000496": {0, -179.99},
+	}
+	for phone, loc := range locs {
+		if phone != "13900000494" {
+			storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, Plate: "京A" + phone[7:], Status: model.DeviceStatusOnline})
+		}
+		spatial.Update(&model.DeviceGeo{
+			Phone: phone, Geo: &model.GeoMeta{LocationStatus: 1},
+			Location: &model.Location{Latitude: loc[0], Longitude: loc[1]}, Time: now,
+		})
+	}
+	t.Cleanup(func() {
+		for phone := range locs {
+			storage.GetDeviceCache().DelDeviceByPhone(phone)
+			spatial.Forget(phone)
+		}
+	})
+
+	w := doRequest(t, router, http.MethodGet, "/api/v1/devices/nearby?lat=39.9&lng=116.4&radius=5000", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res := &VehicleLocationListDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	require.Len(t, res.Vehicles, 2)
+	assert.Equal(t, "13900000491", res.Vehicles[0].Phone)
+	assert.Equal(t, "京A0491", res.Vehicles[0].Plate)
+	assert.Equal(t, "online", res.Vehicles[0].Status)
+	assert.InDelta(t, 1112, res.Vehicles[0].Distance, 1)
+	assert.Equal(t, 39.91, res.Vehicles[0].Location.Latitude)
+	assert.Equal(t, "13900000492", res.Vehicles[1].Phone)
+
+	w = doRequest(t, router, http.MethodGet, "/api/v1/devices/within?minLat=39.8&minLng=116.3&maxLat=40&maxLng=116.7&limit=2", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res = &VehicleLocationListDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	require.Len(t, res.Vehicles, 2)
+	assert.Equal(t, "13900000491", res.Vehicles[0].Phone)
+	assert.Equal(t, "13900000492", res.Vehicles[1].Phone)
+
+	// 跨越180度经线
+	w = doRequest(t, router, http.MethodGet, "/api/v1/devices/within?minLat=-1&minLng=179&maxLat=1&maxLng=-179", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res = &VehicleLocationListDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	require.Len(t, res.Vehicles, 2)
+	assert.Equal(t, "13900000495", res.Vehicles[0].Phone)
+	assert.Equal(t, "13900000496", res.Vehicles[1].Phone)
+
+	// 旧接口返回相同的结构
+	w = doRequest(t, router, http.MethodGet, "/device/nearby?lat=0&lng=-179.999&radius=5000", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res = &VehicleLocationListDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	require.Len(t, res.Vehicles, 2)
+	assert.Equal(t, "13900000496", res.Vehicles[0].Phone)
+	assert.Equal(t, "13900000495", res.Vehicles[1].Phone)
+
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "missing lat", path: "/api/v1/devices/nearby?lng=116.4"},
+		{name: "lat out of range", path: "/api/v1/devices/nearby?lat=91&lng=116.4"},
+		{name: "radius too large", path: "/api/v1/devices/nearby?lat=39.9&lng=116.4&radius=300000"},
+		{name: "invalid limit", path: "/api/v1/devices/nearby?lat=39.9&lng=116.4&limit=0"},
+		{name: "min greater than max", path: "/api/v1/devices/within?minLat=40&minLng=116.3&maxLat=39.8&maxLng=116.7"},
+		{name: "legacy missing lng", path: "/device/nearby?lat=39.9"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(t, router, http.MethodGet, tt.path, "viewer-key", nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
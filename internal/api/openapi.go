@@ -0,0 +1,260 @@
+package api
+
+import (
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const openAPIVersion = "3.0.3"
+
+// 接口定义，用于同时注册gin路由和生成OpenAPI文档
+type operation struct {
+	Method   string
+	Path     string // gin风格路径，如 /devices/:phone
+	Summary  string
+	Tag      string
+	Role     Role
+	Query    []*queryParam
+	Request  any  // 请求体类型的零值，为nil表示无请求体
+	Optional bool // 请求体可省略
+	Upload   bool // 请求体为二进制文件，忽略Request
+	Response any  // 成功响应类型的零值，为nil表示无响应体
+	Status   int  // 成功状态码，默认200
+	Handler  gin.HandlerFunc
+}
+
+type queryParam struct {
+	Name        string
+	Description string
+	Schema      map[string]any
+}
+
+func stringParam(name, desc string, enum ...string) *queryParam {
+	schema := map[string]any{"type": "string"}
+	if len(enum) > 0 {
+		schema["enum"] = enum
+	}
+	return &queryParam{Name: name, Description: desc, Schema: schema}
+}
+
+func intParam(name, desc string) *queryParam {
+	return &queryParam{Name: name, Description: desc, Schema: map[string]any{"type": "integer"}}
+}
+
+func numberParam(name, desc string) *queryParam {
+	return &queryParam{Name: name, Description: desc, Schema: map[string]any{"type": "number"}}
+}
+
+func boolParam(name, desc string) *queryParam {
+	return &queryParam{Name: name, Description: desc, Schema: map[string]any{"type": "boolean"}}
+}
+
+func timeParam(name, desc string) *queryParam {
+	return &queryParam{Name: name, Description: desc, Schema: map[string]any{"type": "string", "format": "date-time"}}
+}
+
+// 注册路由，每个接口按Role鉴权
+func registerOperations(group *gin.RouterGroup, ops []*operation) {
+	for _, op := range ops {
+		group.Handle(op.Method, op.Path, requireRole(op.Role), op.Handler)
+	}
+}
+
+var ginPathParam = regexp.MustCompile(`:(\w+)`)
+
+// 根据接口定义生成OpenAPI 3文档
+func genOpenAPI(basePath string, ops []*operation) map[string]any {
+	g := &schemaGen{schemas: make(map[string]any)}
+	errRef := g.schemaOf(reflect.TypeOf(ErrorResponse{}))
+
+	paths := make(map[string]any)
+	for _, op := range ops {
+		path := basePath + ginPathParam.ReplaceAllString(op.Path, "{$1}")
+		item, ok := paths[path].(map[string]any)
+		if !ok {
+			item = make(map[string]any)
+			paths[path] = item
+		}
+
+		var params []any
+		for _, m := range ginPathParam.FindAllStringSubmatch(op.Path, -1) {
+			params = append(params, map[string]any{
+				"name": m[1], "in": "path", "required": true, "schema": map[string]any{"type": "string"},
+			})
+		}
+		for _, q := range op.Query {
+			params = append(params, map[string]any{
+				"name": q.Name, "in": "query", "description": q.Description, "schema": q.Schema,
+			})
+		}
+
+		status := op.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		success := map[string]any{"description": http.StatusText(status)}
+		if op.Response != nil {
+			success["content"] = jsonContent(g.schemaOf(reflect.TypeOf(op.Response)))
+		}
+		spec := map[string]any{
+			"summary":     op.Summary,
+			"operationId": operationID(op),
+			"tags":        []string{op.Tag},
+			"description": "Requires role: " + string(op.Role),
+			"responses": map[string]any{
+				strconv.Itoa(status): success,
+				"default":            map[string]any{"description": "Error", "content": jsonContent(errRef)},
+			},
+		}
+		if len(params) > 0 {
+			spec["parameters"] = params
+		}
+		if op.Upload {
+			spec["requestBody"] = map[string]any{
+				"required": true,
+				"content": map[string]any{
+					"application/octet-stream": map[string]any{"schema": map[string]any{"type": "string", "format": "binary"}},
+				},
+			}
+		} else if op.Request != nil {
+			spec["requestBody"] = map[string]any{
+				"required": !op.Optional,
+				"content":  jsonContent(g.schemaOf(reflect.TypeOf(op.Request))),
+			}
+		}
+		item[strings.ToLower(op.Method)] = spec
+	}
+
+	return map[string]any{
+		"openapi": openAPIVersion,
+		"info": map[string]any{
+			"title":   "jt808-server-go API",
+			"version": "v1",
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": g.schemas,
+			"securitySchemes": map[string]any{
+				"ApiKeyAuth": map[string]any{"type": "apiKey", "in": "header", "name": apiKeyHeader},
+				"BearerAuth": map[string]any{"type": "http", "scheme": "bearer", "bearerFormat": "JWT"},
+			},
+		},
+		"security": []any{
+			map[string]any{"ApiKeyAuth": []string{}},
+			map[string]any{"BearerAuth": []string{}},
+		},
+	}
+}
+
+func jsonContent(schema any) map[string]any {
+	return map[string]any{"application/json": map[string]any{"schema": schema}}
+}
+
+// 如 GET /devices/:phone/location -> getDevicesPhoneLocation
+func operationID(op *operation) string {
+	id := strings.ToLower(op.Method)
+	for _, seg := range strings.Split(op.Path, "/") {
+		seg = strings.TrimPrefix(seg, ":")
+		if seg == "" {
+			continue
+		}
+		id += strings.ToUpper(seg[:1]) + seg[1:]
+	}
+	return id
+}
+
+// 通过反射将DTO类型转换为JSON Schema，具名struct放入components
+type schemaGen struct {
+	schemas map[string]any
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+func (g *schemaGen) schemaOf(t reflect.Type) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t == timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case t.Kind() == reflect.Struct:
+		if _, ok := g.schemas[t.Name()]; !ok {
+			g.schemas[t.Name()] = map[string]any{} // 占位，避免递归类型死循环
+			g.schemas[t.Name()] = g.structSchema(t)
+		}
+		return map[string]any{"$ref": "#/components/schemas/" + t.Name()}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return map[string]any{"type": "array", "items": g.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+	case t.Kind() == reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case t.Kind() == reflect.String:
+		return map[string]any{"type": "string"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return map[string]any{"type": "number"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		schema := map[string]any{"type": "integer"}
+		if t.Kind() == reflect.Int64 || t.Kind() == reflect.Uint64 {
+			schema["format"] = "int64"
+		}
+		if t.Kind() >= reflect.Uint {
+			schema["minimum"] = 0
+		}
+		return schema
+	default: // interface等任意类型
+		return map[string]any{}
+	}
+}
+
+func (g *schemaGen) structSchema(t reflect.Type) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := jsonName(f)
+		if name == "-" {
+			continue
+		}
+		schema := g.schemaOf(f.Type)
+		rules := strings.Split(f.Tag.Get("binding"), ",")
+		for _, rule := range rules {
+			switch {
+			case rule == "required":
+				required = append(required, name)
+			case strings.HasPrefix(rule, "oneof="):
+				schema["enum"] = strings.Fields(strings.TrimPrefix(rule, "oneof="))
+			}
+		}
+		if desc := f.Tag.Get("description"); desc != "" {
+			if _, isRef := schema["$ref"]; isRef {
+				schema = map[string]any{"allOf": []any{schema}, "description": desc}
+			} else {
+				schema["description"] = desc
+			}
+		}
+		props[name] = schema
+	}
+	res := map[string]any{"type": "object", "properties": props}
+	if len(required) > 0 {
+		res["required"] = required
+	}
+	return res
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,119 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+	"github.com/fakeyanss/jt808-server-go/internal/export"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+// 未指定from时默认导出的时长
+const defaultTrackExportRange = 24 * time.Hour
+
+var trackExportQueryParams = []*queryParam{
+	stringParam("format", "导出格式", string(export.FormatGPX), string(export.FormatKML), string(export.FormatGeoJSON), string(export.FormatCSV)),
+	timeParam("from", "开始时间RFC3339(含)，默认为to之前24小时"),
+	timeParam("to", "结束时间RFC3339(不含)，默认为当前时间"),
+	boolParam("raw", "是否包含数据质量过滤标记的点"),
+	coordQueryParam,
+}
+
+// 历史轨迹的接口
+func (h *v1Handler) trackOperations() []*operation {
+	return []*operation{
+		{
+			Method: http.MethodGet, Path: "/devices/:phone/track/export", Summary: "导出历史轨迹文件", Tag: "track",
+			Role: RoleViewer, Query: trackExportQueryParams, Handler: h.exportTrack,
+		},
+	}
+}
+
+// 轨迹导出条件，时间已转换为设备时间
+type trackExportQuery struct {
+	format   export.Format
+	datum    coord.Datum
+	from, to time.Time
+	raw      bool
+}
+
+func parseTrackExportQuery(c *gin.Context) (*trackExportQuery, error) {
+	datum, err := parseDatum(c.Query("coord"))
+	if err != nil {
+		return nil, err
+	}
+	format, err := export.ParseFormat(c.Query("format"))
+	if err != nil {
+		return nil, err
+	}
+	from, err := parseQueryTime(c.Query, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseQueryTime(c.Query, "to")
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if from.IsZero() {
+		from = to.Add(-defaultTrackExportRange)
+	}
+	if !from.Before(to) {
+		return nil, errors.Wrapf(ErrInvalidQuery, "from=%s, to=%s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	return &trackExportQuery{
+		format: format,
+		datum:  datum,
+		from:   hex.ToDeviceTime(from),
+		to:     hex.ToDeviceTime(to),
+		raw:    c.Query("raw") == "true",
+	}, nil
+}
+
+func (h *v1Handler) exportTrack(c *gin.Context) {
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	q, err := parseTrackExportQuery(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	writeTrackExport(c, d, q)
+}
+
+// 边读边写导出的轨迹文件
+func writeTrackExport(c *gin.Context, d *model.Device, q *trackExportQuery) {
+	c.Header("Content-Type", q.format.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s_%s_%s.%s"`,
+		d.Phone, q.from.Format("20060102150405"), q.to.Format("20060102150405"), q.format))
+	c.Status(http.StatusOK)
+	w, err := export.NewWriter(q.format, c.Writer, &export.Meta{Phone: d.Phone, Plate: d.Plate, From: q.from, To: q.to, Datum: q.datum})
+	if err == nil {
+		err = storage.GetTrackStore().Iterate(d.Phone, q.from, q.to, func(p *model.TrackPoint) error {
+			if !q.raw && len(p.Quality) > 0 {
+				return nil
+			}
+			return w.WritePoint(p)
+		})
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	// 响应头已发送，出错时只能中断响应
+	if err != nil {
+		log.Error().Err(err).Str("phone", d.Phone).Msg("Fail to export track")
+		c.Abort()
+	}
+}
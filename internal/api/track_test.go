@@ -0,0 +1,83 @@
+package api
+
+import (
+	"bytes"
+	"encoding/csv"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func TestV1_TrackExport(t *testing.T) {
+	router, _ := newTestRouter(t)
+	phone := "13900000048"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, Status: model.DeviceStatusOffline})
+	store := storage.GetTrackStore()
+	store.Configure(true, t.TempDir(), 0)
+	t.Cleanup(func() {
+		storage.GetDeviceCache().DelDeviceByPhone(phone)
+		store.Configure(true, "track", 0)
+	})
+
+	// 设备时间按UTC保存
+	day := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		p := &model.TrackPoint{Time: day.Add(time.Duration(i) * time.Hour), Latitude: 39.9, Longitude: 116.4, StatusSign: 0b11}
+		require.NoError(t, store.Append(phone, p))
+	}
+	// 数据质量过滤标记的点默认不导出
+	require.NoError(t, store.Append(phone, &model.TrackPoint{Time: day.Add(30 * time.Minute), StatusSign: 0b01, Quality: []string{"unfixed"}}))
+
+	base := "/api/v1/devices/" + phone + "/track/export"
+	w := doRequest(t, router, http.MethodGet, base+"?format=csv&from=2024-05-01T08:00:00%2B08:00&to=2024-05-01T10:00:00%2B08:00", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="13900000048_20240501080000_20240501100000.csv"`, w.Header().Get("Content-Disposition"))
+	records, err := csv.NewReader(bytes.NewReader(w.Body.Bytes())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, "2024-05-01T08:00:00Z", records[1][0])
+	assert.Equal(t, "2024-05-01T09:00:00Z", records[2][0])
+
+	w = doRequest(t, router, http.MethodGet, base+"?format=csv&raw=true&from=2024-05-01T08:00:00%2B08:00&to=2024-05-01T10:00:00%2B08:00", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	records, err = csv.NewReader(bytes.NewReader(w.Body.Bytes())).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	// 轨迹点按接收顺序导出
+	assert.Equal(t, "unfixed", records[3][len(records[3])-1])
+
+	w = doRequest(t, router, http.MethodGet, base+"?format=gpx&from=2024-05-01T00:00:00Z&to=2024-05-02T00:00:00Z", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, bytes.Count(w.Body.Bytes(), []byte("<trkpt ")))
+
+	// 旧接口
+	w = doRequest(t, router, http.MethodGet, "/device/"+phone+"/track/export?format=gpx&from=2024-05-01T00:00:00Z&to=2024-05-02T00:00:00Z", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 3, bytes.Count(w.Body.Bytes(), []byte("<trkpt ")))
+	w = doRequest(t, router, http.MethodGet, "/device/"+phone+"/track/export?format=shp", "viewer-key", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{name: "unknown format", query: "?format=shp"},
+		{name: "missing format", query: ""},
+		{name: "invalid time", query: "?format=kml&from=2024-05-01"},
+		{name: "empty range", query: "?format=kml&from=2024-05-02T00:00:00Z&to=2024-05-01T00:00:00Z"},
+		{name: "invalid coord", query: "?format=kml&coord=xyz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(t, router, http.MethodGet, base+tt.query, "viewer-key", nil)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+}
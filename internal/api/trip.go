@@ -0,0 +1,114 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+	"github.com/fakeyanss/jt808-server-go/internal/trip"
+)
+
+const dateLayout = "2006-01-02"
+
+// 行程查询参数，日期按设备时间(GMT+8)
+var tripQueryParams = []*queryParam{
+	stringParam("from", "开始日期YYYY-MM-DD(含)"),
+	stringParam("to", "结束日期YYYY-MM-DD(含)"),
+	boolParam("ongoing", "是否包含进行中的行程"),
+	coordQueryParam,
+}
+
+// 行程统计的接口
+func (h *v1Handler) tripOperations() []*operation {
+	return []*operation{
+		{
+			Method: http.MethodGet, Path: "/devices/:phone/trips", Summary: "查询设备行程及每日汇总", Tag: "trip",
+			Role: RoleViewer, Query: tripQueryParams, Response: TripListDTO{}, Handler: h.listTrips,
+		},
+	}
+}
+
+func parseQueryDate(c *gin.Context, key string) (time.Time, error) {
+	v := c.Query(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	// 设备时间按UTC保存，日期同样按UTC解析
+	t, err := time.Parse(dateLayout, v)
+	if err != nil {
+		return time.Time{}, errors.Wrapf(ErrInvalidQuery, "%s=%s", key, v)
+	}
+	return t, nil
+}
+
+// 行程查询条件，to为结束日期的次日零点
+type tripQuery struct {
+	from, to time.Time
+	ongoing  bool
+	datum    coord.Datum
+}
+
+func parseTripQuery(c *gin.Context) (*tripQuery, error) {
+	datum, err := parseDatum(c.Query("coord"))
+	if err != nil {
+		return nil, err
+	}
+	from, err := parseQueryDate(c, "from")
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseQueryDate(c, "to")
+	if err != nil {
+		return nil, err
+	}
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+	return &tripQuery{from: from, to: to, ongoing: c.Query("ongoing") == "true", datum: datum}, nil
+}
+
+func (h *v1Handler) listTrips(c *gin.Context) {
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	q, err := parseTripQuery(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newTripList(h.tripCache, d.Phone, q))
+}
+
+// 查询设备的行程、行程外怠速及每日汇总
+func newTripList(cache *storage.TripCache, phone string, q *tripQuery) *TripListDTO {
+	trips := cache.ListTrips(phone, q.from, q.to)
+	idles := cache.ListIdles(phone, q.from, q.to)
+	res := &TripListDTO{
+		Trips: make([]*TripDTO, 0, len(trips)),
+		Idles: make([]*IdleDTO, 0, len(idles)),
+		Days:  make([]*TripDayDTO, 0),
+	}
+	for _, t := range trips {
+		res.Trips = append(res.Trips, newTripDTO(t, false, q.datum))
+	}
+	if q.ongoing {
+		if t := trip.Current(phone); t != nil &&
+			(q.from.IsZero() || !t.StartTime.Before(q.from)) && (q.to.IsZero() || t.StartTime.Before(q.to)) {
+			res.Trips = append(res.Trips, newTripDTO(t, true, q.datum))
+			trips = append(trips, t)
+		}
+	}
+	for _, p := range idles {
+		res.Idles = append(res.Idles, newIdleDTO(p, q.datum))
+	}
+	for _, s := range model.SummarizeTrips(trips, idles) {
+		res.Days = append(res.Days, newTripDayDTO(s))
+	}
+	return res
+}
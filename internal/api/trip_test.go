@@ -0,0 +1,79 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func TestV1_Trips(t *testing.T) {
+	router, _ := newTestRouter(t)
+	phone := "13900000044"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, Status: model.DeviceStatusOffline})
+	t.Cleanup(func() {
+		storage.GetDeviceCache().DelDeviceByPhone(phone)
+		storage.GetTripCache().DelTripsByPhone(phone)
+	})
+
+	day := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	mileage := 12.5
+	trips := []*model.Trip{
+		{Phone: phone, StartTime: day, EndTime: day.Add(30 * time.Minute), Distance: 12000, Mileage: &mileage, MaxSpeed: 60},
+		{
+			Phone: phone, StartTime: day.Add(4 * time.Hour), EndTime: day.Add(5 * time.Hour), Distance: 30000, MaxSpeed: 80,
+			IdleDuration: 5 * time.Minute,
+			Stops:        []*model.TripStop{{StartTime: day.Add(4*time.Hour + 10*time.Minute), EndTime: day.Add(4*time.Hour + 15*time.Minute), Idling: true}},
+		},
+		{Phone: phone, StartTime: day.AddDate(0, 0, 1), EndTime: day.AddDate(0, 0, 1).Add(time.Hour), Distance: 50000, MaxSpeed: 90},
+	}
+	for _, trip := range trips {
+		storage.GetTripCache().AddTrip(trip)
+	}
+	storage.GetTripCache().AddIdle(&model.IdlePeriod{Phone: phone, StartTime: day.Add(2 * time.Hour), EndTime: day.Add(2*time.Hour + 2*time.Minute)})
+
+	w := doRequest(t, router, http.MethodGet, "/api/v1/devices/"+phone+"/trips?from=2024-05-01&to=2024-05-01", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res := &TripListDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	require.Len(t, res.Trips, 2)
+	require.Len(t, res.Idles, 1)
+	assert.Equal(t, int64(120), res.Idles[0].DurationSec)
+	assert.Equal(t, int64(1800), res.Trips[0].DurationSec)
+	assert.Equal(t, 24.0, res.Trips[0].AvgSpeed)
+	require.Len(t, res.Trips[1].Stops, 1)
+	assert.Equal(t, int64(300), res.Trips[1].Stops[0].DurationSec)
+	require.Len(t, res.Days, 1)
+	assert.Equal(t, &TripDayDTO{
+		Date: "2024-05-01", Trips: 2, Distance: 42000, Mileage: 12.5, DriveSec: 5400, IdleSec: 420,
+		Stops: 1, StopSec: 300, MaxSpeed: 80,
+	}, res.Days[0])
+
+	w = doRequest(t, router, http.MethodGet, "/api/v1/devices/"+phone+"/trips", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res = &TripListDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Len(t, res.Trips, 3)
+	assert.Len(t, res.Days, 2)
+
+	// 旧接口返回相同的结构
+	w = doRequest(t, router, http.MethodGet, "/device/"+phone+"/trips?from=2024-05-02", "viewer-key", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	res = &TripListDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	assert.Len(t, res.Trips, 1)
+	assert.Empty(t, res.Idles)
+	w = doRequest(t, router, http.MethodGet, "/device/"+phone+"/trips?to=20240501", "viewer-key", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doRequest(t, router, http.MethodGet, "/api/v1/devices/"+phone+"/trips?from=20240501", "viewer-key", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	w = doRequest(t, router, http.MethodGet, "/api/v1/devices/13900000000/trips", "viewer-key", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,635 @@
+package api
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/rand"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/server"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+var (
+	ErrInvalidFirmware  = errors.New("Invalid firmware")
+	ErrFirmwareInUse    = errors.New("Firmware is used by unfinished upgrade task")
+	ErrUpgradeTaskState = errors.New("Operation not allowed in current upgrade task state")
+)
+
+const (
+	maxFirmwareSize         = 32 << 20 // 分包总数不超过65535
+	upgradeFragmentSize     = 1000     // 分包消息体长度
+	upgradeFragmentRetries  = 3        // 分包未收到应答时的重试次数
+	defaultUpgradeBatchSize = 10
+)
+
+// 升级包管理和分批升级的接口
+var upgradeQueryParams = []*queryParam{
+	stringParam("name", "升级包名称"),
+	intParam("upgradeType", "升级类型，0终端，12道路运输证IC卡读卡器，52北斗卫星定位模块"),
+	stringParam("manufacturerId", "制造商ID"),
+	stringParam("version", "版本号"),
+}
+
+func (h *v1Handler) upgradeOperations() []*operation {
+	return []*operation{
+		{
+			Method: http.MethodPost, Path: "/firmwares", Summary: "上传升级包", Tag: "upgrade", Role: RoleAdmin,
+			Query: upgradeQueryParams, Upload: true, Response: FirmwareDTO{}, Status: http.StatusCreated,
+			Handler: h.uploadFirmware,
+		},
+		{
+			Method: http.MethodGet, Path: "/firmwares", Summary: "查询升级包列表", Tag: "upgrade", Role: RoleViewer,
+			Response: []*FirmwareDTO{}, Handler: h.listFirmwares,
+		},
+		{
+			Method: http.MethodGet, Path: "/firmwares/:id", Summary: "查询升级包", Tag: "upgrade", Role: RoleViewer,
+			Response: FirmwareDTO{}, Handler: h.getFirmware,
+		},
+		{
+			Method: http.MethodDelete, Path: "/firmwares/:id", Summary: "删除升级包", Tag: "upgrade", Role: RoleAdmin,
+			Status: http.StatusNoContent, Handler: h.deleteFirmware,
+		},
+		{
+			Method: http.MethodPost, Path: "/upgrades", Summary: "创建升级任务并开始分批下发(0x8108)", Tag: "upgrade",
+			Role: RoleOperator, Request: CreateUpgradeRequest{}, Response: UpgradeTaskDTO{}, Status: http.StatusCreated,
+			Handler: h.createUpgrade,
+		},
+		{
+			Method: http.MethodGet, Path: "/upgrades", Summary: "查询升级任务列表", Tag: "upgrade", Role: RoleViewer,
+			Response: []*UpgradeTaskDTO{}, Handler: h.listUpgrades,
+		},
+		{
+			Method: http.MethodGet, Path: "/upgrades/:id", Summary: "查询升级任务及各设备进度", Tag: "upgrade", Role: RoleViewer,
+			Response: UpgradeTaskDTO{}, Handler: h.getUpgrade,
+		},
+		{
+			Method: http.MethodPost, Path: "/upgrades/:id/pause", Summary: "暂停升级任务", Tag: "upgrade", Role: RoleOperator,
+			Response: UpgradeTaskDTO{}, Handler: h.pauseUpgrade,
+		},
+		{
+			Method: http.MethodPost, Path: "/upgrades/:id/resume", Summary: "继续升级任务", Tag: "upgrade", Role: RoleOperator,
+			Response: UpgradeTaskDTO{}, Handler: h.resumeUpgrade,
+		},
+		{
+			Method: http.MethodPost, Path: "/upgrades/:id/abort", Summary: "终止升级任务", Tag: "upgrade", Role: RoleOperator,
+			Response: UpgradeTaskDTO{}, Handler: h.abortUpgrade,
+		},
+	}
+}
+
+func (h *v1Handler) uploadFirmware(c *gin.Context) {
+	upgradeType, err := strconv.ParseUint(c.DefaultQuery("upgradeType", "0"), 10, 8)
+	if err != nil {
+		respondError(c, errors.Wrap(ErrInvalidFirmware, "invalid upgradeType"))
+		return
+	}
+	fw := &model.Firmware{
+		Name:           c.Query("name"),
+		UpgradeType:    uint8(upgradeType),
+		ManufacturerID: c.Query("manufacturerId"),
+		Version:        c.Query("version"),
+		CreatedAt:      time.Now(),
+	}
+	switch {
+	case fw.Version == "" || len(fw.Version) > 255:
+		respondError(c, errors.Wrap(ErrInvalidFirmware, "version is required and at most 255 bytes"))
+		return
+	case len(fw.ManufacturerID) > 11:
+		respondError(c, errors.Wrap(ErrInvalidFirmware, "manufacturerId is at most 11 bytes"))
+		return
+	}
+	image, err := io.ReadAll(io.LimitReader(c.Request.Body, maxFirmwareSize+1))
+	if err != nil {
+		badRequest(c, err)
+		return
+	}
+	if len(image) == 0 || len(image) > maxFirmwareSize {
+		respondError(c, errors.Wrapf(ErrInvalidFirmware, "size must be in (0, %d]", maxFirmwareSize))
+		return
+	}
+	sum := md5.Sum(image)
+	fw.ID = newResourceID("fw")
+	fw.Size = len(image)
+	fw.MD5 = hex.EncodeToString(sum[:])
+	if fw.Name == "" {
+		fw.Name = fw.Version
+	}
+	if err := h.firmwareCache.SaveFirmware(fw, image); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, newFirmwareDTO(fw))
+}
+
+func (h *v1Handler) listFirmwares(c *gin.Context) {
+	res := make([]*FirmwareDTO, 0)
+	for _, fw := range h.firmwareCache.ListFirmware() {
+		res = append(res, newFirmwareDTO(fw))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *v1Handler) getFirmware(c *gin.Context) {
+	fw, err := h.firmwareCache.GetFirmware(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newFirmwareDTO(fw))
+}
+
+func (h *v1Handler) deleteFirmware(c *gin.Context) {
+	id := c.Param("id")
+	if h.upgradeCache.IsFirmwareInUse(id) {
+		respondError(c, errors.Wrapf(ErrFirmwareInUse, "firmware=%s", id))
+		return
+	}
+	if err := h.firmwareCache.DelFirmware(id); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *v1Handler) createUpgrade(c *gin.Context) {
+	req := &CreateUpgradeRequest{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	p := principalOf(c)
+	if req.Group != "" && !p.CanAccessGroup(req.Group) {
+		abortWithError(c, http.StatusForbidden, CodePermissionDenied, "Permission denied for group "+req.Group)
+		return
+	}
+	fw, err := h.firmwareCache.GetFirmware(req.FirmwareID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	devices, err := h.upgradeDevices(p, req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if len(devices) == 0 {
+		respondError(c, errors.Wrap(ErrInvalidCommand, "no device to upgrade"))
+		return
+	}
+	// 制造商ID按设备协议版本定长下发，超长时拒绝而不是截断
+	for _, d := range devices {
+		if maxLen := model.ManufacturerIDLen(d.VersionDesc); len(fw.ManufacturerID) > maxLen {
+			respondError(c, errors.Wrapf(ErrInvalidFirmware, "manufacturerId is at most %d bytes for %s device %s", maxLen, d.VersionDesc, d.Phone))
+			return
+		}
+	}
+
+	now := time.Now()
+	t := &model.UpgradeTask{
+		ID:            newResourceID("up"),
+		FirmwareID:    fw.ID,
+		State:         model.UpgradeTaskRunning,
+		BatchSize:     req.BatchSize,
+		BatchInterval: time.Duration(req.BatchIntervalSec) * time.Second,
+		MaxFailures:   req.MaxFailures,
+		Operator:      p.Name,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if t.BatchSize == 0 {
+		t.BatchSize = defaultUpgradeBatchSize
+	}
+	for _, d := range devices {
+		du := &model.DeviceUpgrade{Phone: d.Phone, State: model.DeviceUpgradePending, UpdatedAt: now}
+		if fw.ManufacturerID != "" && d.ManufacturerID != "" && fw.ManufacturerID != d.ManufacturerID {
+			du.State = model.DeviceUpgradeFailed
+			du.Error = "manufacturer id mismatch"
+		}
+		t.Devices = append(t.Devices, du)
+	}
+	h.upgradeCache.CacheTask(t)
+	res := newUpgradeTaskDTO(t, true) // 启动下发前生成，之后t由下发协程在缓存锁内修改
+	h.upgrader.start(t.ID, callerOf(c))
+	c.JSON(http.StatusCreated, res)
+}
+
+// 分组成员与指定手机号的并集，仅包含调用方可访问的设备
+func (h *v1Handler) upgradeDevices(p *Principal, req *CreateUpgradeRequest) ([]*model.Device, error) {
+	var res []*model.Device
+	seen := make(map[string]bool)
+	if req.Group != "" {
+		if _, err := h.groupCache.GetGroup(req.Group); err != nil {
+			return nil, err
+		}
+		q := &storage.DeviceQuery{Group: req.Group, Filter: p.CanAccessDevice, Limit: storage.MaxPageLimit}
+		for {
+			page, err := h.deviceCache.QueryDevice(q)
+			if err != nil {
+				return nil, err
+			}
+			for _, d := range page.Devices {
+				seen[d.Phone] = true
+				res = append(res, d)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			q.Cursor = page.NextCursor
+		}
+	}
+	for _, phone := range req.Phones {
+		if seen[phone] {
+			continue
+		}
+		d, err := h.deviceCache.GetDeviceByPhone(phone)
+		if err != nil {
+			return nil, errors.Wrapf(err, "phone=%s", phone)
+		}
+		if !p.CanAccessDevice(d) {
+			return nil, errors.Wrapf(storage.ErrDeviceNotFound, "phone=%s", phone)
+		}
+		seen[phone] = true
+		res = append(res, d)
+	}
+	return res, nil
+}
+
+func (h *v1Handler) listUpgrades(c *gin.Context) {
+	p := principalOf(c)
+	res := make([]*UpgradeTaskDTO, 0)
+	for _, t := range h.upgradeCache.ListTask() {
+		if canManageUpgrade(p, t) {
+			res = append(res, newUpgradeTaskDTO(t, false))
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// 查询路径中的升级任务并校验权限，失败时已写入错误响应
+func (h *v1Handler) upgradeTask(c *gin.Context) (*model.UpgradeTask, bool) {
+	t, err := h.upgradeCache.GetTask(c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	if !canManageUpgrade(principalOf(c), t) {
+		abortWithError(c, http.StatusForbidden, CodePermissionDenied, "Permission denied for upgrade task "+t.ID)
+		return nil, false
+	}
+	return t, true
+}
+
+// 未限制分组的调用方可管理所有任务，否则只能管理自己创建的任务
+func canManageUpgrade(p *Principal, t *model.UpgradeTask) bool {
+	return p.Role == RoleAdmin || len(p.Groups) == 0 || p.Name == t.Operator
+}
+
+func (h *v1Handler) getUpgrade(c *gin.Context) {
+	if t, ok := h.upgradeTask(c); ok {
+		c.JSON(http.StatusOK, newUpgradeTaskDTO(t, true))
+	}
+}
+
+func (h *v1Handler) pauseUpgrade(c *gin.Context) {
+	h.changeUpgradeState(c, h.upgrader.pause)
+}
+
+func (h *v1Handler) resumeUpgrade(c *gin.Context) {
+	h.changeUpgradeState(c, func(id string) error {
+		return h.upgrader.resume(id, callerOf(c))
+	})
+}
+
+func (h *v1Handler) abortUpgrade(c *gin.Context) {
+	h.changeUpgradeState(c, h.upgrader.abort)
+}
+
+func (h *v1Handler) changeUpgradeState(c *gin.Context, change func(id string) error) {
+	t, ok := h.upgradeTask(c)
+	if !ok {
+		return
+	}
+	if err := change(t.ID); err != nil {
+		respondError(c, err)
+		return
+	}
+	t, err := h.upgradeCache.GetTask(t.ID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newUpgradeTaskDTO(t, false))
+}
+
+func newResourceID(prefix string) string {
+	b := make([]byte, 8)
+	_, _ = rand.Read(b)
+	return prefix + "-" + hex.EncodeToString(b)
+}
+
+// 分批下发升级包。每个运行中的任务对应一个下发协程，暂停或终止后协程退出
+type upgrader struct {
+	serv          *server.TCPServer
+	audit         *auditor
+	deviceCache   *storage.DeviceCache
+	firmwareCache *storage.FirmwareCache
+	upgradeCache  *storage.UpgradeCache
+
+	mutex   sync.Mutex
+	running map[string]chan struct{} // 任务ID -> 唤醒批次间隔等待的信号
+}
+
+// 进程重启后，中断的任务置为暂停，由调用方决定是否继续
+func newUpgrader(serv *server.TCPServer, audit *auditor) *upgrader {
+	u := &upgrader{
+		serv:          serv,
+		audit:         audit,
+		deviceCache:   storage.GetDeviceCache(),
+		firmwareCache: storage.GetFirmwareCache(),
+		upgradeCache:  storage.GetUpgradeCache(),
+		running:       make(map[string]chan struct{}),
+	}
+	for _, t := range u.upgradeCache.ListTask() {
+		if t.State != model.UpgradeTaskRunning {
+			continue
+		}
+		_ = u.upgradeCache.UpdateTask(t.ID, func(t *model.UpgradeTask) error {
+			t.State = model.UpgradeTaskPaused
+			for _, d := range t.Devices {
+				if d.State == model.DeviceUpgradeSending {
+					d.State = model.DeviceUpgradePending
+					d.SentFragments = 0
+				}
+			}
+			return nil
+		})
+	}
+	return u
+}
+
+// 启动任务的下发协程，已在运行时忽略
+func (u *upgrader) start(id string, who *caller) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if _, ok := u.running[id]; ok {
+		return
+	}
+	wake := make(chan struct{}, 1)
+	u.running[id] = wake
+	go u.run(id, who, wake)
+}
+
+// 唤醒等待批次间隔的下发协程，使其尽快响应状态变化
+func (u *upgrader) wake(id string) {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if wake, ok := u.running[id]; ok {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (u *upgrader) pause(id string) error {
+	err := u.upgradeCache.UpdateTask(id, func(t *model.UpgradeTask) error {
+		if t.State != model.UpgradeTaskRunning {
+			return errors.Wrapf(ErrUpgradeTaskState, "state=%s", t.State)
+		}
+		t.State = model.UpgradeTaskPaused
+		return nil
+	})
+	if err == nil {
+		u.wake(id)
+	}
+	return err
+}
+
+func (u *upgrader) resume(id string, who *caller) error {
+	err := u.upgradeCache.UpdateTask(id, func(t *model.UpgradeTask) error {
+		if t.State != model.UpgradeTaskPaused {
+			return errors.Wrapf(ErrUpgradeTaskState, "state=%s", t.State)
+		}
+		t.State = model.UpgradeTaskRunning
+		return nil
+	})
+	if err == nil {
+		u.start(id, who)
+	}
+	return err
+}
+
+// 终止任务，未下发的设备置为取消，正在下发的设备在当前分包后停止
+func (u *upgrader) abort(id string) error {
+	err := u.upgradeCache.UpdateTask(id, func(t *model.UpgradeTask) error {
+		if t.State != model.UpgradeTaskRunning && t.State != model.UpgradeTaskPaused {
+			return errors.Wrapf(ErrUpgradeTaskState, "state=%s", t.State)
+		}
+		t.State = model.UpgradeTaskAborted
+		for _, d := range t.Devices {
+			if d.State == model.DeviceUpgradePending {
+				d.State = model.DeviceUpgradeCanceled
+				d.UpdatedAt = time.Now()
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		u.wake(id)
+	}
+	return err
+}
+
+func (u *upgrader) run(id string, who *caller, wake chan struct{}) {
+	for {
+		t, err := u.upgradeCache.GetTask(id)
+		if err != nil || t.State != model.UpgradeTaskRunning {
+			if u.exit(id) {
+				return
+			}
+			continue
+		}
+		batch := pendingDevices(t, t.BatchSize)
+		if len(batch) == 0 {
+			_ = u.upgradeCache.UpdateTask(id, func(t *model.UpgradeTask) error {
+				if t.State == model.UpgradeTaskRunning {
+					t.State = model.UpgradeTaskCompleted
+				}
+				return nil
+			})
+			continue
+		}
+		image, err := u.firmwareCache.ReadImage(t.FirmwareID)
+		if err != nil {
+			log.Error().Err(err).Str("task", id).Msg("Fail to read firmware, pause upgrade task")
+			_ = u.pause(id)
+			continue
+		}
+		fw, _ := u.firmwareCache.GetFirmware(t.FirmwareID)
+
+		var wg sync.WaitGroup
+		for _, phone := range batch {
+			phone := phone
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				u.upgradeDevice(who, id, fw, image, phone)
+			}()
+		}
+		wg.Wait()
+
+		if t, err = u.upgradeCache.GetTask(id); err == nil && t.MaxFailures > 0 &&
+			t.CountByState()[model.DeviceUpgradeFailed] > t.MaxFailures {
+			log.Warn().Str("task", id).Int("max_failures", t.MaxFailures).Msg("Too many failures, pause upgrade task")
+			_ = u.pause(id)
+		}
+		if t != nil && t.BatchInterval > 0 && len(pendingDevices(t, 1)) > 0 {
+			select {
+			case <-time.After(t.BatchInterval):
+			case <-wake:
+			}
+		}
+	}
+}
+
+// 任务不再运行时注销下发协程。注销前再次检查状态，避免丢失并发的resume
+func (u *upgrader) exit(id string) bool {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	if t, err := u.upgradeCache.GetTask(id); err == nil && t.State == model.UpgradeTaskRunning {
+		return false
+	}
+	delete(u.running, id)
+	return true
+}
+
+func pendingDevices(t *model.UpgradeTask, n int) []string {
+	var res []string
+	for _, d := range t.Devices {
+		if len(res) >= n {
+			break
+		}
+		if d.State == model.DeviceUpgradePending {
+			res = append(res, d.Phone)
+		}
+	}
+	return res
+}
+
+// 向单个设备下发升级包，逐个分包等待终端应答
+func (u *upgrader) upgradeDevice(who *caller, id string, fw *model.Firmware, image []byte, phone string) {
+	update := func(fn func(d *model.DeviceUpgrade)) {
+		if err := u.upgradeCache.UpdateDevice(id, phone, fn); err != nil {
+			log.Warn().Err(err).Str("task", id).Str("device", phone).Msg("Fail to update device upgrade state")
+		}
+	}
+	fail := func(err error) {
+		update(func(d *model.DeviceUpgrade) {
+			d.State = model.DeviceUpgradeFailed
+			d.Error = err.Error()
+		})
+	}
+
+	d, err := u.deviceCache.GetDeviceByPhone(phone)
+	if err != nil {
+		fail(err)
+		return
+	}
+	if maxLen := model.ManufacturerIDLen(d.VersionDesc); len(fw.ManufacturerID) > maxLen {
+		fail(errors.Wrapf(ErrInvalidFirmware, "manufacturerId is at most %d bytes for %s device", maxLen, d.VersionDesc))
+		return
+	}
+	session, err := storage.GetSession(d.SessionID)
+	if err != nil {
+		fail(err)
+		return
+	}
+	msg := &model.Msg8108{
+		Header:         model.GenMsgHeader(d, 0x8108, 0),
+		UpgradeType:    fw.UpgradeType,
+		ManufacturerID: fw.ManufacturerID,
+		Version:        fw.Version,
+		Packet:         image,
+	}
+	var msgs []model.JT808Msg
+	if body := msg.EncodeBody(); len(body) <= model.MaxBodyLength {
+		msg.Header.SerialNumber = session.GetNextSerialNum()
+		msgs = append(msgs, msg)
+	} else {
+		for _, frag := range model.SplitBody(msg.Header, body, upgradeFragmentSize, session.GetNextSerialNum) {
+			msgs = append(msgs, frag)
+		}
+	}
+	update(func(d *model.DeviceUpgrade) {
+		d.State = model.DeviceUpgradeSending
+		d.SentFragments = 0
+		d.TotalFragments = len(msgs)
+		d.Error = ""
+	})
+
+	for i, m := range msgs {
+		t, err := u.upgradeCache.GetTask(id)
+		if err != nil || t.State == model.UpgradeTaskAborted {
+			update(func(d *model.DeviceUpgrade) { d.State = model.DeviceUpgradeCanceled })
+			return
+		}
+		if t.State == model.UpgradeTaskPaused {
+			// 暂停时停止下发，继续任务后从第一个分包重新下发
+			update(func(d *model.DeviceUpgrade) {
+				d.State = model.DeviceUpgradePending
+				d.SentFragments = 0
+			})
+			return
+		}
+		answer, err := u.requestFragment(session.ID, m)
+		if err != nil {
+			u.audit.record(who, msgs[0].GetHeader(), err)
+			fail(errors.Wrapf(err, "fragment %d/%d", i+1, len(msgs)))
+			return
+		}
+		update(func(d *model.DeviceUpgrade) { d.SentFragments = i + 1 })
+		if _, ok := answer.(*model.Msg0108); ok {
+			break // 终端已上报升级结果，状态已在0x0108处理中更新
+		}
+	}
+	u.audit.record(who, msgs[0].GetHeader(), nil)
+	update(func(d *model.DeviceUpgrade) {
+		if d.State == model.DeviceUpgradeSending {
+			d.State = model.DeviceUpgradeSent
+		}
+	})
+}
+
+// 下发分包并等待应答，未收到应答或应答失败时重试
+func (u *upgrader) requestFragment(sessionID string, m model.JT808Msg) (model.JT808Msg, error) {
+	var err error
+	for i := 0; i < upgradeFragmentRetries; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), defaultAnswerTimeout)
+		var answer model.JT808Msg
+		answer, err = u.serv.Request(ctx, sessionID, m)
+		cancel()
+		if err != nil {
+			if errors.Is(err, server.ErrNoAnswer) {
+				continue
+			}
+			return nil, err
+		}
+		if ack, ok := answer.(*model.Msg0001); ok && ack.Result != uint8(model.ResultSuccess) {
+			err = errors.Errorf("terminal answered result %d", ack.Result)
+			continue
+		}
+		return answer, nil
+	}
+	return nil, err
+}
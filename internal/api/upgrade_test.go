@@ -0,0 +1,140 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func doRequest(t *testing.T, router *gin.Engine, method, path, apiKey string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, bytes.NewReader(body))
+	req.Header.Set(apiKeyHeader, apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestV1_Upgrade(t *testing.T) {
+	router, _ := newTestRouter(t)
+	t.Cleanup(func() { os.RemoveAll("firmware") })
+	phone := "13900000108"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, ManufacturerID: "ABCDE", Status: model.DeviceStatusOffline})
+	t.Cleanup(func() { storage.GetDeviceCache().DelDeviceByPhone(phone) })
+
+	w := doRequest(t, router, http.MethodPost, "/api/v1/firmwares?version=1.0.1", "viewer-key", []byte("image"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	w = doRequest(t, router, http.MethodPost, "/api/v1/firmwares", "admin-key", []byte("image"))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = doRequest(t, router, http.MethodPost, "/api/v1/firmwares?version=1.0.1&manufacturerId=ABCDE", "admin-key", []byte("image"))
+	require.Equal(t, http.StatusCreated, w.Code)
+	fw := &FirmwareDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), fw))
+	assert.Equal(t, 5, fw.Size)
+	assert.Equal(t, "1.0.1", fw.Name)
+
+	body, _ := json.Marshal(&CreateUpgradeRequest{FirmwareID: fw.ID, Phones: []string{phone}})
+	w = doRequest(t, router, http.MethodPost, "/api/v1/upgrades", "admin-key", body)
+	require.Equal(t, http.StatusCreated, w.Code)
+	task := &UpgradeTaskDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), task))
+	assert.Equal(t, defaultUpgradeBatchSize, task.BatchSize)
+	assert.Equal(t, 1, task.Total)
+
+	// 设备离线，下发失败后任务结束
+	require.Eventually(t, func() bool {
+		w = doRequest(t, router, http.MethodGet, "/api/v1/upgrades/"+task.ID, "viewer-key", nil)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), task))
+		return task.State == string(model.UpgradeTaskCompleted)
+	}, time.Second, 10*time.Millisecond)
+	require.Len(t, task.Devices, 1)
+	assert.Equal(t, string(model.DeviceUpgradeFailed), task.Devices[0].State)
+	assert.NotEmpty(t, task.Devices[0].Error)
+
+	w = doRequest(t, router, http.MethodPost, "/api/v1/upgrades/"+task.ID+"/pause", "admin-key", nil)
+	assert.Equal(t, http.StatusConflict, w.Code)
+	w = doRequest(t, router, http.MethodDelete, "/api/v1/firmwares/"+fw.ID, "admin-key", nil)
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	w = doRequest(t, router, http.MethodGet, "/api/v1/firmwares/"+fw.ID, "viewer-key", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), CodeFirmwareNotFound))
+}
+
+func TestV1_UpgradeManufacturerIDLen(t *testing.T) {
+	router, _ := newTestRouter(t)
+	t.Cleanup(func() { os.RemoveAll("firmware") })
+	devices := []*model.Device{
+		{Phone: "13900000109", VersionDesc: model.Version2013, Status: model.DeviceStatusOffline},
+		{Phone: "13900000110", VersionDesc: model.Version2019, Status: model.DeviceStatusOffline},
+	}
+	for _, d := range devices {
+		storage.GetDeviceCache().CacheDevice(d)
+		phone := d.Phone
+		t.Cleanup(func() { storage.GetDeviceCache().DelDeviceByPhone(phone) })
+	}
+
+	w := doRequest(t, router, http.MethodPost, "/api/v1/firmwares?version=1.0.2&manufacturerId=ABCDEFGHIJK", "admin-key", []byte("image"))
+	require.Equal(t, http.StatusCreated, w.Code)
+	fw := &FirmwareDTO{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), fw))
+
+	tests := []struct {
+		name  string
+		phone string
+		code  int
+	}{
+		{"2013 device rejects 11 bytes", "13900000109", http.StatusBadRequest},
+		{"2019 device accepts 11 bytes", "13900000110", http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, _ := json.Marshal(&CreateUpgradeRequest{FirmwareID: fw.ID, Phones: []string{tt.phone}})
+			w := doRequest(t, router, http.MethodPost, "/api/v1/upgrades", "admin-key", body)
+			assert.Equal(t, tt.code, w.Code)
+		})
+	}
+	// 等待已创建的任务结束，避免删除固件时仍在使用
+	require.Eventually(t, func() bool {
+		return !storage.GetUpgradeCache().IsFirmwareInUse(fw.ID)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestUpgrader_PauseInFlight(t *testing.T) {
+	phone := "13900000111"
+	session := &model.Session{ID: "upgrade-pause-session"}
+	storage.StoreSession(session)
+	t.Cleanup(func() { storage.ClearSession(session.ID) })
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, SessionID: session.ID, Status: model.DeviceStatusOnline})
+	t.Cleanup(func() { storage.GetDeviceCache().DelDeviceByPhone(phone) })
+
+	task := &model.UpgradeTask{
+		ID:      newResourceID("up"),
+		State:   model.UpgradeTaskPaused,
+		Devices: []*model.DeviceUpgrade{{Phone: phone, State: model.DeviceUpgradePending}},
+	}
+	storage.GetUpgradeCache().CacheTask(task)
+
+	// 任务已暂停，正在下发的设备在下一个分包前停止并回到待下发
+	u := newUpgrader(nil, nil)
+	u.upgradeDevice(nil, task.ID, &model.Firmware{Version: "1.0.3"}, make([]byte, 3*upgradeFragmentSize), phone)
+
+	got, err := storage.GetUpgradeCache().GetTask(task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.DeviceUpgradePending, got.Devices[0].State)
+	assert.Equal(t, 0, got.Devices[0].SentFragments)
+}
@@ -0,0 +1,420 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/server"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+const v1BasePath = "/api/v1"
+
+// 设备列表的查询参数，见parseDeviceQuery
+var deviceQueryParams = []*queryParam{
+	stringParam("status", "设备状态", "offline", "online", "sleeping"),
+	stringParam("version", "协议版本", "2011", "2013", "2019"),
+	stringParam("plate", "车牌号前缀"),
+	stringParam("phone", "手机号前缀"),
+	timeParam("from", "最近通信时间下限(含)"),
+	timeParam("to", "最近通信时间上限(不含)"),
+	stringParam("region", "行政区划代码前缀"),
+	stringParam("group", "设备分组名"),
+	stringParam("sort", "排序字段", storage.SortByPhone, storage.SortByPlate, storage.SortByLastComTime, storage.SortByStatus),
+	stringParam("order", "排序方向", "asc", "desc"),
+	stringParam("cursor", "上一页返回的nextCursor"),
+	intParam("limit", "每页条数"),
+}
+
+// 单设备指令的查询参数，见sendCommand
+var commandQueryParams = []*queryParam{
+	boolParam("wait", "是否等待终端应答"),
+	intParam("timeoutSec", "等待应答超时秒数，默认10，最大60"),
+}
+
+type v1Handler struct {
+	serv             *server.TCPServer
+	audit            *auditor
+	upgrader         *upgrader
+	deviceCache      *storage.DeviceCache
+	geoCache         *storage.GeoCache
+	groupCache       *storage.GroupCache
+	firmwareCache    *storage.FirmwareCache
+	upgradeCache     *storage.UpgradeCache
+	geofenceCache    *storage.GeofenceCache
+	tripCache        *storage.TripCache
+	drivingRuleCache *storage.DrivingRuleCache
+}
+
+// 注册/api/v1接口，返回接口定义用于生成OpenAPI文档
+func registerV1(authed *gin.RouterGroup, serv *server.TCPServer, audit *auditor) []*operation {
+	h := &v1Handler{
+		serv:             serv,
+		audit:            audit,
+		upgrader:         newUpgrader(serv, audit),
+		deviceCache:      storage.GetDeviceCache(),
+		geoCache:         storage.GetGeoCache(),
+		groupCache:       storage.GetGroupCache(),
+		firmwareCache:    storage.GetFirmwareCache(),
+		upgradeCache:     storage.GetUpgradeCache(),
+		geofenceCache:    storage.GetGeofenceCache(),
+		tripCache:        storage.GetTripCache(),
+		drivingRuleCache: storage.GetDrivingRuleCache(),
+	}
+	ops := []*operation{
+		{
+			Method: http.MethodGet, Path: "/devices", Summary: "查询设备列表", Tag: "device", Role: RoleViewer,
+			Query: deviceQueryParams, Response: DevicePageDTO{}, Handler: h.listDevices,
+		},
+		{
+			Method: http.MethodGet, Path: "/devices/count", Summary: "统计设备数", Tag: "device", Role: RoleViewer,
+			Query: deviceQueryParams[:8], Response: CountDTO{}, Handler: h.countDevices,
+		},
+		{
+			Method: http.MethodGet, Path: "/devices/:phone", Summary: "查询设备信息", Tag: "device", Role: RoleViewer,
+			Response: DeviceDTO{}, Handler: h.getDevice,
+		},
+		{
+			Method: http.MethodGet, Path: "/devices/:phone/location", Summary: "查询设备最新位置", Tag: "device", Role: RoleViewer,
+			Query: []*queryParam{coordQueryParam}, Response: LocationDTO{}, Handler: h.getLocation,
+		},
+		{
+			Method: http.MethodGet, Path: "/devices/:phone/properties", Summary: "查询设备最近上报的终端属性", Tag: "device",
+			Role: RoleViewer, Response: DevicePropertiesDTO{}, Handler: h.getProperties,
+		},
+		{
+			Method: http.MethodPost, Path: "/devices/:phone/params/query", Summary: "下发查询终端参数(0x8104/0x8106)", Tag: "command",
+			Role: RoleOperator, Query: commandQueryParams, Request: QueryParamsRequest{}, Optional: true,
+			Response: CommandResultDTO{}, Handler: h.queryParams,
+		},
+		{
+			Method: http.MethodPut, Path: "/devices/:phone/params", Summary: "下发设置终端参数(0x8103)", Tag: "command",
+			Role: RoleOperator, Query: commandQueryParams, Request: SetParamsRequest{}, Response: CommandResultDTO{},
+			Handler: h.setParams,
+		},
+		{
+			Method: http.MethodPost, Path: "/devices/:phone/control", Summary: "下发终端控制(0x8105)", Tag: "command",
+			Role: RoleOperator, Query: commandQueryParams, Request: ControlCommand{}, Response: CommandResultDTO{},
+			Handler: h.control,
+		},
+		{
+			Method: http.MethodPost, Path: "/devices/:phone/properties/query", Summary: "下发查询终端属性(0x8107)", Tag: "command",
+			Role: RoleOperator, Query: commandQueryParams, Response: CommandResultDTO{}, Handler: h.queryProperties,
+		},
+		{
+			Method: http.MethodGet, Path: "/params/schema", Summary: "查询终端参数定义", Tag: "command", Role: RoleViewer,
+			Response: []*ParamSchemaDTO{}, Handler: h.listParamSchemas,
+		},
+		{
+			Method: http.MethodGet, Path: "/groups", Summary: "查询设备分组列表", Tag: "group", Role: RoleViewer,
+			Response: []*GroupDTO{}, Handler: h.listGroups,
+		},
+		{
+			Method: http.MethodGet, Path: "/groups/:name", Summary: "查询设备分组", Tag: "group", Role: RoleViewer,
+			Response: GroupDTO{}, Handler: h.getGroup,
+		},
+		{
+			Method: http.MethodPut, Path: "/groups/:name", Summary: "创建或更新设备分组", Tag: "group", Role: RoleAdmin,
+			Request: GroupDTO{}, Response: GroupDTO{}, Handler: h.putGroup,
+		},
+		{
+			Method: http.MethodDelete, Path: "/groups/:name", Summary: "删除设备分组", Tag: "group", Role: RoleAdmin,
+			Status: http.StatusNoContent, Handler: h.deleteGroup,
+		},
+		{
+			Method: http.MethodGet, Path: "/groups/:name/devices", Summary: "查询分组成员及状态", Tag: "group", Role: RoleViewer,
+			Query: deviceQueryParams, Response: GroupDevicesDTO{}, Handler: h.listGroupDevices,
+		},
+		{
+			Method: http.MethodPost, Path: "/groups/:name/commands", Summary: "向分组内设备批量下发指令", Tag: "command",
+			Role: RoleOperator, Request: GroupCommandRequest{}, Response: BatchResultDTO{}, Handler: h.sendGroupCommand,
+		},
+	}
+	ops = append(ops, h.upgradeOperations()...)
+	ops = append(ops, h.geofenceOperations()...)
+	ops = append(ops, h.tripOperations()...)
+	ops = append(ops, h.drivingOperations()...)
+	ops = append(ops, h.trackOperations()...)
+	ops = append(ops, h.nearbyOperations()...)
+	registerOperations(authed.Group(v1BasePath), ops)
+	return ops
+}
+
+func (h *v1Handler) listDevices(c *gin.Context) {
+	q, err := parseDeviceQuery(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	page, err := h.deviceCache.QueryDevice(q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newDevicePageDTO(page))
+}
+
+func (h *v1Handler) countDevices(c *gin.Context) {
+	q, err := parseDeviceQuery(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	cnt, err := h.deviceCache.CountDevice(q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, &CountDTO{Count: cnt})
+}
+
+// 查询路径中的设备并校验权限，失败时已写入错误响应
+func (h *v1Handler) device(c *gin.Context) (*model.Device, bool) {
+	d, err := h.deviceCache.GetDeviceByPhone(c.Param("phone"))
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	return d, authorizeDevice(c, d)
+}
+
+func (h *v1Handler) getDevice(c *gin.Context) {
+	if d, ok := h.device(c); ok {
+		c.JSON(http.StatusOK, newDeviceDTO(d))
+	}
+}
+
+func (h *v1Handler) getLocation(c *gin.Context) {
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	datum, ok := datumOf(c)
+	if !ok {
+		return
+	}
+	geo, err := h.geoCache.GetGeoLatestByPhone(d.Phone)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, newLocationDTO(geo, datum))
+}
+
+func (h *v1Handler) getProperties(c *gin.Context) {
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	if d.Properties == nil {
+		abortWithError(c, http.StatusNotFound, CodeNotFound, "device properties not reported")
+		return
+	}
+	c.JSON(http.StatusOK, newDevicePropertiesDTO(d.Properties))
+}
+
+// 请求体可为空，为空或未指定参数时查询全部参数(0x8104)，否则查询指定参数(0x8106)
+func (h *v1Handler) queryParams(c *gin.Context) {
+	req := &QueryParamsRequest{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(req); err != nil {
+			badRequest(c, err)
+			return
+		}
+	}
+	ids, err := req.paramIDs()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	h.sendCommand(c, d, queryParamsBuilder(ids))
+}
+
+func (h *v1Handler) queryProperties(c *gin.Context) {
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	h.sendCommand(c, d, func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+		return &model.Msg8107{Header: model.GenMsgHeader(d, 0x8107, serialNumber)}, nil
+	})
+}
+
+func (h *v1Handler) setParams(c *gin.Context) {
+	req := &SetParamsRequest{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	d, ok := h.device(c)
+	if !ok {
+		return
+	}
+	params, err := req.toDeviceParams(d)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	h.sendCommand(c, d, func(d *model.Device, serialNumber uint16) (model.JT808Msg, error) {
+		return &model.Msg8103{Header: model.GenMsgHeader(d, 0x8103, serialNumber), Parameters: params}, nil
+	})
+}
+
+func (h *v1Handler) listParamSchemas(c *gin.Context) {
+	schemas := model.ParamSchemas()
+	res := make([]*ParamSchemaDTO, 0, len(schemas))
+	for _, s := range schemas {
+		res = append(res, newParamSchemaDTO(s))
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *v1Handler) control(c *gin.Context) {
+	req := &ControlCommand{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	build, err := controlBuilder(req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	if d, ok := h.device(c); ok {
+		h.sendCommand(c, d, build)
+	}
+}
+
+// 下发单设备指令，查询参数wait=true时等待终端应答，超时返回504
+func (h *v1Handler) sendCommand(c *gin.Context, d *model.Device, build msgBuilder) {
+	ctx := c.Request.Context()
+	wait := c.Query("wait") == "true"
+	if wait {
+		timeoutSec, err := strconv.Atoi(c.DefaultQuery("timeoutSec", "0"))
+		if err != nil {
+			badRequest(c, errors.Wrap(err, "invalid timeoutSec"))
+			return
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, answerTimeout(timeoutSec))
+		defer cancel()
+	}
+	res, err := sendOne(ctx, callerOf(c), h.serv, h.audit, d, build, wait)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+func (h *v1Handler) listGroups(c *gin.Context) {
+	p := principalOf(c)
+	res := make([]*GroupDTO, 0)
+	for _, g := range h.groupCache.ListGroup() {
+		if p.CanAccessGroup(g.Name) {
+			res = append(res, newGroupDTO(g))
+		}
+	}
+	c.JSON(http.StatusOK, res)
+}
+
+// 查询路径中的分组并校验权限，失败时已写入错误响应
+func (h *v1Handler) group(c *gin.Context) (*model.DeviceGroup, bool) {
+	name := c.Param("name")
+	if !principalOf(c).CanAccessGroup(name) {
+		abortWithError(c, http.StatusForbidden, CodePermissionDenied, "Permission denied for group "+name)
+		return nil, false
+	}
+	g, err := h.groupCache.GetGroup(name)
+	if err != nil {
+		respondError(c, err)
+		return nil, false
+	}
+	return g, true
+}
+
+func (h *v1Handler) getGroup(c *gin.Context) {
+	if g, ok := h.group(c); ok {
+		c.JSON(http.StatusOK, newGroupDTO(g))
+	}
+}
+
+func (h *v1Handler) putGroup(c *gin.Context) {
+	req := &GroupDTO{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	g := req.toModel(c.Param("name"))
+	h.groupCache.CacheGroup(g)
+	c.JSON(http.StatusOK, newGroupDTO(g))
+}
+
+func (h *v1Handler) deleteGroup(c *gin.Context) {
+	if err := h.groupCache.DelGroup(c.Param("name")); err != nil {
+		respondError(c, err)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *v1Handler) listGroupDevices(c *gin.Context) {
+	g, ok := h.group(c)
+	if !ok {
+		return
+	}
+	q, err := parseDeviceQuery(c)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	q.Group = g.Name
+	page, err := h.deviceCache.QueryDevice(q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	byStatus := make(map[string]int)
+	for _, st := range []model.DeviceStatus{model.DeviceStatusOffline, model.DeviceStatusOnline, model.DeviceStatusSleeping} {
+		st := st
+		cnt, _ := h.deviceCache.CountDevice(&storage.DeviceQuery{Group: g.Name, Status: &st, Filter: q.Filter})
+		byStatus[st.String()] = cnt
+	}
+	c.JSON(http.StatusOK, &GroupDevicesDTO{Page: newDevicePageDTO(page), ByStatus: byStatus})
+}
+
+func (h *v1Handler) sendGroupCommand(c *gin.Context) {
+	g, ok := h.group(c)
+	if !ok {
+		return
+	}
+	req := &GroupCommandRequest{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		badRequest(c, err)
+		return
+	}
+	build, err := req.builder()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	devices, err := h.deviceCache.FilterDevice(&storage.DeviceQuery{
+		Group:  g.Name,
+		Filter: principalOf(c).CanAccessDevice,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, batchSend(c.Request.Context(), callerOf(c), h.serv, h.audit, devices, build))
+}
@@ -0,0 +1,212 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+func newTestRouter(t *testing.T) (*gin.Engine, []*operation) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	a, err := NewAuthenticator(&config.AuthConf{
+		Enable: true,
+		APIKeys: []*config.APIKeyConf{
+			{Name: "dashboard", Key: "viewer-key", Role: "viewer"},
+			{Name: "ops", Key: "admin-key", Role: "admin"},
+		},
+	})
+	require.NoError(t, err)
+	audit, err := newAuditor("")
+	require.NoError(t, err)
+	authed := router.Group("/", a.Middleware())
+	ops := registerV1(authed, nil, audit)
+	registerLegacy(authed, nil, audit)
+	return router, ops
+}
+
+func TestV1_ErrorEnvelope(t *testing.T) {
+	router, _ := newTestRouter(t)
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		apiKey     string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:   "unauthenticated",
+			method: http.MethodGet, path: "/api/v1/devices",
+			wantStatus: http.StatusUnauthorized, wantCode: CodeUnauthenticated,
+		},
+		{
+			name:   "invalid query",
+			method: http.MethodGet, path: "/api/v1/devices?status=lost", apiKey: "viewer-key",
+			wantStatus: http.StatusBadRequest, wantCode: CodeInvalidArgument,
+		},
+		{
+			name:   "device not found",
+			method: http.MethodGet, path: "/api/v1/devices/13800000000", apiKey: "viewer-key",
+			wantStatus: http.StatusNotFound, wantCode: CodeDeviceNotFound,
+		},
+		{
+			name:   "permission denied",
+			method: http.MethodPut, path: "/api/v1/devices/13800000000/params", apiKey: "viewer-key",
+			body:       `{"params":[{"id":1,"value":10}]}`,
+			wantStatus: http.StatusForbidden, wantCode: CodePermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.apiKey != "" {
+				req.Header.Set(apiKeyHeader, tt.apiKey)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			res := &ErrorResponse{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+			require.NotNil(t, res.Error)
+			assert.Equal(t, tt.wantCode, res.Error.Code)
+		})
+	}
+}
+
+func TestGenOpenAPI(t *testing.T) {
+	_, ops := newTestRouter(t)
+	doc := genOpenAPI(v1BasePath, ops)
+
+	_, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.Equal(t, openAPIVersion, doc["openapi"])
+
+	paths := doc["paths"].(map[string]any)
+	device := paths["/api/v1/devices/{phone}"].(map[string]any)
+	get := device["get"].(map[string]any)
+	assert.Equal(t, "getDevicesPhone", get["operationId"])
+	assert.Contains(t, get["responses"], "200")
+
+	schemas := doc["components"].(map[string]any)["schemas"].(map[string]any)
+	for _, name := range []string{"DeviceDTO", "DevicePageDTO", "SetParamsRequest", "ParamItem", "ErrorResponse", "APIError"} {
+		assert.Contains(t, schemas, name)
+	}
+	req := schemas["GroupCommandRequest"].(map[string]any)
+	assert.Equal(t, []string{"type"}, req["required"])
+	typ := req["properties"].(map[string]any)["type"].(map[string]any)
+	assert.Equal(t, []string{"params", "queryParams", "queryProperties", "text", "tracking", "control"}, typ["enum"])
+}
+
+func TestV1_ParamSchema(t *testing.T) {
+	router, _ := newTestRouter(t)
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/params/schema", nil)
+	req.Header.Set(apiKeyHeader, "viewer-key")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var res []*ParamSchemaDTO
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	require.NotEmpty(t, res)
+	assert.Equal(t, "0x0001", res[0].HexID)
+	assert.Equal(t, "heartbeatInterval", res[0].Name)
+	assert.Equal(t, "uint32", res[0].Type)
+}
+
+func TestSetParamsRequest_toDeviceParams(t *testing.T) {
+	tests := []struct {
+		name    string
+		params  []*ParamItem
+		wantErr bool
+	}{
+		{name: "by id and name", params: []*ParamItem{{ID: 0x0001, Value: float64(30)}, {Name: "plate", Value: "粤B12345"}}},
+		{name: "missing id and name", params: []*ParamItem{{Value: float64(30)}}, wantErr: true},
+		{name: "invalid value", params: []*ParamItem{{Name: "videoQuality", Value: float64(11)}}, wantErr: true},
+		{name: "unsupported by version", params: []*ParamItem{{Name: "serverTCPPort", Value: float64(6808)}}, wantErr: true},
+		{name: "unknown id passthrough", params: []*ParamItem{{ID: 0xf001, Value: "0102"}}},
+	}
+	d := &model.Device{Phone: "13800000000", VersionDesc: model.Version2019}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&SetParamsRequest{Params: tt.params}).toDeviceParams(d)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidParam)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, got.Params, len(tt.params))
+		})
+	}
+}
+
+func TestLegacy_SetParams(t *testing.T) {
+	router, _ := newTestRouter(t)
+	phone := "13900000038"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, VersionDesc: model.Version2019, Status: model.DeviceStatusOffline})
+	t.Cleanup(func() { storage.GetDeviceCache().DelDeviceByPhone(phone) })
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr string
+	}{
+		{name: "raw known param", body: `{"params":[{"paramId":19,"paramValue":"3132372e302e302e31","raw":true}]}`, wantErr: "raw value is not allowed"},
+		{name: "unsupported by version", body: `{"params":[{"paramId":24,"paramValue":6808}]}`, wantErr: "not supported by 2019 version terminal"},
+		{name: "valid params", body: `{"params":[{"paramId":1,"paramValue":30},{"paramId":61441,"paramValue":"0102","raw":true}]}`, wantErr: "session"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(t, router, http.MethodPut, "/device/"+phone+"/params", "admin-key", []byte(tt.body))
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			res := map[string]string{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+			assert.Contains(t, res["err"], tt.wantErr)
+		})
+	}
+}
+
+func TestControlCommand_commandWord(t *testing.T) {
+	tests := []struct {
+		name    string
+		cmd     *ControlCommand
+		want    uint8
+		wantErr bool
+	}{
+		{name: "reset", cmd: &ControlCommand{Command: "reset"}, want: model.ControlReset},
+		{name: "upgrade", cmd: &ControlCommand{Command: "upgrade", Upgrade: &model.WirelessUpgradeParam{URL: "http://ota"}}, want: model.ControlWirelessUpgrade},
+		{name: "upgrade without url", cmd: &ControlCommand{Command: "upgrade"}, wantErr: true},
+		{name: "switch back", cmd: &ControlCommand{Command: "connectServer", Connect: &model.ConnectServerParam{Control: model.ConnectToOriginal}}, want: model.ControlConnectServer},
+		{name: "switch without address", cmd: &ControlCommand{Command: "connectServer", Connect: &model.ConnectServerParam{}}, wantErr: true},
+		{name: "upgrade url with separator", cmd: &ControlCommand{Command: "upgrade", Upgrade: &model.WirelessUpgradeParam{URL: "http://ota;evil"}}, wantErr: true},
+		{
+			name:    "connect password with separator",
+			cmd:     &ControlCommand{Command: "connectServer", Connect: &model.ConnectServerParam{Address: "10.0.0.1", DialPassword: "a;b"}},
+			wantErr: true,
+		},
+		{name: "unknown", cmd: &ControlCommand{Command: "selfDestruct"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cmd.commandWord()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidCommand)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+// Package coord 坐标系转换。
+//
+// 终端上报WGS-84坐标，国内地图服务使用GCJ-02(高德、腾讯等)或BD-09(百度)坐标。
+// GCJ-02仅在中国境内加偏，境外与WGS-84相同；反向转换使用迭代逼近，误差小于1e-7度。
+package coord
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+)
+
+type Datum string
+
+const (
+	WGS84 Datum = "wgs84"
+	GCJ02 Datum = "gcj02"
+	BD09  Datum = "bd09"
+)
+
+var ErrUnknownDatum = errors.New("unknown coordinate datum")
+
+const (
+	krasovskyA  = 6378245.0             // 克拉索夫斯基椭球长半轴
+	krasovskyEE = 0.0066934216229659433 // 克拉索夫斯基椭球第一偏心率平方
+	bdXPi       = math.Pi * 3000.0 / 180.0
+)
+
+// 解析坐标系名称，空字符串为WGS-84
+func ParseDatum(s string) (Datum, error) {
+	switch d := Datum(s); d {
+	case "":
+		return WGS84, nil
+	case WGS84, GCJ02, BD09:
+		return d, nil
+	}
+	return "", errors.Wrapf(ErrUnknownDatum, "datum=%s", s)
+}
+
+// 坐标系转换，from与to相同时原样返回
+func Convert(p geo.Point, from, to Datum) geo.Point {
+	if from == to {
+		return p
+	}
+	switch from { // 统一转换为GCJ-02再转为目标坐标系
+	case WGS84:
+		p = WGS84ToGCJ02(p)
+	case BD09:
+		p = BD09ToGCJ02(p)
+	}
+	switch to {
+	case WGS84:
+		return GCJ02ToWGS84(p)
+	case BD09:
+		return GCJ02ToBD09(p)
+	}
+	return p
+}
+
+// 是否在中国境外(粗略的矩形范围)，境外GCJ-02不加偏
+func OutOfChina(p geo.Point) bool {
+	return p.Lng < 72.004 || p.Lng > 137.8347 || p.Lat < 0.8293 || p.Lat > 55.8271
+}
+
+func WGS84ToGCJ02(p geo.Point) geo.Point {
+	if OutOfChina(p) {
+		return p
+	}
+	dLat, dLng := gcjOffset(p)
+	return geo.Point{Lat: p.Lat + dLat, Lng: p.Lng + dLng}
+}
+
+func GCJ02ToWGS84(p geo.Point) geo.Point {
+	if OutOfChina(p) {
+		return p
+	}
+	return refine(p, p, WGS84ToGCJ02)
+}
+
+// 迭代求解forward(res)=target，从初值guess开始
+func refine(guess, target geo.Point, forward func(geo.Point) geo.Point) geo.Point {
+	res := guess
+	for i := 0; i < 10; i++ {
+		f := forward(res)
+		dLat, dLng := f.Lat-target.Lat, f.Lng-target.Lng
+		res.Lat -= dLat
+		res.Lng -= dLng
+		if math.Abs(dLat) < 1e-9 && math.Abs(dLng) < 1e-9 {
+			break
+		}
+	}
+	return res
+}
+
+func GCJ02ToBD09(p geo.Point) geo.Point {
+	x, y := p.Lng, p.Lat
+	z := math.Sqrt(x*x+y*y) + 0.00002*math.Sin(y*bdXPi)
+	theta := math.Atan2(y, x) + 0.000003*math.Cos(x*bdXPi)
+	return geo.Point{Lat: z*math.Sin(theta) + 0.006, Lng: z*math.Cos(theta) + 0.0065}
+}
+
+// 先用近似反算得到初值，再迭代修正
+func BD09ToGCJ02(p geo.Point) geo.Point {
+	x, y := p.Lng-0.0065, p.Lat-0.006
+	z := math.Sqrt(x*x+y*y) - 0.00002*math.Sin(y*bdXPi)
+	theta := math.Atan2(y, x) - 0.000003*math.Cos(x*bdXPi)
+	res := geo.Point{Lat: z * math.Sin(theta), Lng: z * math.Cos(theta)}
+	return refine(res, p, GCJ02ToBD09)
+}
+
+// GCJ-02相对WGS-84的偏移，度
+func gcjOffset(p geo.Point) (float64, float64) {
+	x, y := p.Lng-105.0, p.Lat-35.0
+	dLat := transformLat(x, y)
+	dLng := transformLng(x, y)
+	radLat := p.Lat / 180.0 * math.Pi
+	magic := math.Sin(radLat)
+	magic = 1 - krasovskyEE*magic*magic
+	sqrtMagic := math.Sqrt(magic)
+	dLat = (dLat * 180.0) / ((krasovskyA * (1 - krasovskyEE)) / (magic * sqrtMagic) * math.Pi)
+	dLng = (dLng * 180.0) / (krasovskyA / sqrtMagic * math.Cos(radLat) * math.Pi)
+	return dLat, dLng
+}
+
+func transformLat(x, y float64) float64 {
+	res := -100.0 + 2.0*x + 3.0*y + 0.2*y*y + 0.1*x*y + 0.2*math.Sqrt(math.Abs(x))
+	res += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	res += (20.0*math.Sin(y*math.Pi) + 40.0*math.Sin(y/3.0*math.Pi)) * 2.0 / 3.0
+	res += (160.0*math.Sin(y/12.0*math.Pi) + 320*math.Sin(y*math.Pi/30.0)) * 2.0 / 3.0
+	return res
+}
+
+func transformLng(x, y float64) float64 {
+	res := 300.0 + x + 2.0*y + 0.1*x*x + 0.1*x*y + 0.1*math.Sqrt(math.Abs(x))
+	res += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	res += (20.0*math.Sin(x*math.Pi) + 40.0*math.Sin(x/3.0*math.Pi)) * 2.0 / 3.0
+	res += (150.0*math.Sin(x/12.0*math.Pi) + 300.0*math.Sin(x/30.0*math.Pi)) * 2.0 / 3.0
+	return res
+}
@@ -0,0 +1,54 @@
+package coord
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+)
+
+func TestConvert(t *testing.T) {
+	// 北京天安门
+	wgs := geo.Point{Lat: 39.908692, Lng: 116.397477}
+	tests := []struct {
+		name string
+		from Datum
+		to   Datum
+		in   geo.Point
+		want geo.Point
+	}{
+		{name: "wgs84 to gcj02", from: WGS84, to: GCJ02, in: wgs, want: geo.Point{Lat: 39.910096, Lng: 116.403721}},
+		{name: "wgs84 to bd09", from: WGS84, to: BD09, in: wgs, want: geo.Point{Lat: 39.916435, Lng: 116.410094}},
+		{name: "same datum", from: GCJ02, to: GCJ02, in: wgs, want: wgs},
+		{name: "out of china", from: WGS84, to: GCJ02, in: geo.Point{Lat: 51.5, Lng: -0.12}, want: geo.Point{Lat: 51.5, Lng: -0.12}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Convert(tt.in, tt.from, tt.to)
+			assert.InDelta(t, tt.want.Lat, got.Lat, 1e-5)
+			assert.InDelta(t, tt.want.Lng, got.Lng, 1e-5)
+		})
+	}
+}
+
+func TestConvert_RoundTrip(t *testing.T) {
+	p := geo.Point{Lat: 22.54, Lng: 114.05}
+	for _, d := range []Datum{GCJ02, BD09} {
+		back := Convert(Convert(p, WGS84, d), d, WGS84)
+		assert.InDelta(t, p.Lat, back.Lat, 1e-7, string(d))
+		assert.InDelta(t, p.Lng, back.Lng, 1e-7, string(d))
+	}
+}
+
+func TestParseDatum(t *testing.T) {
+	d, err := ParseDatum("")
+	require.NoError(t, err)
+	assert.Equal(t, WGS84, d)
+	d, err = ParseDatum("bd09")
+	require.NoError(t, err)
+	assert.Equal(t, BD09, d)
+	_, err = ParseDatum("wgs")
+	assert.ErrorIs(t, err, ErrUnknownDatum)
+}
@@ -0,0 +1,70 @@
+// Package geo 经纬度几何计算，距离单位为米。
+//
+// 计算范围为单个城市或线路级别，点线距离使用以参考点为中心的等距柱状投影近似。
+package geo
+
+import "math"
+
+const EarthRadius = 6371008.8 // 地球平均半径，米
+
+type Point struct {
+	Lat float64 `json:"lat"` // 纬度，南纬为负
+	Lng float64 `json:"lng"` // 经度，西经为负
+}
+
+func rad(deg float64) float64 {
+	return deg * math.Pi / 180
+}
+
+// 两点间的球面距离(haversine)
+func Distance(a, b Point) float64 {
+	dLat := rad(b.Lat - a.Lat)
+	dLng := rad(b.Lng - a.Lng)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(rad(a.Lat))*math.Cos(rad(b.Lat))*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// 是否在圆形区域内(含边界)
+func InCircle(p, center Point, radius float64) bool {
+	return Distance(p, center) <= radius
+}
+
+// 是否在矩形区域内(含边界)，leftTop为左上点，rightBottom为右下点
+func InRect(p, leftTop, rightBottom Point) bool {
+	return p.Lat <= leftTop.Lat && p.Lat >= rightBottom.Lat &&
+		p.Lng >= leftTop.Lng && p.Lng <= rightBottom.Lng
+}
+
+// 是否在多边形区域内，射线法，vertices按顺序排列且无需闭合
+func InPolygon(p Point, vertices []Point) bool {
+	if len(vertices) < 3 {
+		return false
+	}
+	inside := false
+	for i, j := 0, len(vertices)-1; i < len(vertices); j, i = i, i+1 {
+		a, b := vertices[i], vertices[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lng < (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// 点到线段ab的最短距离
+func DistanceToSegment(p, a, b Point) float64 {
+	// 以p为原点投影到平面，x为东向，y为北向
+	cosLat := math.Cos(rad(p.Lat))
+	project := func(q Point) (float64, float64) {
+		return rad(q.Lng-p.Lng) * cosLat * EarthRadius, rad(q.Lat-p.Lat) * EarthRadius
+	}
+	ax, ay := project(a)
+	bx, by := project(b)
+	dx, dy := bx-ax, by-ay
+	t := 0.0
+	if l2 := dx*dx + dy*dy; l2 > 0 {
+		t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l2))
+	}
+	return math.Hypot(ax+t*dx, ay+t*dy)
+}
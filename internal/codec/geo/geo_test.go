@@ -0,0 +1,51 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b Point
+		want float64
+	}{
+		{name: "same point", a: Point{Lat: 22.5, Lng: 114}, b: Point{Lat: 22.5, Lng: 114}, want: 0},
+		{name: "one degree latitude", a: Point{Lat: 0, Lng: 0}, b: Point{Lat: 1, Lng: 0}, want: 111195},
+		{name: "beijing to shanghai", a: Point{Lat: 39.9042, Lng: 116.4074}, b: Point{Lat: 31.2304, Lng: 121.4737}, want: 1067000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.InDelta(t, tt.want, Distance(tt.a, tt.b), tt.want*0.002+1)
+		})
+	}
+}
+
+func TestInArea(t *testing.T) {
+	center := Point{Lat: 22.54, Lng: 114.05}
+	assert.True(t, InCircle(Point{Lat: 22.5405, Lng: 114.05}, center, 100))
+	assert.False(t, InCircle(Point{Lat: 22.55, Lng: 114.05}, center, 100))
+
+	leftTop, rightBottom := Point{Lat: 23, Lng: 113}, Point{Lat: 22, Lng: 114}
+	assert.True(t, InRect(Point{Lat: 22.5, Lng: 113.5}, leftTop, rightBottom))
+	assert.False(t, InRect(Point{Lat: 22.5, Lng: 114.5}, leftTop, rightBottom))
+
+	// 凹多边形
+	polygon := []Point{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 4}, {Lat: 4, Lng: 4}, {Lat: 4, Lng: 0}, {Lat: 2, Lng: 2}}
+	assert.True(t, InPolygon(Point{Lat: 1, Lng: 2}, polygon))
+	assert.False(t, InPolygon(Point{Lat: 2, Lng: 1}, polygon))
+	assert.False(t, InPolygon(Point{Lat: 1, Lng: 5}, polygon))
+	assert.False(t, InPolygon(Point{Lat: 1, Lng: 1}, polygon[:2]))
+}
+
+func TestDistanceToSegment(t *testing.T) {
+	a, b := Point{Lat: 0, Lng: 0}, Point{Lat: 0, Lng: 1}
+	// 投影落在线段内，距离约等于纬度差
+	assert.InDelta(t, 1112, DistanceToSegment(Point{Lat: 0.01, Lng: 0.5}, a, b), 2)
+	// 投影落在线段外，取到端点的距离
+	assert.InDelta(t, Distance(Point{Lat: 0, Lng: 1.01}, b), DistanceToSegment(Point{Lat: 0, Lng: 1.01}, a, b), 2)
+	// 退化为点
+	assert.InDelta(t, Distance(Point{Lat: 0.01, Lng: 0}, a), DistanceToSegment(Point{Lat: 0.01, Lng: 0}, a, a), 2)
+}
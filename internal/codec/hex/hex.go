@@ -196,6 +196,14 @@ func ParseTime(timeStr string) time.Time {
 	return timeIns
 }
 
+var deviceZone = time.FixedZone("GMT+8", 8*3600)
+
+// 将时间转为设备时间。设备时间为GMT+8，按UTC保存
+func ToDeviceTime(t time.Time) time.Time {
+	t = t.In(deviceZone)
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+}
+
 func FormatTime(timeIns time.Time) string {
 	year := timeIns.Year()     // 年
 	month := timeIns.Month()   // 月
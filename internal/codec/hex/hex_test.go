@@ -3,6 +3,7 @@ package hex
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -402,3 +403,30 @@ func TestWriteGBK(t *testing.T) {
 		})
 	}
 }
+
+func TestToDeviceTime(t *testing.T) {
+	type args struct {
+		t time.Time
+	}
+	tests := []struct {
+		name string
+		args args
+		want time.Time
+	}{
+		{
+			name: "case1: utc",
+			args: args{t: time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC)},
+			want: time.Date(2024, 5, 2, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "case2: gmt+8",
+			args: args{t: time.Date(2024, 5, 1, 20, 0, 0, 0, time.FixedZone("CST", 8*3600))},
+			want: time.Date(2024, 5, 1, 20, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, ToDeviceTime(tt.args.t))
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package region
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+)
+
+var ErrInvalidBoundary = errors.New("invalid boundary data")
+
+type polygon struct {
+	outer []geo.Point
+	holes [][]geo.Point
+}
+
+type boundary struct {
+	code     string
+	level    int // 1省 2市 3县
+	polygons []*polygon
+	min, max geo.Point
+}
+
+type cell struct {
+	lat, lng int
+}
+
+// 行政区划边界索引，按1度网格分桶。
+//
+// 边界数据为GeoJSON FeatureCollection，要素属性adcode(或code)为6位GB2260代码，
+// 几何为Polygon或MultiPolygon，坐标为[经度, 纬度]。同一位置命中多级行政区时取最低一级。
+type Index struct {
+	datum coord.Datum // 边界数据的坐标系
+	cells map[cell][]*boundary
+	count int
+}
+
+type featureCollection struct {
+	Features []*struct {
+		Properties map[string]any `json:"properties"`
+		Geometry   *struct {
+			Type        string          `json:"type"`
+			Coordinates json.RawMessage `json:"coordinates"`
+		} `json:"geometry"`
+	} `json:"features"`
+}
+
+// 内置的行政区划边界数据，由make region-data从DataV.GeoAtlas下载，坐标系为GCJ-02
+//
+//go:embed data/boundary.geojson
+var defaultBoundary []byte
+
+const defaultBoundaryDatum = coord.GCJ02
+
+// 加载内置的边界数据
+func LoadDefault() (*Index, error) {
+	return LoadGeoJSON(bytes.NewReader(defaultBoundary), defaultBoundaryDatum)
+}
+
+// 加载边界数据文件，datum为数据的坐标系
+func LoadFile(path string, datum coord.Datum) (*Index, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Fail to open boundary file %s", path)
+	}
+	defer f.Close()
+	return LoadGeoJSON(f, datum)
+}
+
+// 从GeoJSON加载边界数据，adcode不是6位代码的要素(如九段线)被忽略
+func LoadGeoJSON(r io.Reader, datum coord.Datum) (*Index, error) {
+	fc := &featureCollection{}
+	if err := json.NewDecoder(r).Decode(fc); err != nil {
+		return nil, errors.Wrap(ErrInvalidBoundary, err.Error())
+	}
+	idx := &Index{datum: datum, cells: make(map[cell][]*boundary)}
+	for _, f := range fc.Features {
+		code := featureCode(f.Properties)
+		if len(code) != 6 || f.Geometry == nil {
+			continue
+		}
+		b := &boundary{code: code, level: codeLevel(code)}
+		var err error
+		switch f.Geometry.Type {
+		case "Polygon":
+			var rings [][][]float64
+			if err = json.Unmarshal(f.Geometry.Coordinates, &rings); err == nil {
+				b.polygons = append(b.polygons, newPolygon(rings))
+			}
+		case "MultiPolygon":
+			var polys [][][][]float64
+			if err = json.Unmarshal(f.Geometry.Coordinates, &polys); err == nil {
+				for _, rings := range polys {
+					b.polygons = append(b.polygons, newPolygon(rings))
+				}
+			}
+		default:
+			continue
+		}
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidBoundary, "adcode=%s, %s", code, err.Error())
+		}
+		idx.add(b)
+	}
+	return idx, nil
+}
+
+func featureCode(props map[string]any) string {
+	for _, key := range []string{"adcode", "code"} {
+		switch v := props[key].(type) {
+		case string:
+			return v
+		case float64:
+			return fmt.Sprintf("%.0f", v)
+		}
+	}
+	return ""
+}
+
+func codeLevel(code string) int {
+	switch {
+	case code[2:] == "0000":
+		return 1
+	case code[4:] == "00":
+		return 2
+	}
+	return 3
+}
+
+func newPolygon(rings [][][]float64) *polygon {
+	p := &polygon{}
+	for i, ring := range rings {
+		points := make([]geo.Point, 0, len(ring))
+		for _, c := range ring {
+			if len(c) >= 2 {
+				points = append(points, geo.Point{Lat: c[1], Lng: c[0]})
+			}
+		}
+		if i == 0 {
+			p.outer = points
+		} else {
+			p.holes = append(p.holes, points)
+		}
+	}
+	return p
+}
+
+func (p *polygon) contains(pt geo.Point) bool {
+	if !geo.InPolygon(pt, p.outer) {
+		return false
+	}
+	for _, h := range p.holes {
+		if geo.InPolygon(pt, h) {
+			return false
+		}
+	}
+	return true
+}
+
+func (idx *Index) add(b *boundary) {
+	b.min = geo.Point{Lat: math.Inf(1), Lng: math.Inf(1)}
+	b.max = geo.Point{Lat: math.Inf(-1), Lng: math.Inf(-1)}
+	for _, p := range b.polygons {
+		for _, pt := range p.outer {
+			b.min.Lat, b.min.Lng = math.Min(b.min.Lat, pt.Lat), math.Min(b.min.Lng, pt.Lng)
+			b.max.Lat, b.max.Lng = math.Max(b.max.Lat, pt.Lat), math.Max(b.max.Lng, pt.Lng)
+		}
+	}
+	if math.IsInf(b.min.Lat, 0) {
+		return
+	}
+	for lat := int(math.Floor(b.min.Lat)); lat <= int(math.Floor(b.max.Lat)); lat++ {
+		for lng := int(math.Floor(b.min.Lng)); lng <= int(math.Floor(b.max.Lng)); lng++ {
+			c := cell{lat: lat, lng: lng}
+			idx.cells[c] = append(idx.cells[c], b)
+		}
+	}
+	idx.count++
+}
+
+// 已加载的行政区数
+func (idx *Index) Len() int {
+	return idx.count
+}
+
+// 坐标(WGS-84)所在的行政区代码，不在任何行政区内返回空字符串
+func (idx *Index) LocateCode(p geo.Point) string {
+	p = coord.Convert(p, coord.WGS84, idx.datum)
+	var found *boundary
+	for _, b := range idx.cells[cell{lat: int(math.Floor(p.Lat)), lng: int(math.Floor(p.Lng))}] {
+		if found != nil && b.level <= found.level {
+			continue
+		}
+		if p.Lat < b.min.Lat || p.Lat > b.max.Lat || p.Lng < b.min.Lng || p.Lng > b.max.Lng {
+			continue
+		}
+		for _, poly := range b.polygons {
+			if poly.contains(p) {
+				found = b
+				break
+			}
+		}
+	}
+	if found == nil {
+		return ""
+	}
+	return found.code
+}
+
+var (
+	defaultIndex *Index
+	indexMutex   sync.RWMutex
+)
+
+// 设置全局边界索引，为nil时关闭逆地理编码
+func SetIndex(idx *Index) {
+	indexMutex.Lock()
+	defer indexMutex.Unlock()
+	defaultIndex = idx
+}
+
+// 坐标(WGS-84)所在的行政区，未加载边界数据或不在任何行政区内返回nil
+func Locate(p geo.Point) *AdministrativeRegion {
+	indexMutex.RLock()
+	idx := defaultIndex
+	indexMutex.RUnlock()
+	if idx == nil {
+		return nil
+	}
+	code := idx.LocateCode(p)
+	if code == "" {
+		return nil
+	}
+	return Parse(code)
+}
@@ -0,0 +1,93 @@
+package region
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+)
+
+// 简化的矩形边界：省包含市，市包含区，区内有一个空洞；另一个区为两块区域
+const testBoundary = `{
+	"type": "FeatureCollection",
+	"features": [
+		{"type": "Feature", "properties": {"adcode": 440000, "name": "广东省"},
+			"geometry": {"type": "Polygon", "coordinates": [[[109, 20], [118, 20], [118, 26], [109, 26], [109, 20]]]}},
+		{"type": "Feature", "properties": {"adcode": "440300", "name": "深圳市"},
+			"geometry": {"type": "Polygon", "coordinates": [[[113.7, 22.4], [114.7, 22.4], [114.7, 22.9], [113.7, 22.9], [113.7, 22.4]]]}},
+		{"type": "Feature", "properties": {"adcode": 440305, "name": "南山区"},
+			"geometry": {"type": "Polygon", "coordinates": [
+				[[113.8, 22.45], [114.0, 22.45], [114.0, 22.65], [113.8, 22.65], [113.8, 22.45]],
+				[[113.85, 22.5], [113.9, 22.5], [113.9, 22.55], [113.85, 22.55], [113.85, 22.5]]
+			]}},
+		{"type": "Feature", "properties": {"adcode": 440304, "name": "福田区"},
+			"geometry": {"type": "MultiPolygon", "coordinates": [
+				[[[114.0, 22.5], [114.1, 22.5], [114.1, 22.6], [114.0, 22.6], [114.0, 22.5]]],
+				[[[114.5, 22.5], [114.6, 22.5], [114.6, 22.6], [114.5, 22.6], [114.5, 22.5]]]
+			]}},
+		{"type": "Feature", "properties": {"adcode": "100000_JD"},
+			"geometry": {"type": "MultiPolygon", "coordinates": []}}
+	]
+}`
+
+func TestIndex_LocateCode(t *testing.T) {
+	idx, err := LoadGeoJSON(strings.NewReader(testBoundary), coord.WGS84)
+	require.NoError(t, err)
+	assert.Equal(t, 4, idx.Len())
+
+	tests := []struct {
+		name  string
+		point geo.Point
+		want  string
+	}{
+		{name: "district", point: geo.Point{Lat: 22.6, Lng: 113.95}, want: "440305"},
+		{name: "hole falls back to city", point: geo.Point{Lat: 22.52, Lng: 113.87}, want: "440300"},
+		{name: "multi polygon", point: geo.Point{Lat: 22.55, Lng: 114.55}, want: "440304"},
+		{name: "city only", point: geo.Point{Lat: 22.8, Lng: 114.3}, want: "440300"},
+		{name: "province only", point: geo.Point{Lat: 23.1, Lng: 113.3}, want: "440000"},
+		{name: "outside", point: geo.Point{Lat: 39.9, Lng: 116.4}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, idx.LocateCode(tt.point))
+		})
+	}
+}
+
+func TestIndex_Datum(t *testing.T) {
+	idx, err := LoadGeoJSON(strings.NewReader(testBoundary), coord.GCJ02)
+	require.NoError(t, err)
+	// WGS-84坐标在深圳一带约偏移500米，靠近边界的点转换后落入南山区
+	wgs := coord.GCJ02ToWGS84(geo.Point{Lat: 22.6, Lng: 113.801})
+	assert.Equal(t, "440305", idx.LocateCode(wgs))
+}
+
+func TestLoadDefault(t *testing.T) {
+	idx, err := LoadDefault()
+	require.NoError(t, err)
+	assert.Equal(t, coord.GCJ02, idx.datum)
+}
+
+func TestLocate(t *testing.T) {
+	assert.Nil(t, Locate(geo.Point{Lat: 22.6, Lng: 113.95}))
+
+	idx, err := LoadGeoJSON(strings.NewReader(testBoundary), coord.WGS84)
+	require.NoError(t, err)
+	SetIndex(idx)
+	t.Cleanup(func() { SetIndex(nil) })
+	ar := Locate(geo.Point{Lat: 22.6, Lng: 113.95})
+	require.NotNil(t, ar)
+	assert.Equal(t, "广东省深圳市南山区", ar.FullName)
+}
+
+func TestLoadGeoJSON_Invalid(t *testing.T) {
+	_, err := LoadGeoJSON(strings.NewReader(`{"features": [`), coord.WGS84)
+	assert.ErrorIs(t, err, ErrInvalidBoundary)
+	_, err = LoadGeoJSON(strings.NewReader(`{"features": [{"properties": {"adcode": 440000},
+		"geometry": {"type": "Polygon", "coordinates": [1, 2]}}]}`), coord.WGS84)
+	assert.ErrorIs(t, err, ErrInvalidBoundary)
+}
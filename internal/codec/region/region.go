@@ -1,6 +1,9 @@
+// Package region GB2260行政区划解析，以及按行政区划边界的离线逆地理编码。
 package region
 
 import (
+	"strings"
+
 	gb2260 "github.com/cn/GB2260.go"
 )
 
@@ -9,19 +12,48 @@ const CodeNotFound = "NotFound"
 var gb = gb2260.NewGB2260("")
 
 type AdministrativeRegion struct {
-	Code string `json:"code"` // The six-digit number of the specific administrative division.
-	Name string `json:"name"` // The Chinese name of the specific administrative division.
+	Code     string `json:"code"`               // The six-digit number of the specific administrative division.
+	Name     string `json:"name"`               // The Chinese name of the specific administrative division.
+	Province string `json:"province,omitempty"` // 省级名称
+	City     string `json:"city,omitempty"`     // 地级名称，直辖市及省直辖县级行政区为空
+	District string `json:"district,omitempty"` // 县级名称
+	FullName string `json:"fullName"`           // xx省[xx市][xx区]
 }
 
-// todo: name解析为xx省[xx市][xx区]
+// 直辖市、省直辖县级行政区在GB2260中的地级占位名称，不出现在完整名称中
+var placeholderNames = map[string]bool{
+	"市辖区":         true,
+	"县":           true,
+	"省直辖县级行政区划":   true,
+	"自治区直辖县级行政区划": true,
+}
 
+// 解析GB2260行政区划代码，名称解析为xx省[xx市][xx区]
 func Parse(code string) *AdministrativeRegion {
 	division := gb.Get(code)
 	ar := &AdministrativeRegion{Code: code}
 	if division == nil {
 		ar.Name = CodeNotFound
-	} else {
-		ar.Name = division.Name
+		ar.FullName = CodeNotFound
+		return ar
+	}
+	ar.Name = division.Name
+
+	var names []string
+	if p := division.Province(); p != nil {
+		ar.Province = p.Name
+		names = append(names, p.Name)
+	}
+	if !division.IsProvince() {
+		if c := division.Prefecture(); c != nil && !placeholderNames[c.Name] {
+			ar.City = c.Name
+			names = append(names, c.Name)
+		}
+	}
+	if division.IsCountry() {
+		ar.District = division.Name
+		names = append(names, division.Name)
 	}
+	ar.FullName = strings.Join(names, "")
 	return ar
 }
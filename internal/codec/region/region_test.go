@@ -38,3 +38,42 @@ func TestParse(t *testing.T) {
 		})
 	}
 }
+
+func TestParse_FullName(t *testing.T) {
+	tests := []struct {
+		code string
+		want AdministrativeRegion
+	}{
+		{
+			code: "610322",
+			want: AdministrativeRegion{Code: "610322", Name: "凤翔县", Province: "陕西省", City: "宝鸡市", District: "凤翔县", FullName: "陕西省宝鸡市凤翔县"},
+		},
+		{
+			code: "130400",
+			want: AdministrativeRegion{Code: "130400", Name: "邯郸市", Province: "河北省", City: "邯郸市", FullName: "河北省邯郸市"},
+		},
+		{
+			code: "130000",
+			want: AdministrativeRegion{Code: "130000", Name: "河北省", Province: "河北省", FullName: "河北省"},
+		},
+		{
+			// 直辖市的区，跳过"市辖区"
+			code: "110101",
+			want: AdministrativeRegion{Code: "110101", Name: "东城区", Province: "北京市", District: "东城区", FullName: "北京市东城区"},
+		},
+		{
+			// 省直辖县级行政区
+			code: "429004",
+			want: AdministrativeRegion{Code: "429004", Name: "仙桃市", Province: "湖北省", District: "仙桃市", FullName: "湖北省仙桃市"},
+		},
+		{
+			code: "999322",
+			want: AdministrativeRegion{Code: "999322", Name: CodeNotFound, FullName: CodeNotFound},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			assert.Equal(t, tt.want, *Parse(tt.code))
+		})
+	}
+}
@@ -99,7 +99,7 @@ func configsBannerTxt() (*asset, error) {
 	return a, nil
 }
 
-var _configsDefaultYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x6c\xd0\xc1\x4a\x03\x31\x10\x06\xe0\xfb\x3e\xc5\x90\x7b\x77\xb7\x82\x18\x72\x6b\x69\x15\x44\xb0\x28\x3e\x40\x1a\x67\xd3\xe8\x34\xb3\x24\xb3\xa5\xfa\xf4\x12\xa3\xb8\xa2\xc7\x7c\xff\x64\x7e\x12\x62\x6f\x1a\x00\xc7\x31\x33\xe1\x36\xda\x3d\xa1\x01\x49\x13\x36\x00\x43\xf8\x43\x63\x0a\x51\x56\xf9\x36\x73\x34\x30\x58\xca\x05\x89\xfd\x1d\x9e\x90\x0c\xa8\xcd\x76\xfd\x74\xa3\xaa\x6d\x42\x42\x27\x9c\xde\x0c\xa8\xb6\x23\xf6\xb9\xfb\x4a\xae\x43\x59\xa9\x5e\x44\xf7\x7a\x91\x31\x9d\x30\x2d\x3c\xb7\xc4\xbe\x0c\x1c\xed\xf9\x31\xbc\xe3\xfd\xf0\xc0\x44\x21\x7a\x03\x97\x7d\xe5\xb5\x75\xaf\xd3\x98\x67\xc9\xf2\x42\xd7\x68\xe5\xe7\x17\xae\x9a\xa6\xae\x2d\x8f\x8b\xf6\xf8\x4f\x5b\x69\x1a\x39\x49\x99\x00\x10\x37\xee\xca\x01\x94\xee\x75\xaf\x3e\x6d\x7a\x9e\xd9\xb2\xda\x41\xe4\x07\x7b\x5d\x70\x6f\x63\xac\x45\x00\xf8\xfb\xb7\xbe\xc3\x9d\x95\x83\x01\xe5\x38\x0e\xc1\xe7\xae\x62\x2b\x67\x51\xcd\x47\x00\x00\x00\xff\xff\x4d\x9b\x24\x20\x81\x01\x00\x00")
+var _configsDefaultYaml = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x94\x56\x5d\x4f\xdb\x4a\x1a\xbe\xe7\x57\x8c\xcc\x6d\x0b\x0e\xa5\x67\x53\xef\x55\x29\xa7\x55\xcf\x56\x3d\x59\xe8\xd1\x5e\x1c\xf5\x62\xb0\x27\xc9\x34\x8e\x27\x1d\x8f\x21\xec\x6a\xa5\x40\xf9\x2a\x10\x12\x75\x21\x14\x1a\x0a\xa8\x7c\xb5\xdd\x42\xb6\x45\x6c\xc8\x47\xf3\x63\xea\xb1\x9d\x2b\xfe\xc2\x6a\xec\x04\x82\x80\x95\x8e\x40\x8a\xfc\xbe\xcf\xbc\xf3\x3e\xcf\xfb\x61\xeb\x24\xa6\x74\x01\xa0\x12\xc3\x24\x3a\xfa\xd9\x80\x23\x3a\x52\x00\xa3\x16\xea\x02\x20\x8a\xaf\x98\x52\x14\x1b\xec\xbe\xf9\x8b\x49\x0c\x05\x44\xa1\x6e\x0a\x9c\x4e\x62\x4f\xd0\x28\xd2\x15\x20\x0d\xfe\x3c\xf0\xdb\x23\x29\xb0\x0d\x62\x8a\x54\x46\xe8\xb8\x02\xa4\x9e\x5e\x9d\xc4\xcc\xde\x96\xe7\x21\x16\x21\xa5\x17\x2c\x2c\x87\x6f\x9b\x88\x8e\x22\x7a\x3b\x46\x7a\x74\x12\x13\x80\x24\x4c\x0f\xe3\xbf\xa3\x5f\xa3\x43\x44\xd7\xb1\x11\x53\xc0\x5d\x39\x30\x0f\x40\x35\x61\xa5\xcc\x0e\x4f\xa8\x2f\x1c\xb8\xee\xc7\x3a\x0f\xfc\xa9\xab\x2b\x08\x2b\xc8\x19\x30\x79\xcd\x6d\xe2\xa6\x14\xa1\x4c\x20\x00\x60\x6a\x2a\x22\x1e\x80\x14\x96\xc3\xb2\xd4\xb6\x3d\xd3\xcd\x96\x59\x02\xdd\xc0\xfe\xbe\xc0\xf7\x26\x81\x14\xee\xef\xbf\x13\x40\x2c\xad\xe3\x58\x28\xb0\xc5\x19\xbb\x30\xca\xe1\x0b\xe3\xb5\xa1\x64\xf9\x5e\x80\x88\xd1\x94\x7a\x2d\x20\xe4\xa7\x33\x02\x0d\x23\xa0\x03\x00\xba\x5c\x93\xb6\x33\x02\x59\x5c\x01\x92\x4a\x8c\x28\x8e\x99\xbd\x81\xb1\x87\xa5\x99\x38\x6f\x22\xd3\xc4\xc4\x08\x02\x10\x8b\x8d\x10\xcb\xd0\xfe\x6a\x21\x0b\x09\xad\x15\xf0\x53\xbf\xef\x19\xa3\x98\xa1\x67\x38\x89\x88\xc5\x14\x10\x92\xcd\xd6\x85\x2f\x05\xf2\xdc\x7e\x27\x30\x93\x51\x44\xa3\x3a\x19\x8b\x10\x1d\xab\xa2\xca\x23\x3a\x51\x13\xe2\x36\x1d\x27\x71\x4b\xd9\x24\x4c\x3f\x20\x86\x81\x54\x86\x89\x61\x8a\x98\xb2\x2c\x5f\xe3\x89\x20\xfa\x38\xe2\xbb\x7d\x67\x1c\x1a\x9a\x19\x87\x89\x8e\x4b\x5b\xc9\x50\x04\xb5\xc7\x9a\x7e\xe1\xb8\x9b\x04\xdd\x80\xcf\xac\x79\x27\xd3\xce\xea\x89\xb7\x3f\xd1\x26\xdb\xd3\xc9\xc6\x3f\x9b\x34\x63\x43\x90\xa1\x27\x7e\x7a\x41\x5b\x9d\x1b\x07\x2c\x6a\xb2\x76\x02\x4c\x37\x2f\x6b\xdd\xee\x75\x00\x54\x44\x59\xab\x81\xdb\x52\x0b\x93\xd9\x1b\xb4\x56\x8f\x4a\x7d\xc1\x01\x48\xa0\xf1\xff\x87\x4b\xa0\xf1\x00\xa7\xea\x18\x19\xec\x01\x6c\x81\xa5\x16\xcb\x97\x16\xa6\xe8\x41\xe0\x43\x94\x75\xa6\x80\x35\x64\x30\xcc\xc6\x1f\x62\xa4\x6b\x22\xbe\x11\x1c\x82\x29\x1c\x1c\xb8\x6f\xb1\x78\xe7\x01\x8a\x74\x02\xb5\xc7\x06\x43\x74\x14\xea\x0a\x08\x25\x45\xf7\x53\x92\x1e\x8f\x50\xc2\x88\x4a\xf4\x9b\xd8\xc6\x11\xd4\x10\xbd\xd0\x3a\xa8\x01\xa3\x96\xc9\x90\x16\xa1\x24\x8d\x91\xa9\x80\xdf\x9f\x8b\x12\xe4\x8e\xec\xc6\xb6\xbb\x3e\xe5\x1d\x6f\x7a\xf5\x3a\xdf\x98\xf5\xb6\xb7\x79\xb1\xc4\x37\x32\x67\xb5\x45\xbb\x5c\x71\x3f\x56\x9c\xd5\x13\xbb\x9c\xb5\x1b\xdb\x76\xb5\x2a\xfe\xeb\x2b\xce\xc6\xae\x53\xc9\x0b\x40\x30\x12\xbf\x4b\x21\xb9\xc7\xff\xeb\x0d\x4b\xcf\x45\x29\x28\x54\xc5\xac\xdf\x90\x20\x32\xb4\x14\xc1\x86\x18\x2c\x9d\xa8\x50\x8f\x13\x93\x29\xfd\x77\x42\xad\xc1\xc3\x86\x89\x54\x8b\x76\x4e\x8b\xa8\x13\x56\xd1\xd3\x1b\xd7\x02\x00\x26\x4c\xa6\x74\x34\x04\x19\x26\x0a\x08\x75\x01\x00\x85\xa0\x57\x33\x00\xdd\xc0\x29\x7e\xe2\xb5\x0c\xcf\x1f\x09\x6a\x3e\x23\xaf\xf4\xca\x5d\x3e\x70\x0a\xa7\xcd\x57\x75\x67\x61\xd7\x29\xbe\x86\x5a\x12\x1b\xce\xc6\xab\xe6\x9a\x20\xca\x8f\x4e\xf9\x4e\xc1\x59\x3f\x6e\x16\xbf\xc2\x14\x76\x3f\x1f\xf1\xdc\x07\xfe\x3a\xcb\xe7\xb7\x79\x63\x3a\x08\xd6\x2e\xe7\x5f\xd0\x78\x5b\xde\xb6\x3e\xff\x68\xed\x33\x92\x32\xa5\x5b\xa2\xc9\x14\x20\xa5\xd3\x69\xe9\x16\xa0\x44\xd4\x4e\x22\x29\x44\x21\x23\x54\xba\x05\x62\x94\x58\x29\x11\x40\x8a\xea\x08\xb1\xdb\x50\x7a\xfe\x4f\xa1\x29\x00\x2f\xc6\x5a\xf3\x29\xf4\x50\x29\x62\xe7\x9d\x07\x00\x36\x4d\x0b\xd1\x73\x03\xb4\x34\xcc\x9e\x90\xd8\xc5\x1a\xf7\x2d\xed\x75\xad\x12\x42\x35\x6c\x40\x26\xee\x1e\x8b\x99\xe1\x7e\xb1\xe0\xee\x47\x1e\x7b\x8d\x65\xfe\xee\x3d\xdf\xc8\x3b\x5b\xb3\xee\xfa\x54\xb3\xfa\xd6\x3b\xdc\x69\x3d\x7e\xab\x2a\xc0\xc7\x82\x5e\x10\x53\x5f\xc8\x7d\xa0\x17\x8c\x68\xf2\xbd\x2e\xd1\xab\x31\xb1\xa8\x40\x37\x70\xf7\xaa\x6e\xa5\xd1\xcc\xcc\xf0\x62\xc9\xcd\xcf\xb8\xb5\x82\xbb\x35\x71\xc3\x06\x14\xeb\x0c\xd2\xf6\xc0\x89\x0c\xbc\xed\x45\x67\xb9\xc1\x17\x2b\x7c\xee\x8d\xf7\xfd\xd4\x5d\x59\x7c\x84\xc8\x2f\xc3\xbf\x3e\x3d\xab\x2d\x7a\x7b\x13\xee\xf1\x16\xff\xcf\x7b\x27\xb3\x0f\x35\x95\x68\xc8\x2e\x57\x1e\x0d\xf4\xf5\xfd\x24\xdb\xd5\x0f\xee\xd6\xc4\x79\xc3\xda\xf5\x86\xbb\x7c\xc0\x67\xa6\xdd\xfa\x61\x10\xc5\x59\x29\x39\xd9\x43\x3f\x0d\x0d\x32\x2b\xa9\x00\xc9\x67\xe0\xdf\x19\x20\x0a\xb3\x76\xf5\xc4\x5d\x9f\x3a\x27\xdb\x6a\xe3\x84\x22\xc6\x64\x69\x86\xe7\xbe\x7a\xf5\x03\xaf\x71\xca\xbf\xbc\xe5\x93\x07\x67\xb5\x45\x77\xf9\xc0\xae\x2c\xb5\x8c\x47\x35\x3e\x5b\x11\x59\xfa\x8f\xee\xe4\x29\xaf\x4d\x3a\x5f\x76\xf9\xcc\x1a\x9f\xde\x15\xe0\xda\xbf\xf8\xcc\x57\xe7\x28\xe7\xee\xbf\xe1\x73\xff\x75\x0a\x25\x3e\x57\x0a\x2e\xbd\x5e\x1d\x0d\x8b\x6a\xfa\x19\xb4\xb7\x0b\x13\x1b\x84\x18\x83\x50\xf4\xd7\x3d\x59\xbc\x71\x1a\x1b\xee\xca\x1a\xdf\xf9\xe8\xac\x94\xce\x6a\x8b\xb2\xb7\x7d\xe0\xee\x54\xec\x72\xd6\x29\x4f\xbb\xf9\x99\x2e\x00\x5e\x5a\x50\xc7\x6c\x5c\x90\x78\xf4\x74\x78\x38\x10\xc2\x3b\x3e\x68\xce\xe6\xbc\xc6\xac\x53\xdd\xf1\x55\xcb\xf2\xfc\x9c\xb3\x55\x73\xd7\xa7\xdc\xc9\x53\xbb\x9c\xe5\xb9\x49\xbb\xbc\xe4\x6d\x2f\xba\x07\x0b\x3f\x32\x13\xfc\xdd\xb1\xb3\x95\xfb\x91\x99\x68\x7e\xfc\xde\xfc\x78\xe2\x6d\x0b\xa1\xf9\xdc\x8c\xf3\xde\x9f\x8d\xa5\x4d\xbe\xbf\x10\x44\xb6\xab\xd9\x80\x72\xa7\x62\x37\x6c\x01\xe8\xbf\x44\x14\x20\x45\x75\x18\x13\x95\x10\xbf\x0a\x70\xb6\x66\xbd\xc3\x12\xcf\x2f\xd9\xd5\xac\xf3\xee\xd8\x29\x94\x9c\x62\xc6\x29\x94\xec\x7a\xd6\xad\x1f\xfe\x19\x68\x94\xa4\x14\xc0\x73\x9f\x6e\xb8\x88\xa2\x17\x48\x65\xbf\x19\x51\x9c\x46\x5a\xb0\x42\x44\x95\x7d\xb2\x62\xf0\x0f\xd7\xed\x7a\xd6\x99\x2b\xb8\xd5\x9c\x5b\xf9\x37\xaf\xec\xd9\xe5\x8a\x1c\x30\xf7\xcf\x27\xb1\x31\x0c\x19\xd2\x75\xcc\xc4\x9a\xec\x07\xdd\x40\x16\x92\xcc\xed\x38\x85\x2f\xa2\x92\xd5\x39\xf7\xf3\x91\x53\xfc\x64\x97\xe7\x9d\xf9\x5d\x9e\xfd\xec\xbc\xdd\x14\xec\xfd\x35\x19\xc0\xda\xef\xca\xe1\x14\x12\x39\xdc\x91\xfd\x62\x95\x97\xec\xf2\xbc\x5d\xce\xd8\xe5\x4f\x4e\xf1\xb5\xb3\x32\x27\xc4\x3e\x5d\x68\xae\x1e\xbb\xeb\x53\x4e\x31\xd3\xfc\xfc\x96\x9f\x7e\xe3\x1b\xb3\xcd\xcc\x26\xaf\xec\x9d\xd5\x16\x13\xc9\xde\xb8\xa8\xeb\x95\xc0\x0f\x2d\x66\x51\x34\x9c\x40\x63\xed\xe8\x2d\x5e\xab\x27\xcd\xd5\x63\xef\x64\x9a\xbf\xce\x3a\x45\x7f\x3d\xad\x1d\x04\x46\x77\x7d\xca\x2e\xcf\x07\xeb\xcc\xdd\x7f\x73\x6d\xd8\x08\x34\x59\x10\xf4\x4a\xc8\x7c\x9d\xe7\x97\xfe\x58\x48\x8d\xe2\x51\x6c\xc4\x86\x2c\xb1\xe2\xba\x81\xe0\x96\x2b\xd9\xdf\xf7\xbd\x93\xe9\x66\x66\xf3\x47\x66\xc2\x2d\x7c\xe5\xf3\xdf\x82\xa6\x3a\xd7\x97\x67\x57\xec\x7a\x96\xe7\x5b\x42\xf3\xdc\xa4\xb3\x52\x92\xd3\xb2\x7c\xf7\xee\x6d\xff\x67\xe0\x52\xa7\x77\x10\xb8\xda\x62\x17\x25\x08\xf5\x09\x46\x42\xcf\xf3\xcf\x20\x53\x78\x06\x2d\x2a\xde\x18\x86\xf8\x88\x10\xeb\x6b\xff\x8d\xef\x57\x89\xc1\xb0\x61\x11\xcb\x1c\x0c\x48\x3c\x8b\x53\x64\xc6\x89\x78\x75\x87\xfa\xfb\xe5\x4e\xac\x06\xb1\x3e\x7e\x15\xd6\x17\x0e\x5f\x82\x25\xb1\x31\x84\x4c\xd6\x71\x61\xdf\x65\x3f\x4c\x47\x20\x4d\x60\x23\x76\x01\xe9\xf4\x9f\xa7\xfc\x37\x48\x0d\x81\xc2\xd1\xa8\xf8\xcc\x06\xdd\x40\xee\x09\x25\x92\xbd\xf1\xae\xff\x0d\x00\xc8\x86\x7e\xd4\x1a\x0c\x00\x00")
 
 func configsDefaultYamlBytes() ([]byte, error) {
 	return bindataRead(
@@ -114,7 +114,7 @@ func configsDefaultYaml() (*asset, error) {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "configs/default.yaml", size: 385, mode: os.FileMode(420), modTime: time.Unix(1678936086, 0)}
+	info := bindataFileInfo{name: "configs/default.yaml", size: 3098, mode: os.FileMode(420), modTime: time.Unix(1792343085, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
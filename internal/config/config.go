@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/mix-go/xfmt"
 	"github.com/pkg/errors"
@@ -43,15 +44,30 @@ type logConf struct {
 }
 
 type serverConf struct {
-	Name   string      `yaml:"name"`
-	Port   *servPort   `yaml:"port"`
-	Banner *servBanner `yaml:"banner"`
+	Name    string       `yaml:"name"`
+	Port    *servPort    `yaml:"port"`
+	Banner  *servBanner  `yaml:"banner"`
+	Session *SessionConf `yaml:"session"`
+	Limit   *LimitConf   `yaml:"limit"`
+	TLS     *TLSConf     `yaml:"tls"`
+
+	ProxyProtocol *ProxyProtocolConf `yaml:"proxyProtocol"`
+	Tracing       *TracingConf       `yaml:"tracing"`
+	Auth          *AuthConf          `yaml:"auth"`
+	DrivingRule   *DrivingRuleConf   `yaml:"drivingRule"`
+	Coordinate    string             `yaml:"coordinate"` // API返回坐标的默认坐标系: wgs84 / gcj02 / bd09，可通过查询参数coord指定
+	Region        *RegionConf        `yaml:"region"`
+	Track         *TrackConf         `yaml:"track"`
+	Quality       *QualityConf       `yaml:"quality"`
 }
 
 type servPort struct {
-	TCPPort  string `yaml:"tcpPort"`
-	UDPPort  string `yaml:"udpPort"`
-	HTTPPort string `yaml:"httpPort"`
+	TCPPort    string `yaml:"tcpPort"`
+	TCPTLSPort string `yaml:"tcpTlsPort"` // 终端TLS端口，为空不开启
+	UDPPort    string `yaml:"udpPort"`
+	HTTPPort   string `yaml:"httpPort"`
+	HTTPSPort  string `yaml:"httpsPort"` // HTTP API TLS端口，为空不开启
+	GRPCPort   string `yaml:"grpcPort"`  // gRPC API端口，为空不开启，配置TLS时使用API证书
 }
 
 type servBanner struct {
@@ -59,6 +75,110 @@ type servBanner struct {
 	BannerPath string `yaml:"bannerPath"`
 }
 
+// session下行写队列配置
+type SessionConf struct {
+	OutboundQueueSize int           `yaml:"outboundQueueSize"` // 下行队列长度
+	WriteTimeout      time.Duration `yaml:"writeTimeout"`      // 写conn超时时间
+	EnqueueTimeout    time.Duration `yaml:"enqueueTimeout"`    // 队列满时入队等待时间(overflowPolicy=block)
+	OverflowPolicy    string        `yaml:"overflowPolicy"`    // 队列满时的处理策略: block / drop / close
+}
+
+// tcp连接限制配置，各项为0表示不限制
+type LimitConf struct {
+	MaxConnections      int           `yaml:"maxConnections"`      // 最大连接数
+	MaxConnectionsPerIP int           `yaml:"maxConnectionsPerIP"` // 单个来源IP的最大连接数
+	HandshakeTimeout    time.Duration `yaml:"handshakeTimeout"`    // 连接建立后须在此时间内完成注册鉴权
+	ReadIdleTimeout     time.Duration `yaml:"readIdleTimeout"`     // 读空闲超时，超时未收到任何帧则断开连接
+	MsgRateLimit        float64       `yaml:"msgRateLimit"`        // 单连接每秒最大上行消息数，超出的帧被丢弃
+	MsgRateBurst        int           `yaml:"msgRateBurst"`        // 单连接上行消息突发数
+}
+
+// TLS配置，终端TLS端口和HTTPS端口共用服务端证书
+type TLSConf struct {
+	Enable            bool          `yaml:"enable"`
+	CertFile          string        `yaml:"certFile"`          // 服务端证书
+	KeyFile           string        `yaml:"keyFile"`           // 服务端私钥
+	ClientCAFile      string        `yaml:"clientCaFile"`      // 客户端证书CA，配置后开启双向认证
+	RequireClientCert bool          `yaml:"requireClientCert"` // 终端是否必须提供客户端证书
+	IdentityField     string        `yaml:"identityField"`     // 终端证书中映射为设备手机号的字段: cn / dns
+	APIClientAuth     bool          `yaml:"apiClientAuth"`     // HTTP API是否要求客户端证书
+	ReloadInterval    time.Duration `yaml:"reloadInterval"`    // 证书热加载的检查间隔，0表示不热加载
+}
+
+// HAProxy PROXY协议配置，部署在四层负载均衡之后时用于获取终端真实地址
+type ProxyProtocolConf struct {
+	Enable         bool          `yaml:"enable"`
+	HeaderTimeout  time.Duration `yaml:"headerTimeout"`  // 读取PROXY头的超时时间
+	TrustedProxies []string      `yaml:"trustedProxies"` // 可信的负载均衡地址(CIDR或IP)，只读取来自这些地址的PROXY头，为空表示不信任任何来源
+}
+
+// OpenTelemetry链路追踪配置，未开启时不导出
+type TracingConf struct {
+	Enable      bool    `yaml:"enable"`
+	Endpoint    string  `yaml:"endpoint"`    // OTLP/HTTP collector地址，host:port
+	Insecure    bool    `yaml:"insecure"`    // 是否使用http而非https
+	ServiceName string  `yaml:"serviceName"` // 上报的服务名
+	SampleRatio float64 `yaml:"sampleRatio"` // 采样比例，(0, 1]
+}
+
+// HTTP API鉴权配置，未开启时所有请求均以admin角色处理，启动时会打印警告
+type AuthConf struct {
+	Enable   bool          `yaml:"enable"`
+	APIKeys  []*APIKeyConf `yaml:"apiKeys"`  // 静态API Key，通过请求头 X-API-Key 传递
+	JWT      *JWTConf      `yaml:"jwt"`      // JWT，通过请求头 Authorization: Bearer 传递，必须包含exp
+	AuditLog string        `yaml:"auditLog"` // 下发指令审计日志文件，为空时写入服务日志
+}
+
+type APIKeyConf struct {
+	Name   string   `yaml:"name"` // 调用方名称，记录在审计日志中
+	Key    string   `yaml:"key"`
+	Role   string   `yaml:"role"`   // viewer / operator / admin
+	Groups []string `yaml:"groups"` // 可访问的设备分组，为空表示不限制
+}
+
+// JWT使用HS256签名，claims中的sub/role/groups分别对应调用方名称、角色和可访问的设备分组
+type JWTConf struct {
+	Secret string `yaml:"secret"` // 为空时不开启JWT鉴权
+	Issuer string `yaml:"issuer"` // 不为空时校验iss
+}
+
+// 离线逆地理编码配置，边界数据为GeoJSON，要素属性adcode为GB2260代码
+type RegionConf struct {
+	Enable       bool   `yaml:"enable"`
+	BoundaryFile string `yaml:"boundaryFile"` // 行政区划边界文件，为空使用内置边界数据
+	Datum        string `yaml:"datum"`        // 边界文件的坐标系: wgs84 / gcj02 / bd09，内置数据为gcj02
+}
+
+// 历史轨迹存储配置，轨迹按设备和日期保存为JSON Lines文件
+type TrackConf struct {
+	Enable        bool   `yaml:"enable"`
+	Dir           string `yaml:"dir"`           // 存储目录
+	RetentionDays int    `yaml:"retentionDays"` // 保留天数，0表示不清理
+}
+
+// GNSS数据质量过滤配置，不合格的位置汇报不参与行程、围栏、驾驶行为等分析，原始数据仍写入历史轨迹
+type QualityConf struct {
+	Enable        bool    `yaml:"enable"`
+	Action        string  `yaml:"action"`        // flag: 标记后仍更新最新位置; drop: 不更新最新位置，只写入历史轨迹
+	RejectUnfixed bool    `yaml:"rejectUnfixed"` // 过滤未定位或经纬度为0的点
+	MinSatellites int     `yaml:"minSatellites"` // 最少卫星数，0不判断，终端未上报附加信息0x31时不判断
+	MaxSpeed      float64 `yaml:"maxSpeed"`      // 与上一个有效点之间的最高平均速度，km/h，0不判断
+	MaxFutureSkew int     `yaml:"maxFutureSkew"` // 定位时间超前服务器时间的上限，秒，0不判断
+	MaxPastSkew   int     `yaml:"maxPastSkew"`   // 定位时间落后服务器时间的上限，秒，0不判断
+}
+
+// 平台侧超速、疲劳驾驶判断的默认规则，字段含义与单位同终端参数0x0055-0x005B，值为0表示不判断该项
+type DrivingRuleConf struct {
+	Enable                     bool   `yaml:"enable"`
+	MaxSpeed                   uint32 `yaml:"maxSpeed"`                   // 最高速度，km/h
+	OverspeedDuration          uint32 `yaml:"overspeedDuration"`          // 超速持续时间，秒
+	ContinuousDrivingThreshold uint32 `yaml:"continuousDrivingThreshold"` // 连续驾驶时间门限，秒
+	DailyDrivingThreshold      uint32 `yaml:"dailyDrivingThreshold"`      // 当天累计驾驶时间门限，秒
+	MinRestDuration            uint32 `yaml:"minRestDuration"`            // 最小休息时间，秒
+	MaxParkingDuration         uint32 `yaml:"maxParkingDuration"`         // 最长停车时间，秒
+	OverspeedWarningDiff       uint16 `yaml:"overspeedWarningDiff"`       // 超速预警差值，0.1km/h
+}
+
 type clientConf struct {
 	Name         string            `yaml:"name"`
 	Conn         *connection       `yaml:"conn"`
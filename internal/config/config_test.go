@@ -1,9 +1,15 @@
 package config
 
 import (
+	"bytes"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"github.com/spf13/viper"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestLoad(t *testing.T) {
@@ -43,6 +49,45 @@ func TestLoad(t *testing.T) {
 						Enable:     true,
 						BannerPath: "./configs/banner.txt",
 					},
+					Session: &SessionConf{
+						OutboundQueueSize: 64,
+						WriteTimeout:      10 * time.Second,
+						EnqueueTimeout:    3 * time.Second,
+						OverflowPolicy:    "block",
+					},
+					Limit: &LimitConf{
+						MaxConnections:      10000,
+						MaxConnectionsPerIP: 100,
+						HandshakeTimeout:    30 * time.Second,
+						ReadIdleTimeout:     5 * time.Minute,
+						MsgRateLimit:        50,
+						MsgRateBurst:        100,
+					},
+					TLS: &TLSConf{
+						Enable:         false,
+						CertFile:       "configs/certs/server.crt",
+						KeyFile:        "configs/certs/server.key",
+						IdentityField:  "cn",
+						ReloadInterval: time.Minute,
+					},
+					ProxyProtocol: &ProxyProtocolConf{
+						Enable:         false,
+						HeaderTimeout:  5 * time.Second,
+						TrustedProxies: []string{},
+					},
+					Tracing: &TracingConf{
+						Enable:      false,
+						Endpoint:    "localhost:4318",
+						Insecure:    true,
+						ServiceName: "jt808-server-go",
+						SampleRatio: 1,
+					},
+					Auth: &AuthConf{
+						Enable:   false,
+						APIKeys:  []*APIKeyConf{},
+						JWT:      &JWTConf{},
+						AuditLog: "./logs/audit.log",
+					},
 				},
 			},
 		},
@@ -54,3 +99,23 @@ func TestLoad(t *testing.T) {
 		})
 	}
 }
+
+// 内置的默认配置需与configs/default.yaml一致，修改配置后需重新生成asset.go(make compile)
+func TestEmbeddedDefaultConf(t *testing.T) {
+	embedded, err := Asset(DefaultServConfPath)
+	require.NoError(t, err)
+	source, err := os.ReadFile(filepath.Join("..", "..", DefaultServConfPath))
+	require.NoError(t, err)
+	assert.Equal(t, string(source), string(embedded))
+
+	v := viper.New()
+	v.SetConfigType("yaml")
+	require.NoError(t, v.ReadConfig(bytes.NewBuffer(embedded)))
+	c := &Config{}
+	require.NoError(t, v.Unmarshal(c))
+	require.NotNil(t, c.Server.Track)
+	assert.True(t, c.Server.Track.Enable)
+	assert.NotNil(t, c.Server.Session)
+	assert.NotNil(t, c.Server.Region)
+	assert.NotNil(t, c.Server.Quality)
+}
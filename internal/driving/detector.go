@@ -0,0 +1,238 @@
+// Package driving 平台侧超速和疲劳驾驶判断。
+//
+// 部分终端不能可靠地上报超速、疲劳驾驶报警位，平台按终端参数0x0055-0x005B的语义，
+// 根据位置汇报中的速度、ACC状态和时间判断超速、超速预警、疲劳驾驶、当天累计驾驶超时和超时停车，
+// 以与终端报警相同的alarm事件广播，报警标志位中终端已上报的位不再重复广播。
+//
+// 默认规则来自配置server.drivingRule，设备可单独覆盖部分项。
+package driving
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/event"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+	"github.com/fakeyanss/jt808-server-go/internal/trip"
+)
+
+type deviceState struct {
+	last       time.Time
+	lastMoving bool
+	received   bool
+
+	overspeedSince time.Time // 超过最高速度的开始时间，未超速为零值
+	drivingSince   time.Time // 本次连续驾驶的开始时间，休息足够后为零值
+	restSince      time.Time // 本次休息的开始时间，行驶中为零值
+	parkingSince   time.Time // ACC开且静止的开始时间
+	day            string    // 当天累计驾驶时间所属日期，设备时间
+	dailyDriving   time.Duration
+	alarm          uint32 // 当前平台侧判断的报警标志位
+}
+
+// 设备的驾驶状态
+type Status struct {
+	Alarm             uint32        `json:"alarm"`             // 平台侧判断的报警标志位
+	ContinuousDriving time.Duration `json:"continuousDriving"` // 本次连续驾驶时长
+	DailyDriving      time.Duration `json:"dailyDriving"`      // 当天累计驾驶时长
+}
+
+type detector struct {
+	mutex   sync.Mutex
+	enable  bool
+	rule    model.DrivingRule
+	states  map[string]*deviceState
+	ruleFor func(phone string, base model.DrivingRule) model.DrivingRule
+}
+
+var defaultDetector = &detector{
+	states: make(map[string]*deviceState),
+	ruleFor: func(phone string, base model.DrivingRule) model.DrivingRule {
+		return storage.GetDrivingRuleCache().GetOverride(phone).Apply(base)
+	},
+}
+
+// 按配置设置默认规则，conf为nil或未开启时不做判断
+func Configure(conf *config.DrivingRuleConf) {
+	defaultDetector.mutex.Lock()
+	defer defaultDetector.mutex.Unlock()
+	if conf == nil {
+		defaultDetector.enable = false
+		return
+	}
+	defaultDetector.enable = conf.Enable
+	defaultDetector.rule = model.DrivingRule{
+		MaxSpeed:                   conf.MaxSpeed,
+		OverspeedDuration:          conf.OverspeedDuration,
+		ContinuousDrivingThreshold: conf.ContinuousDrivingThreshold,
+		DailyDrivingThreshold:      conf.DailyDrivingThreshold,
+		MinRestDuration:            conf.MinRestDuration,
+		MaxParkingDuration:         conf.MaxParkingDuration,
+		OverspeedWarningDiff:       conf.OverspeedWarningDiff,
+	}
+}
+
+// 是否开启平台侧判断
+func Enabled() bool {
+	defaultDetector.mutex.Lock()
+	defer defaultDetector.mutex.Unlock()
+	return defaultDetector.enable
+}
+
+// 默认规则
+func DefaultRule() model.DrivingRule {
+	defaultDetector.mutex.Lock()
+	defer defaultDetector.mutex.Unlock()
+	return defaultDetector.rule
+}
+
+// 设备生效的规则
+func RuleOf(phone string) model.DrivingRule {
+	return defaultDetector.ruleFor(phone, DefaultRule())
+}
+
+// 判断位置汇报，终端未上报的报警位以alarm事件广播，返回平台侧判断的全部报警标志位
+func Evaluate(phone string, alarmSign uint32, dg *model.DeviceGeo) uint32 {
+	if !Enabled() {
+		return 0
+	}
+	alarm := defaultDetector.evaluate(phone, RuleOf(phone), dg)
+	if bits := alarm &^ alarmSign; bits != 0 {
+		event.Publish(&event.Event{Type: event.TypeAlarm, Phone: phone, Time: dg.Time, Geo: dg, AlarmSign: bits, Server: true})
+	}
+	return alarm
+}
+
+// 设备当前的驾驶状态，没有汇报过位置时返回nil
+func StatusOf(phone string) *Status {
+	defaultDetector.mutex.Lock()
+	defer defaultDetector.mutex.Unlock()
+	st, ok := defaultDetector.states[phone]
+	if !ok {
+		return nil
+	}
+	res := &Status{Alarm: st.alarm, DailyDriving: st.dailyDriving}
+	if !st.drivingSince.IsZero() {
+		end := st.last
+		if !st.restSince.IsZero() {
+			end = st.restSince
+		}
+		res.ContinuousDriving = end.Sub(st.drivingSince)
+	}
+	return res
+}
+
+// 清除设备的驾驶状态
+func Forget(phone string) {
+	defaultDetector.mutex.Lock()
+	defer defaultDetector.mutex.Unlock()
+	delete(defaultDetector.states, phone)
+}
+
+func seconds(v uint32) time.Duration {
+	return time.Duration(v) * time.Second
+}
+
+func (d *detector) evaluate(phone string, rule model.DrivingRule, dg *model.DeviceGeo) uint32 {
+	if dg.Geo == nil {
+		return 0
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	st, ok := d.states[phone]
+	if !ok {
+		st = &deviceState{}
+		d.states[phone] = st
+	}
+	cur := dg.Time
+	if st.received && !cur.After(st.last) {
+		return st.alarm // 乱序的汇报不改变状态
+	}
+
+	var speed float64
+	if dg.Drive != nil {
+		speed = dg.Drive.Speed
+	}
+	acc := dg.Geo.ACCStatus == 1
+	moving := acc && speed >= trip.MovingSpeed
+	minRest := seconds(rule.MinRestDuration)
+
+	var alarm uint32
+
+	// 超速及预警
+	if rule.MaxSpeed > 0 && speed > float64(rule.MaxSpeed) {
+		if st.overspeedSince.IsZero() {
+			st.overspeedSince = cur
+		}
+		if cur.Sub(st.overspeedSince) >= seconds(rule.OverspeedDuration) {
+			alarm |= model.AlarmOverspeed
+		}
+	} else {
+		st.overspeedSince = time.Time{}
+	}
+	if rule.MaxSpeed > 0 && rule.OverspeedWarningDiff > 0 && alarm&model.AlarmOverspeed == 0 &&
+		speed > float64(rule.MaxSpeed)-float64(rule.OverspeedWarningDiff)/10 {
+		alarm |= model.AlarmOverspeedWarning
+	}
+
+	// 连续驾驶与休息，休息达到最小休息时间后重新计算连续驾驶时间，汇报间隔达到最小休息时间也视为休息
+	if moving {
+		rested := false
+		if !st.restSince.IsZero() {
+			rested = cur.Sub(st.restSince) >= minRest
+		} else if st.received && minRest > 0 && cur.Sub(st.last) >= minRest {
+			rested = true
+		}
+		if st.drivingSince.IsZero() || rested {
+			st.drivingSince = cur
+		}
+		st.restSince = time.Time{}
+	} else {
+		if st.restSince.IsZero() {
+			st.restSince = cur
+		}
+		if cur.Sub(st.restSince) >= minRest {
+			st.drivingSince = time.Time{}
+		}
+	}
+	if rule.ContinuousDrivingThreshold > 0 && !st.drivingSince.IsZero() {
+		end := cur
+		if !st.restSince.IsZero() {
+			end = st.restSince
+		}
+		if end.Sub(st.drivingSince) >= seconds(rule.ContinuousDrivingThreshold) {
+			alarm |= model.AlarmFatigue
+		}
+	}
+
+	// 当天累计驾驶，只累计前后两次汇报都在行驶且间隔不超过trip.GapDuration的时间
+	if day := cur.Format("2006-01-02"); day != st.day {
+		st.day = day
+		st.dailyDriving = 0
+	}
+	if moving && st.lastMoving && cur.Sub(st.last) <= trip.GapDuration {
+		st.dailyDriving += cur.Sub(st.last)
+	}
+	if rule.DailyDrivingThreshold > 0 && st.dailyDriving >= seconds(rule.DailyDrivingThreshold) {
+		alarm |= model.AlarmDailyDrivingLimit
+	}
+
+	// 超时停车，ACC开且静止
+	if acc && !moving {
+		if st.parkingSince.IsZero() {
+			st.parkingSince = cur
+		}
+		if rule.MaxParkingDuration > 0 && cur.Sub(st.parkingSince) >= seconds(rule.MaxParkingDuration) {
+			alarm |= model.AlarmParkingTimeout
+		}
+	} else {
+		st.parkingSince = time.Time{}
+	}
+
+	st.last, st.lastMoving, st.received = cur, moving, true
+	st.alarm = alarm
+	return alarm
+}
@@ -0,0 +1,158 @@
+package driving
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/event"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+const phone = "13800000045"
+
+var t0 = time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+func newGeo(sec int, speed float64, acc uint8) *model.DeviceGeo {
+	return &model.DeviceGeo{
+		Phone: phone,
+		Geo:   &model.GeoMeta{LocationStatus: 1, ACCStatus: acc},
+		Drive: &model.Drive{Speed: speed},
+		Time:  t0.Add(time.Duration(sec) * time.Second),
+	}
+}
+
+func newTestDetector() *detector {
+	return &detector{states: make(map[string]*deviceState)}
+}
+
+func TestDetector_Overspeed(t *testing.T) {
+	d := newTestDetector()
+	rule := model.DrivingRule{MaxSpeed: 100, OverspeedDuration: 10, OverspeedWarningDiff: 50}
+	tests := []struct {
+		name  string
+		geo   *model.DeviceGeo
+		alarm uint32
+	}{
+		{name: "normal", geo: newGeo(0, 80, 1), alarm: 0},
+		{name: "warning", geo: newGeo(5, 97, 1), alarm: model.AlarmOverspeedWarning},
+		{name: "overspeed not lasting", geo: newGeo(10, 110, 1), alarm: model.AlarmOverspeedWarning},
+		{name: "overspeed", geo: newGeo(20, 105, 1), alarm: model.AlarmOverspeed},
+		{name: "out of order", geo: newGeo(15, 80, 1), alarm: model.AlarmOverspeed},
+		{name: "slow down", geo: newGeo(25, 90, 1), alarm: 0},
+		{name: "overspeed restarts", geo: newGeo(30, 110, 1), alarm: model.AlarmOverspeedWarning},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.alarm, d.evaluate(phone, rule, tt.geo))
+		})
+	}
+}
+
+// 从from到to分钟(含)每5分钟一次汇报
+func series(from, to int, speed float64, acc uint8) []*model.DeviceGeo {
+	var geos []*model.DeviceGeo
+	for m := from; m <= to; m += 5 {
+		geos = append(geos, newGeo(m*60, speed, acc))
+	}
+	return geos
+}
+
+func concat(parts ...[]*model.DeviceGeo) []*model.DeviceGeo {
+	var res []*model.DeviceGeo
+	for _, p := range parts {
+		res = append(res, p...)
+	}
+	return res
+}
+
+func TestDetector_Fatigue(t *testing.T) {
+	rule := model.DrivingRule{ContinuousDrivingThreshold: 3600, MinRestDuration: 600, DailyDrivingThreshold: 5400}
+	tests := []struct {
+		name  string
+		geos  []*model.DeviceGeo
+		alarm uint32
+	}{
+		{name: "driving", geos: series(0, 55, 60, 1), alarm: 0},
+		{name: "continuous driving", geos: series(0, 60, 60, 1), alarm: model.AlarmFatigue},
+		{
+			name:  "short rest does not reset",
+			geos:  concat(series(0, 30, 60, 1), series(35, 35, 0, 1), series(40, 65, 60, 1)),
+			alarm: model.AlarmFatigue,
+		},
+		{
+			name:  "rest resets",
+			geos:  concat(series(0, 30, 60, 1), series(35, 45, 0, 0), series(50, 90, 60, 1)),
+			alarm: 0,
+		},
+		{
+			name:  "fatigue lasts until rest is enough",
+			geos:  concat(series(0, 60, 60, 1), series(65, 70, 0, 0)),
+			alarm: model.AlarmFatigue,
+		},
+		{
+			name:  "report gap counts as rest",
+			geos:  concat(series(0, 30, 60, 1), series(45, 80, 60, 1)),
+			alarm: 0,
+		},
+		{
+			name:  "daily driving",
+			geos:  concat(series(0, 45, 60, 1), series(50, 55, 0, 0), series(60, 105, 60, 1)),
+			alarm: model.AlarmDailyDrivingLimit,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDetector()
+			var alarm uint32
+			for _, g := range tt.geos {
+				alarm = d.evaluate(phone, rule, g)
+			}
+			assert.Equal(t, tt.alarm, alarm)
+		})
+	}
+}
+
+func TestDetector_Parking(t *testing.T) {
+	d := newTestDetector()
+	rule := model.DrivingRule{MaxParkingDuration: 300}
+	assert.Zero(t, d.evaluate(phone, rule, newGeo(0, 0, 1)))
+	assert.Zero(t, d.evaluate(phone, rule, newGeo(200, 0, 1)))
+	assert.Equal(t, model.AlarmParkingTimeout, d.evaluate(phone, rule, newGeo(300, 0, 1)))
+	assert.Zero(t, d.evaluate(phone, rule, newGeo(310, 0, 0)), "acc off is not parking timeout")
+	assert.Zero(t, d.evaluate(phone, rule, newGeo(700, 0, 1)))
+}
+
+func TestDrivingRuleOverride(t *testing.T) {
+	base := model.DrivingRule{MaxSpeed: 120, OverspeedDuration: 10}
+	maxSpeed := uint32(80)
+	o := &model.DrivingRuleOverride{MaxSpeed: &maxSpeed}
+	assert.Equal(t, model.DrivingRule{MaxSpeed: 80, OverspeedDuration: 10}, o.Apply(base))
+	var none *model.DrivingRuleOverride
+	assert.Equal(t, base, none.Apply(base))
+}
+
+func TestEvaluate_Publish(t *testing.T) {
+	Configure(&config.DrivingRuleConf{Enable: true, MaxSpeed: 100, OverspeedWarningDiff: 50})
+	t.Cleanup(func() {
+		Configure(nil)
+		Forget(phone)
+	})
+	sub := event.Subscribe(func(e *event.Event) bool { return e.Phone == phone }, 0)
+	defer sub.Close()
+
+	assert.Equal(t, model.AlarmOverspeed, Evaluate(phone, 0, newGeo(0, 110, 1)))
+	e := <-sub.C
+	assert.Equal(t, event.TypeAlarm, e.Type)
+	assert.Equal(t, model.AlarmOverspeed, e.AlarmSign)
+	assert.True(t, e.Server)
+
+	// 终端已上报超速时不重复广播
+	assert.Equal(t, model.AlarmOverspeed, Evaluate(phone, model.AlarmOverspeed, newGeo(1, 110, 1)))
+	assert.Empty(t, sub.C)
+
+	Configure(nil)
+	assert.Zero(t, Evaluate(phone, 0, newGeo(2, 110, 1)))
+}
@@ -0,0 +1,132 @@
+// Package event 进程内的终端事件广播，供gRPC订阅等场景使用。
+//
+// Publish不会阻塞pipeline，订阅方消费过慢时丢弃事件并计入监控指标。
+package event
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+type Type string
+
+const (
+	TypeLocation Type = "location" // 位置汇报
+	TypeAlarm    Type = "alarm"    // 位置汇报中报警标志位非0
+	TypeStatus   Type = "status"   // 设备状态变化
+	TypeGeofence Type = "geofence" // 平台侧电子围栏事件
+)
+
+// 电子围栏事件的动作
+type FenceAction string
+
+const (
+	FenceEnter     FenceAction = "enter"     // 进入区域
+	FenceExit      FenceAction = "exit"      // 离开区域
+	FenceOverspeed FenceAction = "overspeed" // 区域或路段内超速
+	FenceOffRoute  FenceAction = "offRoute"  // 偏离路线
+	FenceOnRoute   FenceAction = "onRoute"   // 回到路线
+)
+
+const defaultBufferSize = 256
+
+type Event struct {
+	Type       Type             `json:"type"`
+	Phone      string           `json:"phone"`
+	Time       time.Time        `json:"time"`
+	Geo        *model.DeviceGeo `json:"geo,omitempty"`        // location、alarm事件携带
+	AlarmSign  uint32           `json:"alarmSign,omitempty"`  // alarm事件携带
+	Server     bool             `json:"server,omitempty"`     // alarm事件是否由平台侧规则产生
+	Status     string           `json:"status,omitempty"`     // status事件的新状态
+	PrevStatus string           `json:"prevStatus,omitempty"` // status事件的原状态
+	Fence      *FenceInfo       `json:"fence,omitempty"`      // geofence事件携带
+}
+
+type FenceInfo struct {
+	ID       uint32      `json:"id"`
+	Name     string      `json:"name"`
+	Action   FenceAction `json:"action"`
+	MaxSpeed float64     `json:"maxSpeed,omitempty"` // overspeed事件的限速，km/h
+}
+
+// 订阅过滤条件，返回false的事件不会投递
+type Filter func(e *Event) bool
+
+type Subscription struct {
+	C      <-chan *Event
+	ch     chan *Event
+	filter Filter
+	once   sync.Once
+}
+
+// 取消订阅并关闭C
+func (s *Subscription) Close() {
+	s.once.Do(func() {
+		defaultBus.unsubscribe(s)
+		close(s.ch)
+	})
+}
+
+type bus struct {
+	mutex sync.RWMutex
+	subs  map[*Subscription]struct{}
+}
+
+var defaultBus = &bus{subs: make(map[*Subscription]struct{})}
+
+// 订阅事件，filter为nil时接收全部事件，bufSize<=0时使用默认缓冲
+func Subscribe(filter Filter, bufSize int) *Subscription {
+	if bufSize <= 0 {
+		bufSize = defaultBufferSize
+	}
+	ch := make(chan *Event, bufSize)
+	s := &Subscription{C: ch, ch: ch, filter: filter}
+	defaultBus.mutex.Lock()
+	defaultBus.subs[s] = struct{}{}
+	defaultBus.mutex.Unlock()
+	return s
+}
+
+func (b *bus) unsubscribe(s *Subscription) {
+	b.mutex.Lock()
+	delete(b.subs, s)
+	b.mutex.Unlock()
+}
+
+// 广播事件，订阅方缓冲已满时丢弃
+func Publish(e *Event) {
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	defaultBus.mutex.RLock()
+	defer defaultBus.mutex.RUnlock()
+	for s := range defaultBus.subs {
+		if s.filter != nil && !s.filter(e) {
+			continue
+		}
+		select {
+		case s.ch <- e:
+		default:
+			metrics.EventsDropped.WithLabelValues(string(e.Type)).Inc()
+		}
+	}
+}
+
+// 设备状态变化时广播status事件
+func PublishStatus(phone string, prev, cur model.DeviceStatus) {
+	if prev == cur {
+		return
+	}
+	Publish(&Event{Type: TypeStatus, Phone: phone, Status: cur.String(), PrevStatus: prev.String()})
+}
+
+// 广播位置汇报，报警标志位非0时额外广播alarm事件
+func PublishLocation(phone string, alarmSign uint32, geo *model.DeviceGeo) {
+	Publish(&Event{Type: TypeLocation, Phone: phone, Geo: geo})
+	if alarmSign != 0 {
+		Publish(&Event{Type: TypeAlarm, Phone: phone, Geo: geo, AlarmSign: alarmSign})
+	}
+}
@@ -0,0 +1,342 @@
+// Package export 将历史轨迹导出为GPX、KML、GeoJSON和CSV文件。
+//
+// 轨迹点逐个写入，文件头尾在创建和关闭时写出，导出大时间范围时不需要把轨迹全部加载到内存。
+// GPX、KML和GeoJSON只包含已定位的点，CSV包含全部点。
+package export
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/region"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+type Format string
+
+const (
+	FormatGPX     Format = "gpx"
+	FormatKML     Format = "kml"
+	FormatGeoJSON Format = "geojson"
+	FormatCSV     Format = "csv"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+var contentTypes = map[Format]string{
+	FormatGPX:     "application/gpx+xml",
+	FormatKML:     "application/vnd.google-earth.kml+xml",
+	FormatGeoJSON: "application/geo+json",
+	FormatCSV:     "text/csv; charset=utf-8",
+}
+
+func ParseFormat(s string) (Format, error) {
+	f := Format(s)
+	if _, ok := contentTypes[f]; !ok {
+		return "", errors.Wrapf(ErrUnknownFormat, "format=%s", s)
+	}
+	return f, nil
+}
+
+func (f Format) ContentType() string {
+	return contentTypes[f]
+}
+
+// 导出文件的描述信息
+type Meta struct {
+	Phone string
+	Plate string
+	From  time.Time
+	To    time.Time
+	Datum coord.Datum // 导出坐标的坐标系
+}
+
+func (m *Meta) title() string {
+	if m.Plate != "" {
+		return m.Phone + " " + m.Plate
+	}
+	return m.Phone
+}
+
+// 轨迹写入器，Close写出文件尾并刷新缓冲，不关闭底层的io.Writer
+type Writer interface {
+	WritePoint(p *model.TrackPoint) error
+	Close() error
+}
+
+// 创建写入器并写出文件头
+func NewWriter(f Format, w io.Writer, meta *Meta) (Writer, error) {
+	base := &baseWriter{buf: bufio.NewWriter(w), meta: meta}
+	var res Writer
+	switch f {
+	case FormatGPX:
+		res = &gpxWriter{base}
+	case FormatKML:
+		res = &kmlWriter{base}
+	case FormatGeoJSON:
+		res = &geoJSONWriter{baseWriter: base}
+	case FormatCSV:
+		res = &csvWriter{baseWriter: base, csv: csv.NewWriter(base.buf)}
+	default:
+		return nil, errors.Wrapf(ErrUnknownFormat, "format=%s", f)
+	}
+	if err := res.(interface{ header() error }).header(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+type baseWriter struct {
+	buf  *bufio.Writer
+	meta *Meta
+}
+
+func (b *baseWriter) point(p *model.TrackPoint) geo.Point {
+	return coord.Convert(geo.Point{Lat: p.Latitude, Lng: p.Longitude}, coord.WGS84, b.meta.Datum)
+}
+
+func (b *baseWriter) printf(format string, args ...any) error {
+	_, err := fmt.Fprintf(b.buf, format, args...)
+	return err
+}
+
+func escape(s string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(s))
+	return sb.String()
+}
+
+func formatFloat(v float64, prec int) string {
+	return strconv.FormatFloat(v, 'f', prec, 64)
+}
+
+func regionName(code string) string {
+	if code == "" {
+		return ""
+	}
+	return region.Parse(code).FullName
+}
+
+// 轨迹点的说明：速度、方向、ACC、报警和行政区
+func describe(p *model.TrackPoint) string {
+	acc := "关"
+	if p.ACC() {
+		acc = "开"
+	}
+	parts := []string{
+		fmt.Sprintf("速度 %s km/h", formatFloat(p.Speed, 1)),
+		fmt.Sprintf("方向 %d°", p.Direction),
+		"ACC " + acc,
+	}
+	if alarms := p.Alarms(); len(alarms) > 0 {
+		parts = append(parts, "报警 "+strings.Join(alarms, "、"))
+	}
+	if name := regionName(p.Region); name != "" {
+		parts = append(parts, name)
+	}
+	if len(p.Quality) > 0 {
+		parts = append(parts, "数据问题 "+strings.Join(p.Quality, "、"))
+	}
+	return strings.Join(parts, "，")
+}
+
+type gpxWriter struct {
+	*baseWriter
+}
+
+func (w *gpxWriter) header() error {
+	return w.printf(`<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<gpx version="1.1" creator="jt808-server-go" xmlns="http://www.topografix.com/GPX/1/1">`+"\n"+
+		"<trk><name>%s</name><trkseg>\n", escape(w.meta.title()))
+}
+
+func (w *gpxWriter) WritePoint(p *model.TrackPoint) error {
+	if !p.Located() {
+		return nil
+	}
+	pt := w.point(p)
+	return w.printf("<trkpt lat=\"%s\" lon=\"%s\"><ele>%d</ele><time>%s</time><desc>%s</desc></trkpt>\n",
+		formatFloat(pt.Lat, 6), formatFloat(pt.Lng, 6), p.Altitude, p.Time.Format(time.RFC3339), escape(describe(p)))
+}
+
+func (w *gpxWriter) Close() error {
+	if err := w.printf("</trkseg></trk>\n</gpx>\n"); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+type kmlWriter struct {
+	*baseWriter
+}
+
+func (w *kmlWriter) header() error {
+	return w.printf(`<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<kml xmlns="http://www.opengis.net/kml/2.2"><Document><name>%s</name>`+"\n"+
+		`<Style id="normal"><IconStyle><color>ffff0000</color><scale>0.5</scale></IconStyle></Style>`+"\n"+
+		`<Style id="alarm"><IconStyle><color>ff0000ff</color></IconStyle></Style>`+"\n"+
+		"<Folder><name>轨迹点</name>\n", escape(w.meta.title()))
+}
+
+func (w *kmlWriter) WritePoint(p *model.TrackPoint) error {
+	if !p.Located() {
+		return nil
+	}
+	style := "normal"
+	if p.AlarmSign|p.ServerSign != 0 {
+		style = "alarm"
+	}
+	pt := w.point(p)
+	return w.printf("<Placemark><styleUrl>#%s</styleUrl><TimeStamp><when>%s</when></TimeStamp><description>%s</description>"+
+		"<Point><coordinates>%s,%s,%d</coordinates></Point></Placemark>\n",
+		style, p.Time.Format(time.RFC3339), escape(describe(p)), formatFloat(pt.Lng, 6), formatFloat(pt.Lat, 6), p.Altitude)
+}
+
+func (w *kmlWriter) Close() error {
+	if err := w.printf("</Folder></Document></kml>\n"); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+type geoJSONWriter struct {
+	*baseWriter
+	count int
+}
+
+type geoJSONFeature struct {
+	Type       string          `json:"type"`
+	Geometry   geoJSONGeometry `json:"geometry"`
+	Properties geoJSONProps    `json:"properties"`
+}
+
+type geoJSONGeometry struct {
+	Type        string    `json:"type"`
+	Coordinates []float64 `json:"coordinates"`
+}
+
+type geoJSONProps struct {
+	Time      time.Time `json:"time"`
+	Altitude  uint16    `json:"altitude"`
+	Speed     float64   `json:"speed"`
+	Direction uint16    `json:"direction"`
+	ACC       bool      `json:"acc"`
+	Status    uint32    `json:"status"`
+	AlarmSign uint32    `json:"alarmSign"`
+	Alarms    []string  `json:"alarms"`
+	Mileage   *float64  `json:"mileage,omitempty"`
+	Region    string    `json:"region,omitempty"`
+	Quality   []string  `json:"quality,omitempty"`
+}
+
+func (w *geoJSONWriter) header() error {
+	meta, err := json.Marshal(map[string]any{"phone": w.meta.Phone, "plate": w.meta.Plate, "coord": w.meta.Datum})
+	if err != nil {
+		return err
+	}
+	return w.printf(`{"type":"FeatureCollection","properties":%s,"features":[`+"\n", meta)
+}
+
+func (w *geoJSONWriter) WritePoint(p *model.TrackPoint) error {
+	if !p.Located() {
+		return nil
+	}
+	pt := w.point(p)
+	alarms := p.Alarms()
+	if alarms == nil {
+		alarms = []string{}
+	}
+	f := &geoJSONFeature{
+		Type:     "Feature",
+		Geometry: geoJSONGeometry{Type: "Point", Coordinates: []float64{pt.Lng, pt.Lat, float64(p.Altitude)}},
+		Properties: geoJSONProps{
+			Time:      p.Time,
+			Altitude:  p.Altitude,
+			Speed:     p.Speed,
+			Direction: p.Direction,
+			ACC:       p.ACC(),
+			Status:    p.StatusSign,
+			AlarmSign: p.AlarmSign | p.ServerSign,
+			Alarms:    alarms,
+			Mileage:   p.Mileage,
+			Region:    regionName(p.Region),
+			Quality:   p.Quality,
+		},
+	}
+	data, err := json.Marshal(f)
+	if err != nil {
+		return err
+	}
+	if w.count > 0 {
+		if err := w.buf.WriteByte(','); err != nil {
+			return err
+		}
+	}
+	w.count++
+	_, err = w.buf.Write(append(data, '\n'))
+	return err
+}
+
+func (w *geoJSONWriter) Close() error {
+	if err := w.printf("]}\n"); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
+
+type csvWriter struct {
+	*baseWriter
+	csv *csv.Writer
+}
+
+var csvHeader = []string{
+	"time", "latitude", "longitude", "altitude", "speed", "direction", "acc", "located",
+	"status", "alarmSign", "alarms", "mileage", "region", "quality",
+}
+
+func (w *csvWriter) header() error {
+	return w.csv.Write(csvHeader)
+}
+
+func (w *csvWriter) WritePoint(p *model.TrackPoint) error {
+	pt := w.point(p)
+	var mileage string
+	if p.Mileage != nil {
+		mileage = formatFloat(*p.Mileage, 1)
+	}
+	return w.csv.Write([]string{
+		p.Time.Format(time.RFC3339),
+		formatFloat(pt.Lat, 6),
+		formatFloat(pt.Lng, 6),
+		strconv.Itoa(int(p.Altitude)),
+		formatFloat(p.Speed, 1),
+		strconv.Itoa(int(p.Direction)),
+		strconv.FormatBool(p.ACC()),
+		strconv.FormatBool(p.Located()),
+		fmt.Sprintf("0x%08X", p.StatusSign),
+		fmt.Sprintf("0x%08X", p.AlarmSign|p.ServerSign),
+		strings.Join(p.Alarms(), ";"),
+		mileage,
+		regionName(p.Region),
+		strings.Join(p.Quality, ";"),
+	})
+}
+
+func (w *csvWriter) Close() error {
+	w.csv.Flush()
+	if err := w.csv.Error(); err != nil {
+		return err
+	}
+	return w.buf.Flush()
+}
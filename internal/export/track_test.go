@@ -0,0 +1,116 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+func testPoints() []*model.TrackPoint {
+	day := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	return []*model.TrackPoint{
+		{Time: day, Latitude: 39.9, Longitude: 116.4, Altitude: 50, Speed: 60, Direction: 90, StatusSign: 0b11},
+		// 未定位
+		{Time: day.Add(time.Minute), StatusSign: 0b01},
+		{Time: day.Add(2 * time.Minute), Latitude: 39.91, Longitude: 116.41, Speed: 125, StatusSign: 0b11, AlarmSign: 0b1, ServerSign: 0b10},
+	}
+}
+
+func write(t *testing.T, f Format, datum coord.Datum) []byte {
+	buf := &bytes.Buffer{}
+	w, err := NewWriter(f, buf, &Meta{Phone: "13900000048", Plate: "京A<12345>", Datum: datum})
+	require.NoError(t, err)
+	for _, p := range testPoints() {
+		require.NoError(t, w.WritePoint(p))
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+func TestParseFormat(t *testing.T) {
+	f, err := ParseFormat("kml")
+	require.NoError(t, err)
+	assert.Equal(t, "application/vnd.google-earth.kml+xml", f.ContentType())
+	_, err = ParseFormat("shp")
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
+
+func TestWriter_GPX(t *testing.T) {
+	var doc struct {
+		Name   string `xml:"trk>name"`
+		Points []struct {
+			Lat  float64 `xml:"lat,attr"`
+			Lon  float64 `xml:"lon,attr"`
+			Ele  int     `xml:"ele"`
+			Time string  `xml:"time"`
+			Desc string  `xml:"desc"`
+		} `xml:"trk>trkseg>trkpt"`
+	}
+	require.NoError(t, xml.Unmarshal(write(t, FormatGPX, coord.WGS84), &doc))
+	assert.Equal(t, "13900000048 京A<12345>", doc.Name)
+	require.Len(t, doc.Points, 2)
+	assert.Equal(t, 39.9, doc.Points[0].Lat)
+	assert.Equal(t, 116.4, doc.Points[0].Lon)
+	assert.Equal(t, 50, doc.Points[0].Ele)
+	assert.Equal(t, "2024-05-01T08:00:00Z", doc.Points[0].Time)
+	assert.Equal(t, "速度 60.0 km/h，方向 90°，ACC 开", doc.Points[0].Desc)
+	assert.Contains(t, doc.Points[1].Desc, "报警 紧急报警、超速报警")
+}
+
+func TestWriter_KML(t *testing.T) {
+	var doc struct {
+		Placemarks []struct {
+			Style       string `xml:"styleUrl"`
+			Coordinates string `xml:"Point>coordinates"`
+		} `xml:"Document>Folder>Placemark"`
+	}
+	require.NoError(t, xml.Unmarshal(write(t, FormatKML, coord.WGS84), &doc))
+	require.Len(t, doc.Placemarks, 2)
+	assert.Equal(t, "#normal", doc.Placemarks[0].Style)
+	assert.Equal(t, "116.400000,39.900000,50", doc.Placemarks[0].Coordinates)
+	assert.Equal(t, "#alarm", doc.Placemarks[1].Style)
+}
+
+func TestWriter_GeoJSON(t *testing.T) {
+	var doc struct {
+		Type     string `json:"type"`
+		Features []struct {
+			Geometry struct {
+				Coordinates []float64 `json:"coordinates"`
+			} `json:"geometry"`
+			Properties map[string]any `json:"properties"`
+		} `json:"features"`
+	}
+	require.NoError(t, json.Unmarshal(write(t, FormatGeoJSON, coord.GCJ02), &doc))
+	assert.Equal(t, "FeatureCollection", doc.Type)
+	require.Len(t, doc.Features, 2)
+	want := coord.WGS84ToGCJ02(geo.Point{Lat: 39.9, Lng: 116.4})
+	assert.InDelta(t, want.Lng, doc.Features[0].Geometry.Coordinates[0], 1e-9)
+	assert.InDelta(t, want.Lat, doc.Features[0].Geometry.Coordinates[1], 1e-9)
+	assert.Equal(t, []any{}, doc.Features[0].Properties["alarms"])
+	assert.Equal(t, []any{"紧急报警", "超速报警"}, doc.Features[1].Properties["alarms"])
+	assert.Equal(t, true, doc.Features[1].Properties["acc"])
+}
+
+func TestWriter_CSV(t *testing.T) {
+	records, err := csv.NewReader(bytes.NewReader(write(t, FormatCSV, coord.WGS84))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 4)
+	assert.Equal(t, csvHeader, records[0])
+	assert.Equal(t, []string{
+		"2024-05-01T08:01:00Z", "0.000000", "0.000000", "0", "0.0", "0", "true", "false",
+		"0x00000001", "0x00000000", "", "", "", "",
+	}, records[2])
+	assert.Equal(t, "0x00000003", records[3][9])
+	assert.Equal(t, "紧急报警;超速报警", records[3][10])
+}
@@ -0,0 +1,122 @@
+// Package geofence 平台侧电子围栏判断。
+//
+// 每条位置汇报与设备适用的围栏逐一比对，记录设备在各围栏内外的状态，
+// 状态变化时广播geofence事件：区域的进入/离开、路线的偏离/回到路线，以及区域或路段内超速。
+package geofence
+
+import (
+	"sync"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/event"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+type fenceState struct {
+	inside    bool
+	overspeed bool
+}
+
+type evaluator struct {
+	mutex  sync.Mutex
+	states map[string]map[uint32]*fenceState // phone -> fenceID -> state
+}
+
+var defaultEvaluator = &evaluator{states: make(map[string]map[uint32]*fenceState)}
+
+// 判断位置汇报并广播围栏事件，返回产生的事件。未定位的汇报不参与判断
+func Evaluate(d *model.Device, dg *model.DeviceGeo) []*event.Event {
+	if dg.Geo == nil || dg.Geo.LocationStatus == 0 || dg.Location == nil {
+		return nil
+	}
+	fences := storage.GetGeofenceCache().GeofencesOfDevice(d)
+	events := defaultEvaluator.evaluate(d.Phone, dg, fences)
+	for _, e := range events {
+		event.Publish(e)
+	}
+	return events
+}
+
+// 设备当前所在的围栏ID，路线表示未偏离
+func Inside(phone string) map[uint32]bool {
+	return defaultEvaluator.inside(phone)
+}
+
+// 清除设备的围栏状态
+func Forget(phone string) {
+	defaultEvaluator.mutex.Lock()
+	defer defaultEvaluator.mutex.Unlock()
+	delete(defaultEvaluator.states, phone)
+}
+
+func (e *evaluator) evaluate(phone string, dg *model.DeviceGeo, fences []*model.Geofence) []*event.Event {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	prev := e.states[phone]
+	cur := make(map[uint32]*fenceState, len(fences)) // 不再适用或已删除的围栏状态随之丢弃
+	p := geo.Point{Lat: dg.Location.Latitude, Lng: dg.Location.Longitude}
+	var speed float64
+	if dg.Drive != nil {
+		speed = dg.Drive.Speed
+	}
+
+	var events []*event.Event
+	newEvent := func(f *model.Geofence, action event.FenceAction, maxSpeed float64) {
+		events = append(events, &event.Event{
+			Type:  event.TypeGeofence,
+			Phone: phone,
+			Time:  dg.Time,
+			Geo:   dg,
+			Fence: &event.FenceInfo{ID: f.ID, Name: f.Name, Action: action, MaxSpeed: maxSpeed},
+		})
+	}
+
+	for _, f := range fences {
+		inside, maxSpeed := f.Locate(p)
+		st, ok := prev[f.ID]
+		if !ok {
+			// 首次判断时，区域视为在外，路线视为在线路上，只有真正的变化才产生事件
+			st = &fenceState{inside: f.Type == model.GeofenceRoute}
+		}
+		if inside != st.inside {
+			switch {
+			case f.Type == model.GeofenceRoute && inside:
+				newEvent(f, event.FenceOnRoute, 0)
+			case f.Type == model.GeofenceRoute:
+				newEvent(f, event.FenceOffRoute, 0)
+			case inside:
+				newEvent(f, event.FenceEnter, 0)
+			default:
+				newEvent(f, event.FenceExit, 0)
+			}
+			st.inside = inside
+		}
+		overspeed := inside && maxSpeed > 0 && speed > maxSpeed
+		if overspeed && !st.overspeed {
+			newEvent(f, event.FenceOverspeed, maxSpeed)
+		}
+		st.overspeed = overspeed
+		cur[f.ID] = st
+	}
+
+	if len(cur) == 0 {
+		delete(e.states, phone)
+	} else {
+		e.states[phone] = cur
+	}
+	return events
+}
+
+func (e *evaluator) inside(phone string) map[uint32]bool {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	res := make(map[uint32]bool, len(e.states[phone]))
+	for id, st := range e.states[phone] {
+		if st.inside {
+			res[id] = true
+		}
+	}
+	return res
+}
@@ -0,0 +1,82 @@
+package geofence
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/event"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+func newGeo(lat, lng, speed float64) *model.DeviceGeo {
+	return &model.DeviceGeo{
+		Geo:      &model.GeoMeta{LocationStatus: 1},
+		Location: &model.Location{Latitude: lat, Longitude: lng},
+		Drive:    &model.Drive{Speed: speed},
+	}
+}
+
+func actionsOf(events []*event.Event) []event.FenceAction {
+	res := []event.FenceAction{}
+	for _, e := range events {
+		res = append(res, e.Fence.Action)
+	}
+	return res
+}
+
+func TestEvaluator_Area(t *testing.T) {
+	e := &evaluator{states: make(map[string]map[uint32]*fenceState)}
+	fences := []*model.Geofence{
+		{ID: 1, Type: model.GeofenceRectangle, Points: []geo.Point{{Lat: 1, Lng: 0}, {Lat: 0, Lng: 1}}, MaxSpeed: 50},
+	}
+	phone := "13800000001"
+
+	tests := []struct {
+		name string
+		geo  *model.DeviceGeo
+		want []event.FenceAction
+	}{
+		{name: "outside", geo: newGeo(2, 2, 80), want: []event.FenceAction{}},
+		{name: "enter", geo: newGeo(0.5, 0.5, 40), want: []event.FenceAction{event.FenceEnter}},
+		{name: "stay", geo: newGeo(0.6, 0.6, 40), want: []event.FenceAction{}},
+		{name: "overspeed", geo: newGeo(0.6, 0.6, 60), want: []event.FenceAction{event.FenceOverspeed}},
+		{name: "keep overspeed", geo: newGeo(0.7, 0.7, 70), want: []event.FenceAction{}},
+		{name: "slow down", geo: newGeo(0.7, 0.7, 30), want: []event.FenceAction{}},
+		{name: "overspeed again", geo: newGeo(0.7, 0.7, 55), want: []event.FenceAction{event.FenceOverspeed}},
+		{name: "exit", geo: newGeo(2, 2, 80), want: []event.FenceAction{event.FenceExit}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, actionsOf(e.evaluate(phone, tt.geo, fences)))
+		})
+	}
+
+	// 围栏不再适用时丢弃状态
+	e.evaluate(phone, newGeo(0.5, 0.5, 0), fences)
+	assert.Equal(t, map[uint32]bool{1: true}, e.inside(phone))
+	e.evaluate(phone, newGeo(0.5, 0.5, 0), nil)
+	assert.Empty(t, e.inside(phone))
+}
+
+func TestEvaluator_Route(t *testing.T) {
+	e := &evaluator{states: make(map[string]map[uint32]*fenceState)}
+	fences := []*model.Geofence{
+		{
+			ID: 2, Name: "line", Type: model.GeofenceRoute,
+			Points:   []geo.Point{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 0.01}},
+			Segments: []*model.RouteSegment{{Width: 100, MaxSpeed: 60}},
+		},
+	}
+	phone := "13800000002"
+
+	assert.Empty(t, e.evaluate(phone, newGeo(0, 0.005, 40), fences))
+	events := e.evaluate(phone, newGeo(0.01, 0.005, 40), fences)
+	assert.Equal(t, []event.FenceAction{event.FenceOffRoute}, actionsOf(events))
+	assert.Equal(t, event.TypeGeofence, events[0].Type)
+	assert.Equal(t, "line", events[0].Fence.Name)
+	events = e.evaluate(phone, newGeo(0, 0.006, 70), fences)
+	assert.Equal(t, []event.FenceAction{event.FenceOnRoute, event.FenceOverspeed}, actionsOf(events))
+	assert.Equal(t, float64(60), events[1].Fence.MaxSpeed)
+}
@@ -0,0 +1,140 @@
+// Package metrics 定义jt808-server的prometheus监控指标
+package metrics
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "jt808"
+
+const (
+	DirectionIn  = "in"  // 终端上行
+	DirectionOut = "out" // 平台下行
+)
+
+// 下发消息结果
+const (
+	DownlinkSuccess   = "success"
+	DownlinkQueueFull = "queue_full"
+	DownlinkClosed    = "closed"
+	DownlinkError     = "error"
+)
+
+var (
+	Connections = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "connections",
+		Help:      "Current number of terminal connections.",
+	})
+
+	RejectedConnections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rejected_connections_total",
+		Help:      "Number of rejected terminal connections by reason.",
+	}, []string{"reason"})
+
+	RateLimitedFrames = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_frames_total",
+		Help:      "Number of frames dropped for exceeding the per-connection msg rate limit.",
+	})
+
+	Messages = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "messages_total",
+		Help:      "Number of jt808 messages by direction and msg id.",
+	}, []string{"direction", "msg_id"})
+
+	DecodeFailures = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "decode_failures_total",
+		Help:      "Number of packets failed to decode or verify by reason.",
+	}, []string{"reason"})
+
+	StageDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "pipeline_stage_duration_seconds",
+		Help:      "Processing latency of each pipeline stage.",
+		Buckets:   []float64{.0001, .0005, .001, .005, .01, .05, .1, .5, 1},
+	}, []string{"stage"})
+
+	Downlinks = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "downlinks_total",
+		Help:      "Number of messages sent to terminals by the api, by msg id and result.",
+	}, []string{"msg_id", "result"})
+
+	SegmentsPending = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "segments_pending",
+		Help:      "Number of fragmented messages waiting for reassembly.",
+	})
+
+	SegmentsCompleted = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "segments_completed_total",
+		Help:      "Number of fragmented messages reassembled.",
+	})
+
+	PersistenceSaveErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "persistence_save_errors_total",
+		Help:      "Number of failed persistence saves by file.",
+	}, []string{"file"})
+
+	EventsDropped = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "events_dropped_total",
+		Help:      "Number of events dropped for slow subscribers, by event type.",
+	}, []string{"type"})
+
+	FilteredLocations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "filtered_locations_total",
+		Help:      "Number of location reports flagged by the GNSS quality filter, by reason.",
+	}, []string{"reason"})
+)
+
+// 格式化msg id作为label值，与日志中RawMsgID格式一致
+func MsgIDLabel(msgID uint16) string {
+	return fmt.Sprintf("0x%04x", msgID)
+}
+
+// 记录pipeline阶段耗时，配合defer使用:
+//
+//	defer metrics.ObserveStage("decode", time.Now())
+func ObserveStage(stage string, start time.Time) {
+	StageDuration.WithLabelValues(stage).Observe(time.Since(start).Seconds())
+}
+
+// 按设备状态统计设备数，在scrape时调用countFn
+type deviceStatusCollector struct {
+	desc    *prometheus.Desc
+	countFn func() map[string]int
+}
+
+func (c *deviceStatusCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *deviceStatusCollector) Collect(ch chan<- prometheus.Metric) {
+	for status, cnt := range c.countFn() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(cnt), status)
+	}
+}
+
+// 注册设备状态统计，countFn返回<状态名, 设备数>
+func RegisterDeviceStatus(countFn func() map[string]int) {
+	prometheus.MustRegister(&deviceStatusCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "", "devices"),
+			"Number of cached devices by status.",
+			[]string{"status"}, nil,
+		),
+		countFn: countFn,
+	})
+}
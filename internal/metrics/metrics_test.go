@@ -0,0 +1,66 @@
+package metrics
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsgIDLabel(t *testing.T) {
+	tests := []struct {
+		name  string
+		msgID uint16
+		want  string
+	}{
+		{name: "case1: location report", msgID: 0x0200, want: "0x0200"},
+		{name: "case2: downlink", msgID: 0x8103, want: "0x8103"},
+		{name: "case3: small id", msgID: 0x0002, want: "0x0002"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, MsgIDLabel(tt.msgID))
+		})
+	}
+}
+
+func TestObserveStage(t *testing.T) {
+	stage := "test_stage"
+	ObserveStage(stage, time.Now().Add(-2*time.Millisecond))
+
+	m := &dto.Metric{}
+	require.NoError(t, StageDuration.WithLabelValues(stage).(prometheus.Metric).Write(m))
+	assert.Equal(t, uint64(1), m.GetHistogram().GetSampleCount())
+	assert.GreaterOrEqual(t, m.GetHistogram().GetSampleSum(), 0.002)
+}
+
+func TestDeviceStatusCollector(t *testing.T) {
+	c := &deviceStatusCollector{
+		desc: prometheus.NewDesc("jt808_devices", "Number of cached devices by status.", []string{"status"}, nil),
+		countFn: func() map[string]int {
+			return map[string]int{"online": 3, "offline": 1}
+		},
+	}
+	expected := `
+# HELP jt808_devices Number of cached devices by status.
+# TYPE jt808_devices gauge
+jt808_devices{status="offline"} 1
+jt808_devices{status="online"} 3
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
+
+func TestCounters(t *testing.T) {
+	before := testutil.ToFloat64(Messages.WithLabelValues(DirectionIn, "0x0002"))
+	Messages.WithLabelValues(DirectionIn, MsgIDLabel(0x0002)).Inc()
+	assert.Equal(t, before+1, testutil.ToFloat64(Messages.WithLabelValues(DirectionIn, "0x0002")))
+
+	before = testutil.ToFloat64(DecodeFailures.WithLabelValues("verify_failed"))
+	DecodeFailures.WithLabelValues("verify_failed").Inc()
+	assert.Equal(t, before+1, testutil.ToFloat64(DecodeFailures.WithLabelValues("verify_failed")))
+}
@@ -0,0 +1,75 @@
+package protocol
+
+import (
+	"sync"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+// 平台下发消息与终端应答的关联，key为<手机号, 平台消息流水号>
+type answerKey struct {
+	phone        string
+	serialNumber uint16
+}
+
+type answerWaiter struct {
+	msgID uint16 // 平台下发的消息ID
+	ch    chan model.JT808Msg
+}
+
+type answerRegistry struct {
+	mutex   sync.Mutex
+	waiters map[answerKey]*answerWaiter
+}
+
+var answers = &answerRegistry{waiters: make(map[answerKey]*answerWaiter)}
+
+// 等待终端对指定消息的应答(0x0001通用应答或0x0104等专用应答)。
+// 须在下发消息前调用，返回的cancel用于不再等待时释放。
+func WaitAnswer(phone string, msgID, serialNumber uint16) (<-chan model.JT808Msg, func()) {
+	key := answerKey{phone: phone, serialNumber: serialNumber}
+	w := &answerWaiter{msgID: msgID, ch: make(chan model.JT808Msg, 1)}
+	answers.mutex.Lock()
+	answers.waiters[key] = w
+	answers.mutex.Unlock()
+
+	cancel := func() {
+		answers.mutex.Lock()
+		if answers.waiters[key] == w {
+			delete(answers.waiters, key)
+		}
+		answers.mutex.Unlock()
+	}
+	return w.ch, cancel
+}
+
+// 投递终端应答，无人等待时返回false
+func deliverAnswer(phone string, serialNumber uint16, msg model.JT808Msg) bool {
+	key := answerKey{phone: phone, serialNumber: serialNumber}
+	answers.mutex.Lock()
+	w, ok := answers.waiters[key]
+	delete(answers.waiters, key)
+	answers.mutex.Unlock()
+	if !ok {
+		return false
+	}
+	w.ch <- msg // 缓冲为1且只投递一次，不会阻塞
+	return true
+}
+
+// 投递不含应答流水号的终端应答(如0x0107)，按平台消息ID匹配该终端的所有等待方
+func deliverAnswerByMsgID(phone string, msgID uint16, msg model.JT808Msg) bool {
+	var matched []*answerWaiter
+	answers.mutex.Lock()
+	for key, w := range answers.waiters {
+		if key.phone == phone && w.msgID == msgID {
+			matched = append(matched, w)
+			delete(answers.waiters, key)
+		}
+	}
+	answers.mutex.Unlock()
+	for _, w := range matched {
+		w.ch <- msg
+	}
+	return len(matched) > 0
+}
@@ -0,0 +1,45 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+func TestWaitAnswer(t *testing.T) {
+	phone := "13800000000"
+	answer := &model.Msg0001{Header: &model.MsgHeader{PhoneNumber: phone}, AnswerSerialNumber: 7}
+
+	ch, cancel := WaitAnswer(phone, 0x8103, 7)
+	defer cancel()
+	assert.False(t, deliverAnswer(phone, 8, answer), "serial number mismatch")
+	assert.False(t, deliverAnswer("13800000001", 7, answer), "phone mismatch")
+	assert.True(t, deliverAnswer(phone, 7, answer))
+	assert.Equal(t, answer, <-ch)
+	assert.False(t, deliverAnswer(phone, 7, answer), "answer should be delivered only once")
+
+	_, cancel = WaitAnswer(phone, 0x8103, 9)
+	cancel()
+	assert.False(t, deliverAnswer(phone, 9, answer), "canceled waiter")
+}
+
+func TestDeliverAnswerByMsgID(t *testing.T) {
+	phone := "13800000000"
+	answer := &model.Msg0107{Header: &model.MsgHeader{PhoneNumber: phone}}
+
+	ch1, cancel1 := WaitAnswer(phone, 0x8107, 1)
+	defer cancel1()
+	ch2, cancel2 := WaitAnswer(phone, 0x8107, 2)
+	defer cancel2()
+	_, cancel3 := WaitAnswer(phone, 0x8104, 3)
+	defer cancel3()
+
+	assert.False(t, deliverAnswerByMsgID("13800000001", 0x8107, answer))
+	assert.True(t, deliverAnswerByMsgID(phone, 0x8107, answer))
+	assert.Equal(t, answer, <-ch1)
+	assert.Equal(t, answer, <-ch2)
+	assert.False(t, deliverAnswerByMsgID(phone, 0x8107, answer), "answer should be delivered only once")
+	assert.True(t, deliverAnswer(phone, 3, answer), "waiter of other msg id should be kept")
+}
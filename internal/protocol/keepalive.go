@@ -7,8 +7,14 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
+	"github.com/fakeyanss/jt808-server-go/internal/driving"
+	"github.com/fakeyanss/jt808-server-go/internal/event"
+	"github.com/fakeyanss/jt808-server-go/internal/geofence"
 	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/quality"
+	"github.com/fakeyanss/jt808-server-go/internal/spatial"
 	"github.com/fakeyanss/jt808-server-go/internal/storage"
+	"github.com/fakeyanss/jt808-server-go/internal/trip"
 )
 
 type KeepaliveTimer struct {
@@ -73,6 +79,7 @@ func checkDeviceKeepalive(t *KeepaliveTimer, devicePhone string) {
 	}
 	if d.ShouleTurnOffline() {
 		// 保活失效
+		event.PublishStatus(d.Phone, d.Status, model.DeviceStatusOffline)
 		d.Status = model.DeviceStatusOffline
 		cache.CacheDevice(d)
 		log.Debug().Str("device", devicePhone).Msg("Turn offline for device keepalive expired")
@@ -80,6 +87,12 @@ func checkDeviceKeepalive(t *KeepaliveTimer, devicePhone string) {
 		d.Conn.Close()
 		cache.DelDeviceByPhone(devicePhone)
 		gisCache.DelGeoByPhone(devicePhone)
+		spatial.Forget(devicePhone)
+		quality.Forget(devicePhone)
+		geofence.Forget(devicePhone)
+		trip.Forget(devicePhone)
+		storage.GetTripCache().DelTripsByPhone(devicePhone)
+		driving.Forget(devicePhone)
 		log.Debug().Str("device", d.Phone).Msg("Clear cache and close connection after device being offline for a long time")
 		t.Cancel(devicePhone)
 	}
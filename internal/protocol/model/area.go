@@ -0,0 +1,430 @@
+package model
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 区域属性位，路线属性使用其中的bit0、bit2-5
+const (
+	AreaAttrByTime             uint16 = 1 << 0  // 根据时间
+	AreaAttrSpeedLimit         uint16 = 1 << 1  // 限速，路线保留
+	AreaAttrEnterAlarmDriver   uint16 = 1 << 2  // 进区域报警给驾驶员
+	AreaAttrEnterAlarmPlatform uint16 = 1 << 3  // 进区域报警给平台
+	AreaAttrExitAlarmDriver    uint16 = 1 << 4  // 出区域报警给驾驶员
+	AreaAttrExitAlarmPlatform  uint16 = 1 << 5  // 出区域报警给平台
+	AreaAttrSouth              uint16 = 1 << 6  // 南纬
+	AreaAttrWest               uint16 = 1 << 7  // 西经
+	AreaAttrNoDoorOpen         uint16 = 1 << 8  // 禁止开门
+	AreaAttrCloseComm          uint16 = 1 << 14 // 进区域关闭通信模块
+	AreaAttrCollectGNSS        uint16 = 1 << 15 // 进区域采集GNSS详细定位数据
+
+	routeAttrMask = AreaAttrByTime | AreaAttrEnterAlarmDriver | AreaAttrEnterAlarmPlatform |
+		AreaAttrExitAlarmDriver | AreaAttrExitAlarmPlatform
+)
+
+// 区域属性位的名称，下标为bit位
+var areaAttrNames = []string{
+	"byTime", "speedLimit", "enterAlarmDriver", "enterAlarmPlatform", "exitAlarmDriver", "exitAlarmPlatform",
+	"south", "west", "noDoorOpen", "", "", "", "", "", "closeComm", "collectGNSS",
+}
+
+// 路段属性位
+const (
+	SegmentAttrDriveTime  uint8 = 1 << 0 // 行驶时间
+	SegmentAttrSpeedLimit uint8 = 1 << 1 // 限速
+	SegmentAttrSouth      uint8 = 1 << 2 // 南纬
+	SegmentAttrWest       uint8 = 1 << 3 // 西经
+)
+
+// 设置区域的设置属性
+const (
+	AreaActionUpdate uint8 = 0 // 更新区域，清除终端已有的同类区域
+	AreaActionAppend uint8 = 1 // 追加区域
+	AreaActionModify uint8 = 2 // 修改区域
+)
+
+// 查询区域或线路数据的查询类型
+const (
+	AreaTypeCircle    uint8 = 1 // 圆形区域
+	AreaTypeRectangle uint8 = 2 // 矩形区域
+	AreaTypePolygon   uint8 = 3 // 多边形区域
+	AreaTypeRoute     uint8 = 4 // 路线
+)
+
+const (
+	areaTimeLen    = 6   // 起始、结束时间BCD[6]
+	maxAreaDeleted = 125 // 删除区域时单条消息最多的区域数
+)
+
+// 区域属性位的名称列表
+func AreaAttrNames(attr uint16) []string {
+	return bitNames(uint32(attr), areaAttrNames)
+}
+
+// 由名称解析区域属性位
+func ParseAreaAttrs(names []string) (uint16, error) {
+	var attr uint16
+	for _, name := range names {
+		found := false
+		for i, n := range areaAttrNames {
+			if n != "" && n == name {
+				attr |= 1 << i
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, errors.Wrapf(ErrInvalidGeofence, "unknown area attr %s", name)
+		}
+	}
+	return attr, nil
+}
+
+// 平台侧围栏类型对应的查询类型，0表示不支持
+func AreaTypeOf(t GeofenceType) uint8 {
+	switch t {
+	case GeofenceCircle:
+		return AreaTypeCircle
+	case GeofenceRectangle:
+		return AreaTypeRectangle
+	case GeofencePolygon:
+		return AreaTypePolygon
+	case GeofenceRoute:
+		return AreaTypeRoute
+	}
+	return 0
+}
+
+// 区域坐标，经纬度为绝对值，单位为百万分之一度，南纬、西经由区域属性或路段属性表示
+type AreaPoint struct {
+	Latitude  uint32 `json:"latitude"`
+	Longitude uint32 `json:"longitude"`
+}
+
+func newAreaPoint(p geo.Point) AreaPoint {
+	return AreaPoint{
+		Latitude:  uint32(math.Round(math.Abs(p.Lat) * LocationAccuracy)),
+		Longitude: uint32(math.Round(math.Abs(p.Lng) * LocationAccuracy)),
+	}
+}
+
+func (p AreaPoint) toPoint(south, west bool) geo.Point {
+	res := geo.Point{Lat: float64(p.Latitude) / LocationAccuracy, Lng: float64(p.Longitude) / LocationAccuracy}
+	if south {
+		res.Lat = -res.Lat
+	}
+	if west {
+		res.Lng = -res.Lng
+	}
+	return res
+}
+
+func readAreaPoint(pkt []byte, idx *int) AreaPoint {
+	return AreaPoint{Latitude: hex.ReadDoubleWord(pkt, idx), Longitude: hex.ReadDoubleWord(pkt, idx)}
+}
+
+func writeAreaPoint(pkt []byte, p AreaPoint) []byte {
+	pkt = hex.WriteDoubleWord(pkt, p.Latitude)
+	return hex.WriteDoubleWord(pkt, p.Longitude)
+}
+
+// 区域的时间和速度限制
+type AreaLimit struct {
+	StartTime         string `json:"startTime,omitempty"`         // 起始时间YYMMDDhhmmss，区域属性bit0为1时有效
+	EndTime           string `json:"endTime,omitempty"`           // 结束时间YYMMDDhhmmss
+	MaxSpeed          uint16 `json:"maxSpeed,omitempty"`          // 最高速度，km/h，区域属性bit1为1时有效
+	OverspeedDuration uint8  `json:"overspeedDuration,omitempty"` // 超速持续时间，秒
+	NightMaxSpeed     uint16 `json:"nightMaxSpeed,omitempty"`     // 夜间最高速度，km/h，2019版本
+}
+
+func hasLen(pkt []byte, idx, n int) error {
+	if len(pkt) < idx+n {
+		return ErrDecodeMsg
+	}
+	return nil
+}
+
+func readTimeRange(pkt []byte, idx *int, byTime bool) (start, end string, err error) {
+	if !byTime {
+		return "", "", nil
+	}
+	if err = hasLen(pkt, *idx, 2*areaTimeLen); err != nil {
+		return "", "", err
+	}
+	return hex.ReadBCD(pkt, idx, areaTimeLen), hex.ReadBCD(pkt, idx, areaTimeLen), nil
+}
+
+func writeTimeRange(pkt []byte, byTime bool, start, end string) []byte {
+	if !byTime {
+		return pkt
+	}
+	pkt = hex.WriteBytes(pkt, bcdTime(start))
+	return hex.WriteBytes(pkt, bcdTime(end))
+}
+
+// YYMMDDhhmmss转为BCD[6]，长度不足时补0
+func bcdTime(s string) []byte {
+	b := make([]byte, areaTimeLen)
+	for i := 0; i < len(s) && i < 2*areaTimeLen; i++ {
+		b[i/2] |= (s[i] - '0') << (4 * (1 - i%2))
+	}
+	return b
+}
+
+// 读取最高速度和超速持续时间，夜间最高速度位置因消息而异，单独读取
+func (l *AreaLimit) readSpeed(pkt []byte, idx *int, speedLimit bool) error {
+	if !speedLimit {
+		return nil
+	}
+	if err := hasLen(pkt, *idx, 3); err != nil {
+		return err
+	}
+	l.MaxSpeed = hex.ReadWord(pkt, idx)
+	l.OverspeedDuration = hex.ReadByte(pkt, idx)
+	return nil
+}
+
+func (l *AreaLimit) writeSpeed(pkt []byte, speedLimit bool) []byte {
+	if !speedLimit {
+		return pkt
+	}
+	pkt = hex.WriteWord(pkt, l.MaxSpeed)
+	return hex.WriteByte(pkt, l.OverspeedDuration)
+}
+
+func (l *AreaLimit) readNightSpeed(pkt []byte, idx *int, speedLimit bool, ver VersionType) error {
+	if !speedLimit || ver != Version2019 {
+		return nil
+	}
+	if err := hasLen(pkt, *idx, 2); err != nil {
+		return err
+	}
+	l.NightMaxSpeed = hex.ReadWord(pkt, idx)
+	return nil
+}
+
+func (l *AreaLimit) writeNightSpeed(pkt []byte, speedLimit bool, ver VersionType) []byte {
+	if !speedLimit || ver != Version2019 {
+		return pkt
+	}
+	return hex.WriteWord(pkt, l.NightMaxSpeed)
+}
+
+// 区域名称，2019版本，WORD长度+GBK字符串
+func readAreaName(pkt []byte, idx *int, ver VersionType) (string, error) {
+	if ver != Version2019 {
+		return "", nil
+	}
+	if err := hasLen(pkt, *idx, 2); err != nil {
+		return "", err
+	}
+	n := int(hex.ReadWord(pkt, idx))
+	if err := hasLen(pkt, *idx, n); err != nil {
+		return "", err
+	}
+	return hex.ReadGBK(pkt, idx, n), nil
+}
+
+func writeAreaName(pkt []byte, name string, ver VersionType) []byte {
+	if ver != Version2019 {
+		return pkt
+	}
+	gbk := hex.WriteGBK(nil, name)
+	pkt = hex.WriteWord(pkt, uint16(len(gbk)))
+	return hex.WriteBytes(pkt, gbk)
+}
+
+// 删除区域或路线的区域ID列表，为空表示删除全部同类区域
+func decodeAreaIDs(pkt []byte) ([]uint32, error) {
+	idx := 0
+	if err := hasLen(pkt, idx, 1); err != nil {
+		return nil, err
+	}
+	cnt := int(hex.ReadByte(pkt, &idx))
+	if err := hasLen(pkt, idx, cnt*4); err != nil {
+		return nil, err
+	}
+	ids := make([]uint32, 0, cnt)
+	for i := 0; i < cnt; i++ {
+		ids = append(ids, hex.ReadDoubleWord(pkt, &idx))
+	}
+	return ids, nil
+}
+
+func encodeAreaIDs(ids []uint32) ([]byte, error) {
+	if len(ids) > maxAreaDeleted {
+		return nil, errors.Wrapf(ErrEncodeMsg, "at most %d areas per message", maxAreaDeleted)
+	}
+	pkt := hex.WriteByte(nil, uint8(len(ids)))
+	for _, id := range ids {
+		pkt = hex.WriteDoubleWord(pkt, id)
+	}
+	return pkt, nil
+}
+
+// 区域消息体超过单包长度时不下发，平台下发暂不支持分包
+func checkBodyLength(body []byte) error {
+	if len(body) > MaxBodyLength {
+		return errors.Wrapf(ErrEncodeMsg, "body length %d exceeds %d", len(body), MaxBodyLength)
+	}
+	return nil
+}
+
+var areaSettingMsgIDs = map[GeofenceType]uint16{
+	GeofenceCircle:    0x8600,
+	GeofenceRectangle: 0x8602,
+	GeofencePolygon:   0x8604,
+	GeofenceRoute:     0x8606,
+}
+
+// 下发区域时的附加设置，时间、限速、南纬、西经属性位由围栏生成
+type AreaOptions struct {
+	Action            uint8  // 设置属性，仅圆形、矩形区域
+	Attr              uint16 // 报警、开门、通信模块、GNSS采集等属性位
+	StartTime         string // 起始时间YYMMDDhhmmss，与结束时间都不为空时按时间生效
+	EndTime           string // 结束时间YYMMDDhhmmss
+	OverspeedDuration uint8  // 超速持续时间，秒
+	NightMaxSpeed     uint16 // 夜间最高速度，km/h，2019版本
+}
+
+// 由平台侧围栏生成终端区域设置消息(0x8600/0x8602/0x8604/0x8606)，genHeader按消息ID生成消息头
+func NewAreaSetting(f *Geofence, opt *AreaOptions, genHeader func(msgID uint16) *MsgHeader) (JT808Msg, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+	header := genHeader(areaSettingMsgIDs[f.Type])
+	name, nightMaxSpeed := f.Name, opt.NightMaxSpeed
+	if header.Attr.VersionDesc != Version2019 { // 名称和夜间最高速度为2019版本字段
+		name, nightMaxSpeed = "", 0
+	}
+	attr := opt.Attr &^ (AreaAttrByTime | AreaAttrSpeedLimit | AreaAttrSouth | AreaAttrWest)
+	if opt.StartTime != "" && opt.EndTime != "" {
+		attr |= AreaAttrByTime
+	}
+	limit := AreaLimit{StartTime: opt.StartTime, EndTime: opt.EndTime}
+	if f.MaxSpeed > 0 && f.Type != GeofenceRoute {
+		attr |= AreaAttrSpeedLimit
+		limit.MaxSpeed = uint16(math.Round(f.MaxSpeed))
+		limit.OverspeedDuration = opt.OverspeedDuration
+		limit.NightMaxSpeed = nightMaxSpeed
+	}
+	if attr&AreaAttrByTime == 0 {
+		limit.StartTime, limit.EndTime = "", ""
+	}
+
+	points := f.Points
+	if f.Center != nil {
+		points = []geo.Point{*f.Center}
+	}
+	south, west, err := hemisphere(points)
+	if err != nil {
+		return nil, err
+	}
+
+	switch f.Type {
+	case GeofenceCircle:
+		attr |= hemisphereAttr(south, west)
+		return &Msg8600{
+			Header: header,
+			Action: opt.Action,
+			Areas: []*CircleArea{{
+				ID: f.ID, Attr: attr, Center: newAreaPoint(*f.Center), Radius: uint32(math.Round(f.Radius)),
+				AreaLimit: limit, Name: name,
+			}},
+		}, nil
+	case GeofenceRectangle:
+		attr |= hemisphereAttr(south, west)
+		return &Msg8602{
+			Header: header,
+			Action: opt.Action,
+			Areas: []*RectArea{{
+				ID: f.ID, Attr: attr, LeftTop: newAreaPoint(f.Points[0]), RightBottom: newAreaPoint(f.Points[1]),
+				AreaLimit: limit, Name: name,
+			}},
+		}, nil
+	case GeofencePolygon:
+		attr |= hemisphereAttr(south, west)
+		area := &PolygonArea{ID: f.ID, Attr: attr, AreaLimit: limit, Name: name}
+		for _, p := range f.Points {
+			area.Points = append(area.Points, newAreaPoint(p))
+		}
+		return &Msg8604{Header: header, Area: area}, nil
+	default: // GeofenceRoute
+		route := &Route{ID: f.ID, Attr: attr & routeAttrMask, StartTime: limit.StartTime, EndTime: limit.EndTime, Name: name}
+		segAttr := uint8(0)
+		if south {
+			segAttr |= SegmentAttrSouth
+		}
+		if west {
+			segAttr |= SegmentAttrWest
+		}
+		for i, p := range f.Points {
+			// 最后一个拐点沿用最后一段的路段设置
+			s := f.Segments[len(f.Segments)-1]
+			if i < len(f.Segments) {
+				s = f.Segments[i]
+			}
+			if s.Width > math.MaxUint8 {
+				return nil, errors.Wrapf(ErrInvalidGeofence, "segment width %.0f exceeds %d", s.Width, math.MaxUint8)
+			}
+			tp := &RoutePoint{
+				ID: uint32(i + 1), SegmentID: uint32(i + 1), Point: newAreaPoint(p),
+				Width: uint8(math.Round(s.Width)), Attr: segAttr,
+			}
+			if maxSpeed := s.MaxSpeed; maxSpeed > 0 || f.MaxSpeed > 0 {
+				if maxSpeed == 0 {
+					maxSpeed = f.MaxSpeed
+				}
+				tp.Attr |= SegmentAttrSpeedLimit
+				tp.MaxSpeed = uint16(math.Round(maxSpeed))
+				tp.OverspeedDuration = opt.OverspeedDuration
+				tp.NightMaxSpeed = nightMaxSpeed
+			}
+			route.Points = append(route.Points, tp)
+		}
+		return &Msg8606{Header: header, Route: route}, nil
+	}
+}
+
+// 由平台侧围栏类型生成终端删除区域消息(0x8601/0x8603/0x8605/0x8607)
+func NewAreaDelete(t GeofenceType, ids []uint32, genHeader func(msgID uint16) *MsgHeader) (JT808Msg, error) {
+	switch t {
+	case GeofenceCircle:
+		return &Msg8601{Header: genHeader(0x8601), IDs: ids}, nil
+	case GeofenceRectangle:
+		return &Msg8603{Header: genHeader(0x8603), IDs: ids}, nil
+	case GeofencePolygon:
+		return &Msg8605{Header: genHeader(0x8605), IDs: ids}, nil
+	case GeofenceRoute:
+		return &Msg8607{Header: genHeader(0x8607), IDs: ids}, nil
+	}
+	return nil, errors.Wrapf(ErrInvalidGeofence, "type %s is not supported", t)
+}
+
+// 终端区域以属性位表示南北纬、东西经，区域内所有点须在同一半球
+func hemisphere(points []geo.Point) (south, west bool, err error) {
+	for i, p := range points {
+		s, w := p.Lat < 0, p.Lng < 0
+		if i > 0 && (s != south || w != west) {
+			return false, false, errors.Wrap(ErrInvalidGeofence, "area points must be in the same hemisphere")
+		}
+		south, west = s, w
+	}
+	return south, west, nil
+}
+
+func hemisphereAttr(south, west bool) uint16 {
+	var attr uint16
+	if south {
+		attr |= AreaAttrSouth
+	}
+	if west {
+		attr |= AreaAttrWest
+	}
+	return attr
+}
@@ -0,0 +1,181 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+func genAreaHeader(ver VersionType) func(msgID uint16) *MsgHeader {
+	return func(msgID uint16) *MsgHeader {
+		h := genMsgHeader(msgID)
+		h.Attr.VersionDesc = ver
+		return h
+	}
+}
+
+// 编码后取消息体解码到同类型的新消息
+func decodeBody(t *testing.T, m JT808Msg, got JT808Msg) {
+	pkt, err := m.Encode()
+	require.NoError(t, err)
+	body := pkt[len(pkt)-int(m.GetHeader().Attr.BodyLength):]
+	assert.ErrorIs(t, got.Decode(&PacketData{Header: m.GetHeader(), Body: body[:len(body)-1]}), ErrDecodeMsg)
+	require.NoError(t, got.Decode(&PacketData{Header: m.GetHeader(), Body: body}))
+}
+
+func TestMsg8600_Encode(t *testing.T) {
+	m := &Msg8600{
+		Header: genAreaHeader(Version2013)(0x8600),
+		Action: AreaActionAppend,
+		Areas: []*CircleArea{{
+			ID: 1, Attr: AreaAttrByTime | AreaAttrSpeedLimit, Center: AreaPoint{Latitude: 22540000, Longitude: 114050000}, Radius: 200,
+			AreaLimit: AreaLimit{StartTime: "260101080000", EndTime: "261231180000", MaxSpeed: 60, OverspeedDuration: 10},
+		}},
+	}
+	pkt, err := m.Encode()
+	require.NoError(t, err)
+	wantBody := "01" + "01" + "00000001" + "0003" + "0157eee0" + "06cc43d0" + "000000c8" +
+		"260101080000" + "261231180000" + "003c" + "0a"
+	assert.Equal(t, hex.Str2Byte(wantBody), pkt[len(pkt)-len(wantBody)/2:])
+}
+
+func TestNewAreaSetting_EncodeDecode(t *testing.T) {
+	fences := []*Geofence{
+		{ID: 1, Name: "圆", Type: GeofenceCircle, Center: &geo.Point{Lat: -22.54, Lng: -114.05}, Radius: 200, MaxSpeed: 60},
+		{ID: 2, Name: "矩形", Type: GeofenceRectangle, Points: []geo.Point{{Lat: 23, Lng: 113}, {Lat: 22, Lng: 114}}},
+		{ID: 3, Name: "多边形", Type: GeofencePolygon, Points: []geo.Point{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 1}, {Lat: 1, Lng: 1}}, MaxSpeed: 40},
+		{
+			ID: 4, Name: "路线", Type: GeofenceRoute, Points: []geo.Point{{Lat: 22, Lng: 113}, {Lat: 22.1, Lng: 113}, {Lat: 22.1, Lng: 113.1}},
+			Segments: []*RouteSegment{{Width: 50, MaxSpeed: 80}, {Width: 30}},
+		},
+	}
+	opt := &AreaOptions{Attr: AreaAttrEnterAlarmPlatform | AreaAttrNoDoorOpen, StartTime: "000000080000", EndTime: "000000180000", OverspeedDuration: 5, NightMaxSpeed: 30}
+	for _, ver := range []VersionType{Version2013, Version2019} {
+		for _, f := range fences {
+			t.Run(fmt.Sprintf("%s-%d", f.Type, ver), func(t *testing.T) {
+				msg, err := NewAreaSetting(f, opt, genAreaHeader(ver))
+				require.NoError(t, err)
+
+				var got *Geofence
+				var attr uint16
+				switch m := msg.(type) {
+				case *Msg8600:
+					res := &Msg8600{}
+					decodeBody(t, m, res)
+					assert.Equal(t, m.Areas, res.Areas)
+					got, attr = res.Areas[0].Geofence(), res.Areas[0].Attr
+				case *Msg8602:
+					res := &Msg8602{}
+					decodeBody(t, m, res)
+					assert.Equal(t, m.Areas, res.Areas)
+					got, attr = res.Areas[0].Geofence(), res.Areas[0].Attr
+				case *Msg8604:
+					res := &Msg8604{}
+					decodeBody(t, m, res)
+					assert.Equal(t, m.Area, res.Area)
+					got, attr = res.Area.Geofence(), res.Area.Attr
+				case *Msg8606:
+					res := &Msg8606{}
+					decodeBody(t, m, res)
+					assert.Equal(t, m.Route, res.Route)
+					assert.Equal(t, uint16(80), res.Route.Points[0].MaxSpeed)
+					assert.Zero(t, res.Route.Points[1].Attr&SegmentAttrSpeedLimit)
+					got, attr = res.Route.Geofence(), res.Route.Attr
+				default:
+					t.Fatalf("unexpected msg %T", msg)
+				}
+
+				assert.Equal(t, f.Type, got.Type)
+				assert.Equal(t, f.Radius, got.Radius)
+				if f.Center != nil {
+					assert.Equal(t, *f.Center, *got.Center)
+				}
+				assert.Equal(t, f.Points, got.Points)
+				assert.NotZero(t, attr&AreaAttrByTime)
+				assert.NotZero(t, attr&AreaAttrEnterAlarmPlatform)
+				if ver == Version2019 {
+					assert.Equal(t, f.Name, got.Name)
+				} else {
+					assert.Empty(t, got.Name)
+				}
+			})
+		}
+	}
+
+	circle, err := NewAreaSetting(fences[0], &AreaOptions{}, genAreaHeader(Version2019))
+	require.NoError(t, err)
+	area := circle.(*Msg8600).Areas[0]
+	assert.Equal(t, []string{"speedLimit", "south", "west"}, AreaAttrNames(area.Attr))
+	assert.Empty(t, area.StartTime)
+
+	_, err = NewAreaSetting(&Geofence{Type: GeofencePolygon, Points: []geo.Point{{Lat: -1, Lng: 0}, {Lat: 1, Lng: 1}, {Lat: 1, Lng: 0}}}, opt, genAreaHeader(Version2019))
+	assert.ErrorIs(t, err, ErrInvalidGeofence)
+	_, err = NewAreaSetting(&Geofence{Type: GeofenceRoute, Points: []geo.Point{{Lat: 0, Lng: 0}, {Lat: 1, Lng: 1}}, Segments: []*RouteSegment{{Width: 300}}}, opt, genAreaHeader(Version2019))
+	assert.ErrorIs(t, err, ErrInvalidGeofence)
+
+	polygon := &Geofence{Type: GeofencePolygon}
+	for i := 0; i < 130; i++ {
+		polygon.Points = append(polygon.Points, geo.Point{Lat: float64(i) / 1000, Lng: float64(i*i) / 1000})
+	}
+	msg, err := NewAreaSetting(polygon, opt, genAreaHeader(Version2019))
+	require.NoError(t, err)
+	_, err = msg.Encode()
+	assert.ErrorIs(t, err, ErrEncodeMsg)
+}
+
+func TestParseAreaAttrs(t *testing.T) {
+	attr, err := ParseAreaAttrs([]string{"enterAlarmDriver", "collectGNSS"})
+	require.NoError(t, err)
+	assert.Equal(t, AreaAttrEnterAlarmDriver|AreaAttrCollectGNSS, attr)
+	assert.Equal(t, []string{"enterAlarmDriver", "collectGNSS"}, AreaAttrNames(attr))
+	_, err = ParseAreaAttrs([]string{"unknown"})
+	assert.ErrorIs(t, err, ErrInvalidGeofence)
+}
+
+func TestNewAreaDelete_EncodeDecode(t *testing.T) {
+	for _, typ := range []GeofenceType{GeofenceCircle, GeofenceRectangle, GeofencePolygon, GeofenceRoute} {
+		msg, err := NewAreaDelete(typ, []uint32{1, 2}, genAreaHeader(Version2019))
+		require.NoError(t, err)
+		pkt, err := msg.Encode()
+		require.NoError(t, err)
+		assert.Equal(t, hex.Str2Byte("02"+"00000001"+"00000002"), pkt[len(pkt)-9:])
+	}
+	_, err := NewAreaDelete("line", nil, genAreaHeader(Version2019))
+	assert.ErrorIs(t, err, ErrInvalidGeofence)
+
+	m := &Msg8601{Header: genMsgHeader(0x8601), IDs: make([]uint32, maxAreaDeleted+1)}
+	_, err = m.Encode()
+	assert.ErrorIs(t, err, ErrEncodeMsg)
+	got := &Msg8601{}
+	assert.ErrorIs(t, got.Decode(&PacketData{Header: m.Header, Body: hex.Str2Byte("0200000001")}), ErrDecodeMsg)
+}
+
+func TestMsg0608_EncodeDecode(t *testing.T) {
+	q := &Msg8608{Header: genMsgHeader(0x8608), AreaType: AreaTypePolygon, IDs: []uint32{3}}
+	gotQ := &Msg8608{}
+	decodeBody(t, q, gotQ)
+	assert.Equal(t, q.IDs, gotQ.IDs)
+
+	answer := &Msg0608{}
+	require.NoError(t, answer.GenOutgoing(gotQ))
+	assert.Equal(t, uint16(0x0608), answer.Header.MsgID)
+	assert.Equal(t, AreaTypePolygon, answer.AreaType)
+	assert.ErrorIs(t, answer.GenOutgoing(&Msg8107{Header: genMsgHeader(0x8107)}), ErrGenOutgoingMsg)
+
+	answer.Polygons = []*PolygonArea{{
+		ID: 3, Attr: AreaAttrSouth, Name: "p",
+		Points: []AreaPoint{{Latitude: 1000000, Longitude: 0}, {Latitude: 1000000, Longitude: 1000000}, {Latitude: 0, Longitude: 0}},
+	}}
+	got := &Msg0608{}
+	decodeBody(t, answer, got)
+	assert.Equal(t, answer.Polygons, got.Polygons)
+	areas := got.Areas()
+	require.Len(t, areas, 1)
+	assert.Equal(t, geo.Point{Lat: -1, Lng: 1}, areas[0].Fence.Points[1])
+	assert.Equal(t, AreaAttrSouth, areas[0].Attr)
+}
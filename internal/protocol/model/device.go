@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/region"
 	"github.com/pkg/errors"
 )
 
@@ -18,6 +19,29 @@ const (
 	DeviceStatusSleeping DeviceStatus = 2
 )
 
+func (s DeviceStatus) String() string {
+	switch s {
+	case DeviceStatusOffline:
+		return "offline"
+	case DeviceStatusOnline:
+		return "online"
+	case DeviceStatusSleeping:
+		return "sleeping"
+	default:
+		return "unknown"
+	}
+}
+
+// 解析String()的输出，无法识别时返回false
+func ParseDeviceStatus(s string) (DeviceStatus, bool) {
+	for _, st := range []DeviceStatus{DeviceStatusOffline, DeviceStatusOnline, DeviceStatusSleeping} {
+		if st.String() == s {
+			return st, true
+		}
+	}
+	return 0, false
+}
+
 // 终端设备的基础属性信息，用于数据缓存、持久化和保活相关流程处理
 type Device struct {
 	ID    string `json:"id"` // ID是否可重复？
@@ -40,6 +64,13 @@ type Device struct {
 	AuthCode        string      `json:"authcode"`
 	IMEI            string      `json:"imei"`
 	SoftwareVersion string      `json:"softwareVersion"` // 终端软件版本号(非jt808协议版本)
+	ProvinceID      uint16      `json:"provinceId"`      // 省域ID
+	CityID          uint16      `json:"cityId"`          // 市县域ID
+	ManufacturerID  string      `json:"manufacturerId"`  // 制造商ID
+	DeviceMode      string      `json:"deviceMode"`      // 终端型号
+	PlateColor      byte        `json:"plateColor"`      // 车牌颜色
+
+	Properties *DeviceProperties `json:"properties,omitempty"` // 终端属性，查询终端属性后更新
 }
 
 func NewDevice(in *Msg0100, session *Session) *Device {
@@ -55,9 +86,19 @@ func NewDevice(in *Msg0100, session *Session) *Device {
 		Status:          DeviceStatusOffline,
 		VersionDesc:     in.Header.Attr.VersionDesc,
 		ProtocolVersion: in.Header.ProtocolVersion,
+		ProvinceID:      in.ProvinceID,
+		CityID:          in.CityID,
+		ManufacturerID:  in.ManufacturerID,
+		DeviceMode:      in.DeviceMode,
+		PlateColor:      in.PlateColor,
 	}
 }
 
+// GB/T 2260 6位行政区划代码，省域ID为前2位，市县域ID为后4位
+func (d *Device) RegionCode() string {
+	return fmt.Sprintf("%02d%04d", d.ProvinceID, d.CityID)
+}
+
 func (d *Device) ShouleTurnOffline() bool {
 	now := time.Now().UnixMilli()
 	return d.Status != DeviceStatusOffline && now > d.Keepalive.Milliseconds()+d.LastestComTime.UnixMilli()
@@ -81,6 +122,11 @@ type DeviceGeo struct {
 	Battery   *Battery    `json:"battery"`   //电池信息
 	CsqLevel  int8        `json:"csq"`       // 信号强度(百分比)
 	Sattelite int8        `json:"satellite"` // 卫星数量
+	Mileage   *float64    `json:"mileage"`   // 里程表读数，km，附加信息0x01，未上报为nil
+
+	Region *region.AdministrativeRegion `json:"region,omitempty"` // 所在行政区，由处理层按边界数据逆地理编码
+
+	Quality []string `json:"quality,omitempty"` // 数据质量过滤标记的问题，为空表示有效
 }
 
 type Battery struct {
@@ -212,6 +258,11 @@ func (dg *DeviceGeo) Decode(phone string, m *Msg0200) error {
 		dg.LBSInfos = LBSs
 	}
 
+	if data, exists := m.AttachData[0x01]; exists && len(data) >= 4 { //里程，1/10km
+		mileage := float64(binary.BigEndian.Uint32(data)) / MileageAccuracy
+		dg.Mileage = &mileage
+	}
+
 	if data, exists := m.AttachData[0x04]; exists && len(data) >= 2 { //电量
 		dg.Battery = &Battery{}
 		dg.Battery.Decode(data)
@@ -230,17 +281,25 @@ func (dg *DeviceGeo) Decode(phone string, m *Msg0200) error {
 const (
 	LocationAccuracy = 1000000
 	SpeedAccuracy    = 10
+	MileageAccuracy  = 10
 )
 
 type Location struct {
-	Latitude  float64 `json:"latitude"`  // 纬度，精确到百万分之一度
-	Longitude float64 `json:"longitude"` // 精度，精确到百万分之一度
+	Latitude  float64 `json:"latitude"`  // 纬度，精确到百万分之一度，南纬为负
+	Longitude float64 `json:"longitude"` // 经度，精确到百万分之一度，西经为负
 	Altitude  uint16  `json:"altitude"`  // 高程，海拔高度，单位为米(m)
 }
 
+// 按状态位的南北纬、东西经标志还原经纬度符号
 func (l *Location) Decode(m *Msg0200) {
 	l.Latitude = float64(m.Latitude) / LocationAccuracy
 	l.Longitude = float64(m.Longitude) / LocationAccuracy
+	if m.StatusSign&LatitudeTypeBit != 0 {
+		l.Latitude = -l.Latitude
+	}
+	if m.StatusSign&LongitudeTypeBit != 0 {
+		l.Longitude = -l.Longitude
+	}
 	l.Altitude = m.Altitude
 }
 
@@ -334,8 +393,8 @@ func (g *GeoMeta) Encode() uint32 {
 	var bitNum uint32
 	bitNum += uint32(g.ACCStatus)
 	bitNum += uint32(g.LocationStatus) << 1
-	bitNum += uint32(g.LongitudeType) << 2
-	bitNum += uint32(g.LatitudeType) << 3
+	bitNum += uint32(g.LatitudeType) << 2
+	bitNum += uint32(g.LongitudeType) << 3
 	bitNum += uint32(g.OperatingStatus) << 4
 	bitNum += uint32(g.GeoEncryptionStatus) << 5
 	bitNum += uint32(g.LoadStatus) << 8
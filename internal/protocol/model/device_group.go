@@ -0,0 +1,42 @@
+package model
+
+import "strings"
+
+// 设备分组(车队)，用于API按分组授权和批量管理
+type DeviceGroup struct {
+	Name   string       `json:"name"`
+	Phones []string     `json:"phones"` // 静态成员，设备手机号
+	Rules  []*GroupRule `json:"rules"`  // 动态规则，满足任一规则的设备也属于该分组
+}
+
+// 分组的动态规则，各非空条件需同时满足
+type GroupRule struct {
+	PlatePrefix    string `json:"platePrefix"`    // 车牌号前缀
+	RegionCode     string `json:"regionCode"`     // 行政区划代码前缀
+	ManufacturerID string `json:"manufacturerId"` // 制造商ID
+}
+
+// 判断设备是否属于该分组
+func (g *DeviceGroup) Match(d *Device) bool {
+	for _, phone := range g.Phones {
+		if phone == d.Phone {
+			return true
+		}
+	}
+	for _, r := range g.Rules {
+		if r.Match(d) {
+			return true
+		}
+	}
+	return false
+}
+
+// 空规则不匹配任何设备，避免误将所有设备加入分组
+func (r *GroupRule) Match(d *Device) bool {
+	if r.PlatePrefix == "" && r.RegionCode == "" && r.ManufacturerID == "" {
+		return false
+	}
+	return strings.HasPrefix(d.Plate, r.PlatePrefix) &&
+		strings.HasPrefix(d.RegionCode(), r.RegionCode) &&
+		(r.ManufacturerID == "" || r.ManufacturerID == d.ManufacturerID)
+}
@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceGroup_Match(t *testing.T) {
+	group := &DeviceGroup{
+		Name:   "fleet-a",
+		Phones: []string{"13800000001"},
+		Rules: []*GroupRule{
+			{PlatePrefix: "粤B", RegionCode: "4403"},
+			{ManufacturerID: "ABCDE"},
+			{},
+		},
+	}
+	tests := []struct {
+		name   string
+		device *Device
+		want   bool
+	}{
+		{name: "static member", device: &Device{Phone: "13800000001"}, want: true},
+		{name: "plate and region", device: &Device{Phone: "2", Plate: "粤B12345", ProvinceID: 44, CityID: 300}, want: true},
+		{name: "plate but other region", device: &Device{Phone: "3", Plate: "粤B12345", ProvinceID: 44, CityID: 100}, want: false},
+		{name: "manufacturer", device: &Device{Phone: "4", ManufacturerID: "ABCDE"}, want: true},
+		{name: "none", device: &Device{Phone: "5", Plate: "京A00001"}, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, group.Match(tt.device))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+var ErrDecodeDeviceProperties = errors.New("Fail to decode device properties")
+
+const iccidLen = 10 // ICCID，BCD[10]
+
+// 终端类型，按位定义
+var terminalTypeBits = []string{
+	0: "passenger",      // 适用客运车辆
+	1: "dangerousGoods", // 适用危险品车辆
+	2: "freight",        // 适用普通货运车辆
+	3: "taxi",           // 适用出租车辆
+	6: "videoRecording", // 支持硬盘录像
+	7: "split",          // 0一体机，1分体机
+	8: "trailer",        // 2019版本，适用挂车
+}
+
+// GNSS模块属性，按位定义
+var gnssBits = []string{"GPS", "BDS", "GLONASS", "Galileo"}
+
+// 通信模块属性，按位定义
+var commBits = []string{"GPRS", "CDMA", "TD-SCDMA", "WCDMA", "CDMA2000", "TD-LTE", "", "other"}
+
+// 终端属性，来自查询终端属性应答0x0107
+type DeviceProperties struct {
+	TerminalType    uint16    `json:"terminalType"`    // 终端类型，按位定义
+	ManufacturerID  string    `json:"manufacturerId"`  // 制造商ID，2013版本5位，2019版本11位
+	TerminalModel   string    `json:"terminalModel"`   // 终端型号，2013版本20位，2019版本30位
+	TerminalID      string    `json:"terminalId"`      // 终端ID，2013版本7位，2019版本30位
+	ICCID           string    `json:"iccid"`           // 终端SIM卡ICCID
+	HardwareVersion string    `json:"hardwareVersion"` // 终端硬件版本号
+	FirmwareVersion string    `json:"firmwareVersion"` // 终端固件版本号
+	GNSSProperty    uint8     `json:"gnssProperty"`    // GNSS模块属性，按位定义
+	CommProperty    uint8     `json:"commProperty"`    // 通信模块属性，按位定义
+	UpdatedAt       time.Time `json:"updatedAt"`       // 平台收到应答的时间
+}
+
+// 制造商ID、终端型号、终端ID的长度
+func propertyFieldLens(ver VersionType) (manuLen, modelLen, idLen int) {
+	if ver == Version2019 {
+		return 11, 30, 30
+	}
+	return 5, 20, 7
+}
+
+func (p *DeviceProperties) Decode(ver VersionType, pkt []byte) error {
+	manuLen, modelLen, idLen := propertyFieldLens(ver)
+	idx := 0
+	fixedLen := 2 + manuLen + modelLen + idLen + iccidLen + 1
+	if len(pkt) < fixedLen {
+		return errors.Wrapf(ErrDecodeDeviceProperties, "body length %d less than %d", len(pkt), fixedLen)
+	}
+	cutset := "\x00"
+	p.TerminalType = hex.ReadWord(pkt, &idx)
+	p.ManufacturerID = strings.TrimRight(hex.ReadString(pkt, &idx, manuLen), cutset)
+	p.TerminalModel = strings.TrimRight(hex.ReadString(pkt, &idx, modelLen), cutset)
+	p.TerminalID = strings.TrimRight(hex.ReadString(pkt, &idx, idLen), cutset)
+	p.ICCID = hex.ReadBCD(pkt, &idx, iccidLen)
+
+	var err error
+	if p.HardwareVersion, err = readLenGBK(pkt, &idx); err != nil {
+		return errors.Wrap(err, "hardware version")
+	}
+	if p.FirmwareVersion, err = readLenGBK(pkt, &idx); err != nil {
+		return errors.Wrap(err, "firmware version")
+	}
+	if len(pkt) < idx+2 {
+		return errors.Wrap(ErrDecodeDeviceProperties, "module properties out of range")
+	}
+	p.GNSSProperty = hex.ReadByte(pkt, &idx)
+	p.CommProperty = hex.ReadByte(pkt, &idx)
+	return nil
+}
+
+// 读取BYTE长度+STRING内容
+func readLenGBK(pkt []byte, idx *int) (string, error) {
+	if len(pkt) < *idx+1 {
+		return "", ErrDecodeDeviceProperties
+	}
+	n := int(hex.ReadByte(pkt, idx))
+	if len(pkt) < *idx+n {
+		return "", ErrDecodeDeviceProperties
+	}
+	return hex.ReadGBK(pkt, idx, n), nil
+}
+
+func (p *DeviceProperties) Encode(ver VersionType) (pkt []byte) {
+	manuLen, modelLen, idLen := propertyFieldLens(ver)
+	pkt = hex.WriteWord(pkt, p.TerminalType)
+	pkt = writeFixedString(pkt, p.ManufacturerID, manuLen)
+	pkt = writeFixedString(pkt, p.TerminalModel, modelLen)
+	pkt = writeFixedString(pkt, p.TerminalID, idLen)
+	iccid := p.ICCID
+	if len(iccid) < iccidLen*2 {
+		iccid = strings.Repeat("0", iccidLen*2-len(iccid)) + iccid
+	}
+	pkt = hex.WriteBCD(pkt, iccid[:iccidLen*2])
+	for _, v := range []string{p.HardwareVersion, p.FirmwareVersion} {
+		b := hex.WriteGBK(nil, v)
+		pkt = hex.WriteByte(pkt, uint8(len(b)))
+		pkt = hex.WriteBytes(pkt, b)
+	}
+	pkt = hex.WriteByte(pkt, p.GNSSProperty)
+	pkt = hex.WriteByte(pkt, p.CommProperty)
+	return pkt
+}
+
+// 写入定长字符串，不足补0x00，超长截断
+func writeFixedString(pkt []byte, s string, n int) []byte {
+	b := make([]byte, n)
+	copy(b, s)
+	return hex.WriteBytes(pkt, b)
+}
+
+func bitNames(v uint32, names []string) []string {
+	res := []string{}
+	for i, name := range names {
+		if name != "" && v&(1<<i) != 0 {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// 终端类型描述，bit7为0时表示一体机，不输出
+func (p *DeviceProperties) TerminalTypes() []string {
+	return bitNames(uint32(p.TerminalType), terminalTypeBits)
+}
+
+// 支持的GNSS定位系统
+func (p *DeviceProperties) GNSS() []string {
+	return bitNames(uint32(p.GNSSProperty), gnssBits)
+}
+
+// 支持的通信方式
+func (p *DeviceProperties) Comm() []string {
+	return bitNames(uint32(p.CommProperty), commBits)
+}
@@ -30,6 +30,34 @@ func Test_location_Decode(t *testing.T) {
 				Altitude:  312,
 			},
 		},
+		{
+			name: "case2: south latitude and west longitude",
+			args: args{
+				m: &Msg0200{
+					StatusSign: LatitudeTypeBit | LongitudeTypeBit,
+					Latitude:   33868820,
+					Longitude:  151209296,
+				},
+			},
+			want: Location{
+				Latitude:  -33.86882,
+				Longitude: -151.209296,
+			},
+		},
+		{
+			name: "case3: west longitude only",
+			args: args{
+				m: &Msg0200{
+					StatusSign: LongitudeTypeBit,
+					Latitude:   51507351,
+					Longitude:  127758,
+				},
+			},
+			want: Location{
+				Latitude:  51.507351,
+				Longitude: -0.127758,
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -39,3 +67,15 @@ func Test_location_Decode(t *testing.T) {
 		})
 	}
 }
+
+func TestGeoMeta_EncodeHemisphere(t *testing.T) {
+	g := &GeoMeta{LatitudeType: 1}
+	assert.Equal(t, LatitudeTypeBit, g.Encode())
+	g = &GeoMeta{LongitudeType: 1}
+	assert.Equal(t, LongitudeTypeBit, g.Encode())
+
+	decoded := &GeoMeta{}
+	decoded.Decode(LatitudeTypeBit | LongitudeTypeBit)
+	assert.Equal(t, uint8(1), decoded.LatitudeType)
+	assert.Equal(t, uint8(1), decoded.LongitudeType)
+}
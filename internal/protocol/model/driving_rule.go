@@ -0,0 +1,55 @@
+package model
+
+// 0x0200报警标志位中平台侧规则可判断的报警
+const (
+	AlarmOverspeed         uint32 = 1 << 1  // 超速报警
+	AlarmFatigue           uint32 = 1 << 2  // 疲劳驾驶报警
+	AlarmOverspeedWarning  uint32 = 1 << 13 // 超速预警
+	AlarmDailyDrivingLimit uint32 = 1 << 18 // 当天累计驾驶超时
+	AlarmParkingTimeout    uint32 = 1 << 19 // 超时停车
+)
+
+// 平台侧驾驶规则，字段含义与单位同终端参数0x0055-0x005B，值为0表示不判断该项
+type DrivingRule struct {
+	MaxSpeed                   uint32 `json:"maxSpeed"`                   // 0x0055 最高速度，km/h
+	OverspeedDuration          uint32 `json:"overspeedDuration"`          // 0x0056 超速持续时间，秒
+	ContinuousDrivingThreshold uint32 `json:"continuousDrivingThreshold"` // 0x0057 连续驾驶时间门限，秒
+	DailyDrivingThreshold      uint32 `json:"dailyDrivingThreshold"`      // 0x0058 当天累计驾驶时间门限，秒
+	MinRestDuration            uint32 `json:"minRestDuration"`            // 0x0059 最小休息时间，秒
+	MaxParkingDuration         uint32 `json:"maxParkingDuration"`         // 0x005A 最长停车时间，秒
+	OverspeedWarningDiff       uint16 `json:"overspeedWarningDiff"`       // 0x005B 超速预警差值，0.1km/h
+}
+
+// 设备的驾驶规则覆盖项，为nil的字段沿用默认规则
+type DrivingRuleOverride struct {
+	MaxSpeed                   *uint32 `json:"maxSpeed"`
+	OverspeedDuration          *uint32 `json:"overspeedDuration"`
+	ContinuousDrivingThreshold *uint32 `json:"continuousDrivingThreshold"`
+	DailyDrivingThreshold      *uint32 `json:"dailyDrivingThreshold"`
+	MinRestDuration            *uint32 `json:"minRestDuration"`
+	MaxParkingDuration         *uint32 `json:"maxParkingDuration"`
+	OverspeedWarningDiff       *uint16 `json:"overspeedWarningDiff"`
+}
+
+// 将覆盖项应用到默认规则，返回新的规则
+func (o *DrivingRuleOverride) Apply(base DrivingRule) DrivingRule {
+	if o == nil {
+		return base
+	}
+	res := base
+	setUint32 := func(dst *uint32, v *uint32) {
+		if v != nil {
+			*dst = *v
+		}
+	}
+	setUint32(&res.MaxSpeed, o.MaxSpeed)
+	setUint32(&res.OverspeedDuration, o.OverspeedDuration)
+	setUint32(&res.ContinuousDrivingThreshold, o.ContinuousDrivingThreshold)
+	setUint32(&res.DailyDrivingThreshold, o.DailyDrivingThreshold)
+	setUint32(&res.MinRestDuration, o.MinRestDuration)
+	setUint32(&res.MaxParkingDuration, o.MaxParkingDuration)
+	if o.OverspeedWarningDiff != nil {
+		res.OverspeedWarningDiff = *o.OverspeedWarningDiff
+	}
+	return res
+}
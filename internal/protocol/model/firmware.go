@@ -0,0 +1,85 @@
+package model
+
+import "time"
+
+// 平台托管的升级包，升级包内容单独存储
+type Firmware struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	UpgradeType    uint8     `json:"upgradeType"`    // 升级类型，0终端，12IC卡读卡器，52北斗卫星定位模块
+	ManufacturerID string    `json:"manufacturerId"` // 制造商ID
+	Version        string    `json:"version"`        // 版本号
+	Size           int       `json:"size"`           // 升级包字节数
+	MD5            string    `json:"md5"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// 升级任务状态
+type UpgradeTaskState string
+
+const (
+	UpgradeTaskRunning   UpgradeTaskState = "running"
+	UpgradeTaskPaused    UpgradeTaskState = "paused"
+	UpgradeTaskAborted   UpgradeTaskState = "aborted"
+	UpgradeTaskCompleted UpgradeTaskState = "completed"
+)
+
+// 单个设备的升级状态
+type DeviceUpgradeState string
+
+const (
+	DeviceUpgradePending   DeviceUpgradeState = "pending"   // 等待下发
+	DeviceUpgradeSending   DeviceUpgradeState = "sending"   // 正在下发分包
+	DeviceUpgradeSent      DeviceUpgradeState = "sent"      // 已下发完成，等待0x0108升级结果
+	DeviceUpgradeSucceeded DeviceUpgradeState = "succeeded" // 终端上报升级成功
+	DeviceUpgradeFailed    DeviceUpgradeState = "failed"    // 下发失败或终端上报升级失败
+	DeviceUpgradeCanceled  DeviceUpgradeState = "canceled"  // 任务终止或终端上报取消
+)
+
+// 是否已结束，结束后不再变化
+func (s DeviceUpgradeState) IsFinal() bool {
+	return s == DeviceUpgradeSucceeded || s == DeviceUpgradeFailed || s == DeviceUpgradeCanceled
+}
+
+type DeviceUpgrade struct {
+	Phone          string             `json:"phone"`
+	State          DeviceUpgradeState `json:"state"`
+	SentFragments  int                `json:"sentFragments"`  // 已收到应答的分包数
+	TotalFragments int                `json:"totalFragments"` // 分包总数
+	Error          string             `json:"error,omitempty"`
+	UpdatedAt      time.Time          `json:"updatedAt"`
+}
+
+// 升级任务，设备按批次下发，每批下发完成后间隔一段时间再下发下一批
+type UpgradeTask struct {
+	ID            string           `json:"id"`
+	FirmwareID    string           `json:"firmwareId"`
+	State         UpgradeTaskState `json:"state"`
+	BatchSize     int              `json:"batchSize"`     // 每批设备数
+	BatchInterval time.Duration    `json:"batchInterval"` // 批次间隔
+	MaxFailures   int              `json:"maxFailures"`   // 失败设备数超过该值时自动暂停，0表示不限制
+	Operator      string           `json:"operator"`      // 创建任务的调用方
+	Devices       []*DeviceUpgrade `json:"devices"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+}
+
+// 各状态的设备数
+func (t *UpgradeTask) CountByState() map[DeviceUpgradeState]int {
+	res := make(map[DeviceUpgradeState]int)
+	for _, d := range t.Devices {
+		res[d.State]++
+	}
+	return res
+}
+
+// 深拷贝，用于在锁外读取
+func (t *UpgradeTask) Clone() *UpgradeTask {
+	c := *t
+	c.Devices = make([]*DeviceUpgrade, 0, len(t.Devices))
+	for _, d := range t.Devices {
+		dc := *d
+		c.Devices = append(c.Devices, &dc)
+	}
+	return &c
+}
@@ -0,0 +1,56 @@
+package model
+
+// 消息体最大长度，消息体属性中长度字段为10位
+const MaxBodyLength = 1023
+
+// 平台下发的单个分包，消息体为原消息体的一段
+type MsgFragment struct {
+	Header *MsgHeader `json:"header"`
+	Body   []byte     `json:"-"`
+}
+
+func (m *MsgFragment) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	m.Body = packet.Body
+	return nil
+}
+
+func (m *MsgFragment) Encode() (pkt []byte, err error) {
+	pkt = append(pkt, m.Body...)
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *MsgFragment) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *MsgFragment) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
+
+// 将消息体按size切分为分包，每个分包使用nextSerialNum分配的流水号，包序号从1开始
+func SplitBody(header *MsgHeader, body []byte, size int, nextSerialNum func() uint16) []*MsgFragment {
+	if size <= 0 || size > MaxBodyLength {
+		size = MaxBodyLength
+	}
+	total := (len(body) + size - 1) / size
+	res := make([]*MsgFragment, 0, total)
+	for i := 0; i < total; i++ {
+		end := (i + 1) * size
+		if end > len(body) {
+			end = len(body)
+		}
+		attr := *header.Attr
+		attr.PacketFragmented = 1
+		attr.PacketFragmentedDesc = PacketFragmentedTrue
+		h := *header
+		h.Attr = &attr
+		h.SerialNumber = nextSerialNum()
+		h.Frag = &MsgFragmentation{Total: uint16(total), Index: uint16(i + 1)}
+		res = append(res, &MsgFragment{Header: &h, Body: body[i*size : end]})
+	}
+	return res
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+)
+
+var ErrInvalidGeofence = errors.New("Invalid geofence")
+
+type GeofenceType string
+
+const (
+	GeofenceCircle    GeofenceType = "circle"    // 圆形区域
+	GeofenceRectangle GeofenceType = "rectangle" // 矩形区域
+	GeofencePolygon   GeofenceType = "polygon"   // 多边形区域
+	GeofenceRoute     GeofenceType = "route"     // 路线，由拐点和各路段宽度构成的走廊
+)
+
+// 平台侧电子围栏，ID与下发到终端的区域/路线ID一致
+type Geofence struct {
+	ID       uint32          `json:"id"`
+	Name     string          `json:"name"`
+	Type     GeofenceType    `json:"type"`
+	Center   *geo.Point      `json:"center,omitempty"`   // 圆心，圆形区域
+	Radius   float64         `json:"radius,omitempty"`   // 半径，米，圆形区域
+	Points   []geo.Point     `json:"points,omitempty"`   // 矩形为左上、右下两点，多边形为顶点，路线为拐点
+	Segments []*RouteSegment `json:"segments,omitempty"` // 路段，路线的第i段连接第i和第i+1个拐点
+	MaxSpeed float64         `json:"maxSpeed"`           // 区域内最高速度，km/h，0表示不限速；路线中路段未设置时使用
+	Phones   []string        `json:"phones"`             // 适用的设备
+	Groups   []string        `json:"groups"`             // 适用的设备分组
+
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type RouteSegment struct {
+	Width    float64 `json:"width"`    // 路段宽度，米，偏离中心线超过一半宽度即为偏离路线
+	MaxSpeed float64 `json:"maxSpeed"` // 路段最高速度，km/h，0表示使用路线的maxSpeed
+}
+
+func (f *Geofence) Validate() error {
+	switch f.Type {
+	case GeofenceCircle:
+		if f.Center == nil || f.Radius <= 0 {
+			return errors.Wrap(ErrInvalidGeofence, "circle requires center and positive radius")
+		}
+	case GeofenceRectangle:
+		if len(f.Points) != 2 || f.Points[0].Lat < f.Points[1].Lat || f.Points[0].Lng > f.Points[1].Lng {
+			return errors.Wrap(ErrInvalidGeofence, "rectangle requires left-top and right-bottom points")
+		}
+	case GeofencePolygon:
+		if len(f.Points) < 3 {
+			return errors.Wrap(ErrInvalidGeofence, "polygon requires at least 3 points")
+		}
+	case GeofenceRoute:
+		if len(f.Points) < 2 || len(f.Segments) != len(f.Points)-1 {
+			return errors.Wrap(ErrInvalidGeofence, "route requires at least 2 points and one segment between each pair")
+		}
+		for i, s := range f.Segments {
+			if s == nil || s.Width <= 0 {
+				return errors.Wrapf(ErrInvalidGeofence, "segment %d requires positive width", i)
+			}
+		}
+	default:
+		return errors.Wrapf(ErrInvalidGeofence, "type %s is not supported", f.Type)
+	}
+	points := f.Points
+	if f.Center != nil {
+		points = append([]geo.Point{*f.Center}, points...)
+	}
+	for _, p := range points {
+		if p.Lat < -90 || p.Lat > 90 || p.Lng < -180 || p.Lng > 180 {
+			return errors.Wrapf(ErrInvalidGeofence, "point (%f, %f) out of range", p.Lat, p.Lng)
+		}
+	}
+	return nil
+}
+
+// 判断点是否在围栏内，在内时返回该位置的最高速度，0表示不限速。
+// 路线以最近路段判断是否偏离
+func (f *Geofence) Locate(p geo.Point) (inside bool, maxSpeed float64) {
+	switch f.Type {
+	case GeofenceCircle:
+		inside = f.Center != nil && geo.InCircle(p, *f.Center, f.Radius)
+	case GeofenceRectangle:
+		inside = len(f.Points) == 2 && geo.InRect(p, f.Points[0], f.Points[1])
+	case GeofencePolygon:
+		inside = geo.InPolygon(p, f.Points)
+	case GeofenceRoute:
+		nearest := -1
+		var minDist float64
+		for i := 0; i+1 < len(f.Points) && i < len(f.Segments); i++ {
+			d := geo.DistanceToSegment(p, f.Points[i], f.Points[i+1])
+			if d <= f.Segments[i].Width/2 && (nearest < 0 || d < minDist) {
+				nearest, minDist = i, d
+			}
+		}
+		if nearest < 0 {
+			return false, 0
+		}
+		if s := f.Segments[nearest]; s.MaxSpeed > 0 {
+			return true, s.MaxSpeed
+		}
+		return true, f.MaxSpeed
+	}
+	if !inside {
+		return false, 0
+	}
+	return true, f.MaxSpeed
+}
+
+// 判断围栏是否适用于设备，groups为设备所属分组
+func (f *Geofence) AppliesTo(phone string, groups []string) bool {
+	for _, p := range f.Phones {
+		if p == phone {
+			return true
+		}
+	}
+	for _, g := range f.Groups {
+		for _, dg := range groups {
+			if g == dg {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+)
+
+func TestGeofence_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		fence   *Geofence
+		wantErr bool
+	}{
+		{
+			name:  "circle",
+			fence: &Geofence{Type: GeofenceCircle, Center: &geo.Point{Lat: 22.5, Lng: 114}, Radius: 100},
+		},
+		{
+			name:    "circle without radius",
+			fence:   &Geofence{Type: GeofenceCircle, Center: &geo.Point{Lat: 22.5, Lng: 114}},
+			wantErr: true,
+		},
+		{
+			name:  "rectangle",
+			fence: &Geofence{Type: GeofenceRectangle, Points: []geo.Point{{Lat: 23, Lng: 113}, {Lat: 22, Lng: 114}}},
+		},
+		{
+			name:    "rectangle reversed",
+			fence:   &Geofence{Type: GeofenceRectangle, Points: []geo.Point{{Lat: 22, Lng: 114}, {Lat: 23, Lng: 113}}},
+			wantErr: true,
+		},
+		{
+			name:    "polygon with two points",
+			fence:   &Geofence{Type: GeofencePolygon, Points: []geo.Point{{Lat: 0, Lng: 0}, {Lat: 1, Lng: 1}}},
+			wantErr: true,
+		},
+		{
+			name:    "polygon out of range",
+			fence:   &Geofence{Type: GeofencePolygon, Points: []geo.Point{{Lat: 0, Lng: 0}, {Lat: 1, Lng: 1}, {Lat: 91, Lng: 0}}},
+			wantErr: true,
+		},
+		{
+			name: "route",
+			fence: &Geofence{Type: GeofenceRoute, Points: []geo.Point{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 1}},
+				Segments: []*RouteSegment{{Width: 100}}},
+		},
+		{
+			name:    "route without segment",
+			fence:   &Geofence{Type: GeofenceRoute, Points: []geo.Point{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 1}}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			fence:   &Geofence{Type: "line"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.fence.Validate()
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrInvalidGeofence))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestGeofence_Locate(t *testing.T) {
+	circle := &Geofence{Type: GeofenceCircle, Center: &geo.Point{Lat: 22.54, Lng: 114.05}, Radius: 100, MaxSpeed: 30}
+	inside, maxSpeed := circle.Locate(geo.Point{Lat: 22.5405, Lng: 114.05})
+	assert.True(t, inside)
+	assert.Equal(t, float64(30), maxSpeed)
+	inside, maxSpeed = circle.Locate(geo.Point{Lat: 22.55, Lng: 114.05})
+	assert.False(t, inside)
+	assert.Zero(t, maxSpeed)
+
+	route := &Geofence{
+		Type:     GeofenceRoute,
+		Points:   []geo.Point{{Lat: 0, Lng: 0}, {Lat: 0, Lng: 0.01}, {Lat: 0.01, Lng: 0.01}},
+		Segments: []*RouteSegment{{Width: 100, MaxSpeed: 60}, {Width: 100}},
+		MaxSpeed: 40,
+	}
+	inside, maxSpeed = route.Locate(geo.Point{Lat: 0.0002, Lng: 0.005}) // 距第一段约22米
+	assert.True(t, inside)
+	assert.Equal(t, float64(60), maxSpeed)
+	inside, maxSpeed = route.Locate(geo.Point{Lat: 0.005, Lng: 0.0102}) // 距第二段约22米，使用路线限速
+	assert.True(t, inside)
+	assert.Equal(t, float64(40), maxSpeed)
+	inside, _ = route.Locate(geo.Point{Lat: 0.005, Lng: 0.005})
+	assert.False(t, inside)
+}
+
+func TestGeofence_AppliesTo(t *testing.T) {
+	f := &Geofence{Phones: []string{"13800000001"}, Groups: []string{"fleet"}}
+	assert.True(t, f.AppliesTo("13800000001", nil))
+	assert.True(t, f.AppliesTo("13800000002", []string{"other", "fleet"}))
+	assert.False(t, f.AppliesTo("13800000002", []string{"other"}))
+}
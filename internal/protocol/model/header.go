@@ -124,6 +124,29 @@ const (
 	Version2019 VersionType = 2
 )
 
+func (v VersionType) String() string {
+	switch v {
+	case Version2011:
+		return "2011"
+	case Version2013:
+		return "2013"
+	case Version2019:
+		return "2019"
+	default:
+		return "unknown"
+	}
+}
+
+// 解析String()的输出，无法识别时返回false
+func ParseVersionType(s string) (VersionType, bool) {
+	for _, v := range []VersionType{Version2011, Version2013, Version2019} {
+		if v.String() == s {
+			return v, true
+		}
+	}
+	return 0, false
+}
+
 // 定义消息体属性
 type MsgBodyAttr struct {
 	BodyLength       uint16 `json:"bodyLength"`       // 消息体长度
@@ -213,6 +236,7 @@ func GenMsgHeader(d *Device, msgID, serialNumber uint16) *MsgHeader {
 			PacketFragmented: 0,
 			VersionSign:      versionDecode(d.VersionDesc),
 			Extra:            0,
+			VersionDesc:      d.VersionDesc,
 		},
 		ProtocolVersion: d.ProtocolVersion,
 		PhoneNumber:     d.Phone,
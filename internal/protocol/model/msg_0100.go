@@ -23,7 +23,7 @@ type Msg0100 struct {
 	PlateColor byte `json:"plateColor"`
 
 	PlateNumber  string `json:"plateNumber"`  // 车牌号
-	LocationDesc string `json:"locationDesc"` // 省市地域中文名称，通过GBT2260解析
+	LocationDesc string `json:"locationDesc"` // 省市地域中文名称，通过GBT2260解析为xx省[xx市][xx区]
 }
 
 func (m *Msg0100) Decode(packet *PacketData) error {
@@ -55,7 +55,7 @@ func (m *Msg0100) Decode(packet *PacketData) error {
 
 	m.PlateColor = hex.ReadByte(pkt, &idx)
 	m.PlateNumber = hex.ReadGBK(pkt, &idx, int(m.Header.Attr.BodyLength)-idx)
-	m.LocationDesc = region.Parse(fmt.Sprintf("%02d%04d", m.ProvinceID, m.CityID)).Name
+	m.LocationDesc = region.Parse(fmt.Sprintf("%02d%04d", m.ProvinceID, m.CityID)).FullName
 
 	return nil
 }
@@ -48,13 +48,16 @@ func (m *Msg0104) GetHeader() *MsgHeader {
 	return m.Header
 }
 
+// 应答查询终端参数0x8104或查询指定终端参数0x8106
 func (m *Msg0104) GenOutgoing(incoming JT808Msg) error {
-	in, ok := incoming.(*Msg8104)
-	if !ok {
+	switch incoming.(type) {
+	case *Msg8104, *Msg8106:
+	default:
 		return ErrGenOutgoingMsg
 	}
-	m.AnswerSerialNumber = in.Header.SerialNumber
-	m.Header = in.Header
+	in := incoming.GetHeader()
+	m.AnswerSerialNumber = in.SerialNumber
+	m.Header = in
 	m.Header.MsgID = 0x0104
 
 	return nil
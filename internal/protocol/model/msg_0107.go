@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/rs/zerolog/log"
+)
+
+// 查询终端属性应答
+type Msg0107 struct {
+	Header     *MsgHeader        `json:"header"`
+	Properties *DeviceProperties `json:"properties"`
+}
+
+func (m *Msg0107) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	m.Properties = &DeviceProperties{}
+	err := m.Properties.Decode(m.Header.Attr.VersionDesc, packet.Body)
+	if err != nil {
+		log.Error().Err(err).Str("device", m.Header.PhoneNumber).Msg("Fail to decode device properties")
+		return ErrDecodeMsg
+	}
+	return nil
+}
+
+func (m *Msg0107) Encode() (pkt []byte, err error) {
+	pkt = m.Properties.Encode(m.Header.Attr.VersionDesc)
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg0107) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg0107) GenOutgoing(incoming JT808Msg) error {
+	if _, ok := incoming.(*Msg8107); !ok {
+		return ErrGenOutgoingMsg
+	}
+	m.Header = incoming.GetHeader()
+	m.Header.MsgID = 0x0107
+	return nil
+}
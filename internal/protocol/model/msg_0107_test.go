@@ -0,0 +1,68 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+func TestMsg8106_EncodeDecode(t *testing.T) {
+	m := &Msg8106{Header: genMsgHeader(0x8106), ParamCnt: 2, ParamIDs: []uint32{0x0001, 0x0013}}
+	pkt, err := m.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, hex.Str2Byte("8106400901123456789012345678900001"+"02"+"00000001"+"00000013"), pkt)
+
+	got := &Msg8106{}
+	require.NoError(t, got.Decode(&PacketData{Header: m.Header, Body: pkt[len(pkt)-9:]}))
+	assert.Equal(t, m.ParamIDs, got.ParamIDs)
+
+	err = got.Decode(&PacketData{Header: m.Header, Body: hex.Str2Byte("0200000001")})
+	assert.ErrorIs(t, err, ErrDecodeMsg)
+}
+
+func TestDeviceProperties_EncodeDecode(t *testing.T) {
+	props := &DeviceProperties{
+		TerminalType:    0b101,
+		ManufacturerID:  "ABCDE",
+		TerminalModel:   "model-1",
+		TerminalID:      "T00001",
+		ICCID:           "89860012345678901234",
+		HardwareVersion: "HW1.0",
+		FirmwareVersion: "固件2.1",
+		GNSSProperty:    0b11,
+		CommProperty:    0b10000001,
+	}
+	tests := []struct {
+		name    string
+		ver     VersionType
+		bodyLen int
+	}{
+		{name: "2013", ver: Version2013, bodyLen: 2 + 5 + 20 + 7 + 10 + 1 + 5 + 1 + 7 + 1 + 1},
+		{name: "2019", ver: Version2019, bodyLen: 2 + 11 + 30 + 30 + 10 + 1 + 5 + 1 + 7 + 1 + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkt := props.Encode(tt.ver)
+			assert.Len(t, pkt, tt.bodyLen)
+
+			got := &DeviceProperties{}
+			require.NoError(t, got.Decode(tt.ver, pkt))
+			assert.Equal(t, props, got)
+			assert.Equal(t, []string{"passenger", "freight"}, got.TerminalTypes())
+			assert.Equal(t, []string{"GPS", "BDS"}, got.GNSS())
+			assert.Equal(t, []string{"GPRS", "other"}, got.Comm())
+
+			assert.ErrorIs(t, got.Decode(tt.ver, pkt[:tt.bodyLen-3]), ErrDecodeDeviceProperties)
+		})
+	}
+}
+
+func TestMsg0107_GenOutgoing(t *testing.T) {
+	m := &Msg0107{}
+	require.NoError(t, m.GenOutgoing(&Msg8107{Header: genMsgHeader(0x8107)}))
+	assert.Equal(t, uint16(0x0107), m.Header.MsgID)
+	assert.ErrorIs(t, m.GenOutgoing(&Msg8104{Header: genMsgHeader(0x8104)}), ErrGenOutgoingMsg)
+}
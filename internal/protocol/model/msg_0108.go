@@ -0,0 +1,58 @@
+package model
+
+import (
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 升级结果
+const (
+	UpgradeResultSuccess  uint8 = 0 // 成功
+	UpgradeResultFail     uint8 = 1 // 失败
+	UpgradeResultCanceled uint8 = 2 // 取消
+)
+
+// 终端升级结果通知
+type Msg0108 struct {
+	Header      *MsgHeader `json:"header"`
+	UpgradeType uint8      `json:"upgradeType"` // 升级类型
+	Result      uint8      `json:"result"`      // 升级结果，0成功，1失败，2取消
+}
+
+func (m *Msg0108) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	pkt, idx := packet.Body, 0
+	if len(pkt) < 2 {
+		return ErrDecodeMsg
+	}
+	m.UpgradeType = hex.ReadByte(pkt, &idx)
+	m.Result = hex.ReadByte(pkt, &idx)
+	return nil
+}
+
+func (m *Msg0108) Encode() (pkt []byte, err error) {
+	pkt = hex.WriteByte(pkt, m.UpgradeType)
+	pkt = hex.WriteByte(pkt, m.Result)
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg0108) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+// 模拟终端收到升级包后直接上报升级成功
+func (m *Msg0108) GenOutgoing(incoming JT808Msg) error {
+	in, ok := incoming.(*Msg8108)
+	if !ok {
+		return ErrGenOutgoingMsg
+	}
+	m.Header = in.GetHeader()
+	m.Header.MsgID = 0x0108
+	m.Header.Attr.PacketFragmented = 0
+	m.Header.Attr.PacketFragmentedDesc = PacketFragmentedFalse
+	m.Header.Frag = nil
+	m.UpgradeType = in.UpgradeType
+	m.Result = UpgradeResultSuccess
+	return nil
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 查询区域或线路数据应答，2019版本，按查询类型填写对应的区域列表
+type Msg0608 struct {
+	Header   *MsgHeader     `json:"header"`
+	AreaType uint8          `json:"areaType"`           // 查询类型
+	Circles  []*CircleArea  `json:"circles,omitempty"`  // 圆形区域
+	Rects    []*RectArea    `json:"rects,omitempty"`    // 矩形区域
+	Polygons []*PolygonArea `json:"polygons,omitempty"` // 多边形区域
+	Routes   []*Route       `json:"routes,omitempty"`   // 路线
+}
+
+func (m *Msg0608) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	pkt, idx := packet.Body, 0
+	ver := m.Header.Attr.VersionDesc
+	if err := hasLen(pkt, idx, 5); err != nil {
+		return err
+	}
+	m.AreaType = hex.ReadByte(pkt, &idx)
+	m.Circles, m.Rects, m.Polygons, m.Routes = nil, nil, nil, nil
+	cnt := int(hex.ReadDoubleWord(pkt, &idx))
+	for i := 0; i < cnt; i++ {
+		var err error
+		switch m.AreaType {
+		case AreaTypeCircle:
+			a := &CircleArea{}
+			err = a.decode(pkt, &idx, ver)
+			m.Circles = append(m.Circles, a)
+		case AreaTypeRectangle:
+			a := &RectArea{}
+			err = a.decode(pkt, &idx, ver)
+			m.Rects = append(m.Rects, a)
+		case AreaTypePolygon:
+			a := &PolygonArea{}
+			err = a.decode(pkt, &idx, ver)
+			m.Polygons = append(m.Polygons, a)
+		case AreaTypeRoute:
+			r := &Route{}
+			err = r.decode(pkt, &idx, ver)
+			m.Routes = append(m.Routes, r)
+		default:
+			return ErrDecodeMsg
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Msg0608) Encode() (pkt []byte, err error) {
+	ver := m.Header.Attr.VersionDesc
+	pkt = hex.WriteByte(pkt, m.AreaType)
+	pkt = hex.WriteDoubleWord(pkt, uint32(len(m.Circles)+len(m.Rects)+len(m.Polygons)+len(m.Routes)))
+	for _, a := range m.Circles {
+		pkt = a.encode(pkt, ver)
+	}
+	for _, a := range m.Rects {
+		pkt = a.encode(pkt, ver)
+	}
+	for _, a := range m.Polygons {
+		pkt = a.encode(pkt, ver)
+	}
+	for _, r := range m.Routes {
+		pkt = r.encode(pkt, ver)
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg0608) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+// 模拟终端未保存区域，应答空列表
+func (m *Msg0608) GenOutgoing(incoming JT808Msg) error {
+	in, ok := incoming.(*Msg8608)
+	if !ok {
+		return ErrGenOutgoingMsg
+	}
+	m.Header = in.GetHeader()
+	m.Header.MsgID = 0x0608
+	m.AreaType = in.AreaType
+	return nil
+}
+
+// 应答中的区域及其属性位
+type TerminalArea struct {
+	Fence *Geofence
+	Attr  uint16
+}
+
+// 按查询类型转为平台侧围栏的形状
+func (m *Msg0608) Areas() []*TerminalArea {
+	res := make([]*TerminalArea, 0)
+	for _, a := range m.Circles {
+		res = append(res, &TerminalArea{Fence: a.Geofence(), Attr: a.Attr})
+	}
+	for _, a := range m.Rects {
+		res = append(res, &TerminalArea{Fence: a.Geofence(), Attr: a.Attr})
+	}
+	for _, a := range m.Polygons {
+		res = append(res, &TerminalArea{Fence: a.Geofence(), Attr: a.Attr})
+	}
+	for _, r := range m.Routes {
+		res = append(res, &TerminalArea{Fence: r.Geofence(), Attr: r.Attr})
+	}
+	return res
+}
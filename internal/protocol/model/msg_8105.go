@@ -0,0 +1,213 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+var ErrDecodeControlParam = errors.New("Fail to decode terminal control param")
+
+// 终端控制命令字
+const (
+	ControlWirelessUpgrade uint8 = 1 // 无线升级
+	ControlConnectServer   uint8 = 2 // 控制终端连接指定服务器
+	ControlPowerOff        uint8 = 3 // 终端关机
+	ControlReset           uint8 = 4 // 终端复位
+	ControlFactoryReset    uint8 = 5 // 终端恢复出厂设置
+	ControlCloseDataComm   uint8 = 6 // 关闭数据通信
+	ControlCloseWireless   uint8 = 7 // 关闭所有无线通信
+)
+
+// 连接控制
+const (
+	ConnectToSpecified uint8 = 0 // 切换到指定监管平台服务器
+	ConnectToOriginal  uint8 = 1 // 切换回原缺省监控平台服务器
+)
+
+const ControlParamSep = ";" // 命令参数之间的分隔符，参数值中不能包含
+
+// 无线升级参数，各字段以半角分号分隔，无值的字段放空
+type WirelessUpgradeParam struct {
+	URL             string `json:"url"`             // URL地址
+	APN             string `json:"apn"`             // 拨号点名称
+	DialUser        string `json:"dialUser"`        // 拨号用户名
+	DialPassword    string `json:"dialPassword"`    // 拨号密码
+	Address         string `json:"address"`         // 服务器地址，IP或域名
+	TCPPort         uint16 `json:"tcpPort"`         // TCP端口，为0时放空
+	UDPPort         uint16 `json:"udpPort"`         // UDP端口，为0时放空
+	ManufacturerID  string `json:"manufacturerId"`  // 制造商ID
+	HardwareVersion string `json:"hardwareVersion"` // 硬件版本
+	FirmwareVersion string `json:"firmwareVersion"` // 固件版本
+	TimeLimit       uint16 `json:"timeLimit"`       // 连接到指定服务器时限，分钟，为0时放空
+}
+
+func (p *WirelessUpgradeParam) String() string {
+	return strings.Join([]string{
+		p.URL,
+		p.APN,
+		p.DialUser,
+		p.DialPassword,
+		p.Address,
+		formatOptionalUint(p.TCPPort),
+		formatOptionalUint(p.UDPPort),
+		p.ManufacturerID,
+		p.HardwareVersion,
+		p.FirmwareVersion,
+		formatOptionalUint(p.TimeLimit),
+	}, ControlParamSep)
+}
+
+func (p *WirelessUpgradeParam) Parse(s string) (err error) {
+	fields := splitControlParam(s, 11)
+	p.URL = fields[0]
+	p.APN = fields[1]
+	p.DialUser = fields[2]
+	p.DialPassword = fields[3]
+	p.Address = fields[4]
+	if p.TCPPort, err = parseOptionalUint(fields[5]); err != nil {
+		return err
+	}
+	if p.UDPPort, err = parseOptionalUint(fields[6]); err != nil {
+		return err
+	}
+	p.ManufacturerID = fields[7]
+	p.HardwareVersion = fields[8]
+	p.FirmwareVersion = fields[9]
+	p.TimeLimit, err = parseOptionalUint(fields[10])
+	return err
+}
+
+// 连接指定服务器参数，连接控制为1时无后续参数
+type ConnectServerParam struct {
+	Control      uint8  `json:"control"`      // 连接控制，见ConnectToSpecified、ConnectToOriginal
+	AuthCode     string `json:"authCode"`     // 监管平台鉴权码
+	APN          string `json:"apn"`          // 拨号点名称
+	DialUser     string `json:"dialUser"`     // 拨号用户名
+	DialPassword string `json:"dialPassword"` // 拨号密码
+	Address      string `json:"address"`      // 服务器地址，IP或域名
+	TCPPort      uint16 `json:"tcpPort"`      // TCP端口，为0时放空
+	UDPPort      uint16 `json:"udpPort"`      // UDP端口，为0时放空
+	TimeLimit    uint16 `json:"timeLimit"`    // 连接到指定服务器时限，分钟，为0时放空
+}
+
+func (p *ConnectServerParam) String() string {
+	if p.Control == ConnectToOriginal {
+		return strconv.Itoa(int(p.Control))
+	}
+	return strings.Join([]string{
+		strconv.Itoa(int(p.Control)),
+		p.AuthCode,
+		p.APN,
+		p.DialUser,
+		p.DialPassword,
+		p.Address,
+		formatOptionalUint(p.TCPPort),
+		formatOptionalUint(p.UDPPort),
+		formatOptionalUint(p.TimeLimit),
+	}, ControlParamSep)
+}
+
+func (p *ConnectServerParam) Parse(s string) error {
+	fields := splitControlParam(s, 9)
+	control, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return errors.Wrapf(ErrDecodeControlParam, "invalid connect control %q", fields[0])
+	}
+	p.Control = uint8(control)
+	if p.Control == ConnectToOriginal {
+		return nil
+	}
+	p.AuthCode = fields[1]
+	p.APN = fields[2]
+	p.DialUser = fields[3]
+	p.DialPassword = fields[4]
+	p.Address = fields[5]
+	if p.TCPPort, err = parseOptionalUint(fields[6]); err != nil {
+		return err
+	}
+	if p.UDPPort, err = parseOptionalUint(fields[7]); err != nil {
+		return err
+	}
+	p.TimeLimit, err = parseOptionalUint(fields[8])
+	return err
+}
+
+// 按分号拆分参数，不足n个字段时补空
+func splitControlParam(s string, n int) []string {
+	fields := strings.Split(s, ControlParamSep)
+	for len(fields) < n {
+		fields = append(fields, "")
+	}
+	return fields
+}
+
+func formatOptionalUint(v uint16) string {
+	if v == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(v))
+}
+
+func parseOptionalUint(s string) (uint16, error) {
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, errors.Wrapf(ErrDecodeControlParam, "invalid number %q", s)
+	}
+	return uint16(v), nil
+}
+
+// 终端控制，终端以0x0001通用应答
+type Msg8105 struct {
+	Header  *MsgHeader            `json:"header"`
+	Command uint8                 `json:"command"`           // 命令字
+	Upgrade *WirelessUpgradeParam `json:"upgrade,omitempty"` // 命令字为1时的参数
+	Connect *ConnectServerParam   `json:"connect,omitempty"` // 命令字为2时的参数
+}
+
+func (m *Msg8105) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	pkt, idx := packet.Body, 0
+	if len(pkt) < 1 {
+		return ErrDecodeMsg
+	}
+	m.Command = hex.ReadByte(pkt, &idx)
+	param := hex.ReadGBK(pkt, &idx, len(pkt)-idx)
+	switch m.Command {
+	case ControlWirelessUpgrade:
+		m.Upgrade = &WirelessUpgradeParam{}
+		return m.Upgrade.Parse(param)
+	case ControlConnectServer:
+		m.Connect = &ConnectServerParam{}
+		return m.Connect.Parse(param)
+	}
+	return nil
+}
+
+func (m *Msg8105) Encode() (pkt []byte, err error) {
+	pkt = hex.WriteByte(pkt, m.Command)
+	switch {
+	case m.Command == ControlWirelessUpgrade && m.Upgrade != nil:
+		pkt = hex.WriteGBK(pkt, m.Upgrade.String())
+	case m.Command == ControlConnectServer && m.Connect != nil:
+		pkt = hex.WriteGBK(pkt, m.Connect.String())
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8105) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8105) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMsg8105_EncodeDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		msg       *Msg8105
+		wantParam string
+	}{
+		{
+			name:      "reset",
+			msg:       &Msg8105{Command: ControlReset},
+			wantParam: "",
+		},
+		{
+			name: "wireless upgrade",
+			msg: &Msg8105{Command: ControlWirelessUpgrade, Upgrade: &WirelessUpgradeParam{
+				URL:             "http://ota.example.com/fw.bin",
+				Address:         "10.0.0.1",
+				TCPPort:         8080,
+				ManufacturerID:  "ABCDE",
+				FirmwareVersion: "固件2.1",
+				TimeLimit:       30,
+			}},
+			wantParam: "http://ota.example.com/fw.bin;;;;10.0.0.1;8080;;ABCDE;;固件2.1;30",
+		},
+		{
+			name: "connect specified server",
+			msg: &Msg8105{Command: ControlConnectServer, Connect: &ConnectServerParam{
+				Control:  ConnectToSpecified,
+				AuthCode: "auth",
+				APN:      "cmnet",
+				Address:  "jt808.example.com",
+				TCPPort:  8808,
+				UDPPort:  8808,
+			}},
+			wantParam: "0;auth;cmnet;;;jt808.example.com;8808;8808;",
+		},
+		{
+			name:      "connect original server",
+			msg:       &Msg8105{Command: ControlConnectServer, Connect: &ConnectServerParam{Control: ConnectToOriginal, Address: "ignored"}},
+			wantParam: "1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.msg.Header = genMsgHeader(0x8105)
+			pkt, err := tt.msg.Encode()
+			require.NoError(t, err)
+
+			header := &MsgHeader{}
+			require.NoError(t, header.Decode(pkt))
+			body := pkt[len(pkt)-int(header.Attr.BodyLength):]
+			assert.Equal(t, tt.msg.Command, body[0])
+
+			got := &Msg8105{}
+			require.NoError(t, got.Decode(&PacketData{Header: header, Body: body}))
+			assert.Equal(t, tt.msg.Command, got.Command)
+			switch tt.msg.Command {
+			case ControlWirelessUpgrade:
+				assert.Equal(t, tt.wantParam, got.Upgrade.String())
+				assert.Equal(t, tt.msg.Upgrade, got.Upgrade)
+			case ControlConnectServer:
+				assert.Equal(t, tt.wantParam, got.Connect.String())
+				assert.Equal(t, tt.msg.Connect.Control, got.Connect.Control)
+			default:
+				assert.Len(t, body, 1)
+			}
+		})
+	}
+}
+
+func TestConnectServerParam_Parse(t *testing.T) {
+	p := &ConnectServerParam{}
+	require.NoError(t, p.Parse("0;auth;cmnet;;;10.0.0.1;8808"))
+	assert.Equal(t, "10.0.0.1", p.Address)
+	assert.Equal(t, uint16(8808), p.TCPPort)
+	assert.Zero(t, p.UDPPort)
+
+	assert.ErrorIs(t, p.Parse("0;auth;cmnet;;;10.0.0.1;port"), ErrDecodeControlParam)
+	assert.ErrorIs(t, p.Parse("x"), ErrDecodeControlParam)
+}
@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 查询指定终端参数，终端以0x0104应答
+type Msg8106 struct {
+	Header   *MsgHeader `json:"header"`
+	ParamCnt uint8      `json:"paramCnt"` // 参数总数
+	ParamIDs []uint32   `json:"paramIds"` // 参数ID列表
+}
+
+func (m *Msg8106) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	pkt, idx := packet.Body, 0
+	m.ParamCnt = hex.ReadByte(pkt, &idx)
+	if len(pkt) < idx+int(m.ParamCnt)*4 {
+		return ErrDecodeMsg
+	}
+	m.ParamIDs = make([]uint32, 0, m.ParamCnt)
+	for i := 0; i < int(m.ParamCnt); i++ {
+		m.ParamIDs = append(m.ParamIDs, hex.ReadDoubleWord(pkt, &idx))
+	}
+	return nil
+}
+
+func (m *Msg8106) Encode() (pkt []byte, err error) {
+	pkt = hex.WriteByte(pkt, uint8(len(m.ParamIDs)))
+	for _, id := range m.ParamIDs {
+		pkt = hex.WriteDoubleWord(pkt, id)
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8106) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8106) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
@@ -0,0 +1,25 @@
+package model
+
+// 查询终端属性，终端以0x0107应答
+type Msg8107 struct {
+	Header *MsgHeader `json:"header"`
+}
+
+func (m *Msg8107) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	return nil
+}
+
+func (m *Msg8107) Encode() (pkt []byte, err error) {
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8107) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8107) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
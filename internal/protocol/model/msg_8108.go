@@ -0,0 +1,86 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 升级类型
+const (
+	UpgradeTypeTerminal   uint8 = 0  // 终端
+	UpgradeTypeCardReader uint8 = 12 // 道路运输证IC卡读卡器
+	UpgradeTypeGNSS       uint8 = 52 // 北斗卫星定位模块
+)
+
+// 制造商ID长度，2013版本5位，2019版本11位
+func ManufacturerIDLen(ver VersionType) int {
+	if ver == Version2019 {
+		return 11
+	}
+	return 5
+}
+
+// 下发终端升级包，升级包较大时按分包下发，终端以0x0001应答每个分包，升级完成后上报0x0108
+type Msg8108 struct {
+	Header         *MsgHeader `json:"header"`
+	UpgradeType    uint8      `json:"upgradeType"`    // 升级类型
+	ManufacturerID string     `json:"manufacturerId"` // 制造商ID
+	Version        string     `json:"version"`        // 版本号
+	Packet         []byte     `json:"-"`              // 升级数据包
+}
+
+func (m *Msg8108) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	pkt, idx := packet.Body, 0
+	manuLen := ManufacturerIDLen(m.Header.Attr.VersionDesc)
+	if len(pkt) < 1+manuLen+1 {
+		return ErrDecodeMsg
+	}
+	m.UpgradeType = hex.ReadByte(pkt, &idx)
+	m.ManufacturerID = strings.TrimRight(hex.ReadString(pkt, &idx, manuLen), "\x00")
+	verLen := int(hex.ReadByte(pkt, &idx))
+	if len(pkt) < idx+verLen+4 {
+		return ErrDecodeMsg
+	}
+	m.Version = hex.ReadString(pkt, &idx, verLen)
+	pktLen := int(hex.ReadDoubleWord(pkt, &idx))
+	if len(pkt) < idx+pktLen {
+		return errors.Wrapf(ErrDecodeMsg, "upgrade packet length %d, got %d", pktLen, len(pkt)-idx)
+	}
+	m.Packet = hex.ReadBytes(pkt, &idx, pktLen)
+	return nil
+}
+
+// 消息体，分包下发时按此切分
+func (m *Msg8108) EncodeBody() []byte {
+	var pkt []byte
+	pkt = hex.WriteByte(pkt, m.UpgradeType)
+	pkt = writeFixedString(pkt, m.ManufacturerID, ManufacturerIDLen(m.Header.Attr.VersionDesc))
+	pkt = hex.WriteByte(pkt, uint8(len(m.Version)))
+	pkt = hex.WriteString(pkt, m.Version)
+	pkt = hex.WriteDoubleWord(pkt, uint32(len(m.Packet)))
+	pkt = hex.WriteBytes(pkt, m.Packet)
+	return pkt
+}
+
+func (m *Msg8108) Encode() (pkt []byte, err error) {
+	pkt = m.EncodeBody()
+	if len(pkt) > MaxBodyLength {
+		return nil, errors.Wrapf(ErrEncodeMsg, "body length %d exceeds %d, should be fragmented", len(pkt), MaxBodyLength)
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8108) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8108) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
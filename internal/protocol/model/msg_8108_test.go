@@ -0,0 +1,79 @@
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+func TestMsg8108_EncodeDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		ver     VersionType
+		wantHex string
+	}{
+		{name: "2013", ver: Version2013, wantHex: "00" + "4142434445" + "03" + "312e30" + "00000002" + "abcd"},
+		{name: "2019", ver: Version2019, wantHex: "00" + "4142434445000000000000" + "03" + "312e30" + "00000002" + "abcd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := genMsgHeader(0x8108)
+			h.Attr.VersionDesc = tt.ver
+			m := &Msg8108{Header: h, ManufacturerID: "ABCDE", Version: "1.0", Packet: []byte{0xab, 0xcd}}
+			body := m.EncodeBody()
+			assert.Equal(t, hex.Str2Byte(tt.wantHex), body)
+
+			got := &Msg8108{}
+			require.NoError(t, got.Decode(&PacketData{Header: h, Body: body}))
+			assert.Equal(t, m.ManufacturerID, got.ManufacturerID)
+			assert.Equal(t, m.Version, got.Version)
+			assert.Equal(t, m.Packet, got.Packet)
+
+			assert.ErrorIs(t, got.Decode(&PacketData{Header: h, Body: body[:len(body)-1]}), ErrDecodeMsg)
+		})
+	}
+
+	m := &Msg8108{Header: genMsgHeader(0x8108), Version: "1.0", Packet: make([]byte, MaxBodyLength)}
+	_, err := m.Encode()
+	assert.ErrorIs(t, err, ErrEncodeMsg)
+}
+
+func TestSplitBody(t *testing.T) {
+	body := bytes.Repeat([]byte{0x01, 0x02, 0x03}, 700)
+	var serial uint16 = 10
+	next := func() uint16 {
+		serial++
+		return serial
+	}
+	frags := SplitBody(genMsgHeader(0x8108), body, 1000, next)
+	require.Len(t, frags, 3)
+
+	var merged []byte
+	for i, f := range frags {
+		pkt, err := f.Encode()
+		require.NoError(t, err)
+		h := &MsgHeader{}
+		require.NoError(t, h.Decode(pkt))
+		assert.True(t, h.IsFragmented())
+		assert.Equal(t, &MsgFragmentation{Total: 3, Index: uint16(i + 1)}, h.Frag)
+		assert.Equal(t, uint16(11+i), h.SerialNumber)
+		merged = append(merged, pkt[h.Idx:]...)
+	}
+	assert.Equal(t, body, merged)
+	assert.Len(t, frags[2].Body, 100)
+}
+
+func TestMsg0108_GenOutgoing(t *testing.T) {
+	frag := genMsgHeader(0x8108)
+	frag.Attr.PacketFragmented = 1
+	frag.Frag = &MsgFragmentation{Total: 2, Index: 2}
+	m := &Msg0108{}
+	require.NoError(t, m.GenOutgoing(&Msg8108{Header: frag, UpgradeType: UpgradeTypeGNSS}))
+	pkt, err := m.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, hex.Str2Byte("0108400201123456789012345678900001"+"3400"), pkt)
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 临时位置跟踪控制
+type Msg8202 struct {
+	Header   *MsgHeader `json:"header"`
+	Interval uint16     `json:"interval"` // 时间间隔，单位秒，为0时停止跟踪
+	Duration uint32     `json:"duration"` // 位置跟踪有效期，单位秒，终端在有效期内按时间间隔汇报位置
+}
+
+func (m *Msg8202) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	pkt, idx := packet.Body, 0
+	m.Interval = hex.ReadWord(pkt, &idx)
+	if m.Interval > 0 {
+		m.Duration = hex.ReadDoubleWord(pkt, &idx)
+	}
+	return nil
+}
+
+func (m *Msg8202) Encode() (pkt []byte, err error) {
+	pkt = hex.WriteWord(pkt, m.Interval)
+	if m.Interval > 0 { // 停止跟踪时无后继字段
+		pkt = hex.WriteDoubleWord(pkt, m.Duration)
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8202) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8202) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 文本信息标志位
+const (
+	TextFlagEmergency uint8 = 1 << 0 // 紧急
+	TextFlagDisplay   uint8 = 1 << 2 // 终端显示器显示
+	TextFlagTTS       uint8 = 1 << 3 // 终端TTS播读
+	TextFlagAdScreen  uint8 = 1 << 4 // 广告屏显示，2013版本
+)
+
+// 文本类型，2019版本
+const (
+	TextTypeNotice  uint8 = 1 // 通知
+	TextTypeService uint8 = 2 // 服务
+)
+
+// 文本信息下发
+type Msg8300 struct {
+	Header   *MsgHeader `json:"header"`
+	Flag     uint8      `json:"flag"`     // 标志
+	TextType uint8      `json:"textType"` // 文本类型，2019版本
+	Text     string     `json:"text"`     // 文本信息，GBK编码，2019版本最长1024字节
+}
+
+func (m *Msg8300) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	pkt, idx := packet.Body, 0
+	m.Flag = hex.ReadByte(pkt, &idx)
+	if m.Header.Attr.VersionDesc == Version2019 {
+		m.TextType = hex.ReadByte(pkt, &idx)
+	}
+	m.Text = hex.ReadGBK(pkt, &idx, len(pkt)-idx)
+	return nil
+}
+
+func (m *Msg8300) Encode() (pkt []byte, err error) {
+	pkt = hex.WriteByte(pkt, m.Flag)
+	if m.Header.Attr.VersionDesc == Version2019 {
+		pkt = hex.WriteByte(pkt, m.TextType)
+	}
+	pkt = hex.WriteGBK(pkt, m.Text)
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8300) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8300) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
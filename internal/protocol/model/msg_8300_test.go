@@ -0,0 +1,56 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+func TestMsg8300_EncodeDecode(t *testing.T) {
+	m := &Msg8300{
+		Header:   genMsgHeader(0x8300),
+		Flag:     TextFlagDisplay | TextFlagTTS,
+		TextType: TextTypeNotice,
+		Text:     "测试",
+	}
+	pkt, err := m.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, hex.Str2Byte("8300400601123456789012345678900001"+"0c01"+"b2e2cad4"), pkt)
+
+	header := &MsgHeader{}
+	require.NoError(t, header.Decode(pkt))
+	got := &Msg8300{}
+	require.NoError(t, got.Decode(&PacketData{Header: header, Body: pkt[len(pkt)-6:]}))
+	assert.Equal(t, m.Flag, got.Flag)
+	assert.Equal(t, m.TextType, got.TextType)
+	assert.Equal(t, m.Text, got.Text)
+}
+
+func TestMsg8202_Encode(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     *Msg8202
+		wantPkt []byte
+	}{
+		{
+			name:    "start tracking",
+			msg:     &Msg8202{Header: genMsgHeader(0x8202), Interval: 10, Duration: 3600},
+			wantPkt: hex.Str2Byte("8202400601123456789012345678900001" + "000a00000e10"),
+		},
+		{
+			name:    "stop tracking",
+			msg:     &Msg8202{Header: genMsgHeader(0x8202)},
+			wantPkt: hex.Str2Byte("8202400201123456789012345678900001" + "0000"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkt, err := tt.msg.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPkt, pkt)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package model
+
+import (
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 圆形区域
+type CircleArea struct {
+	ID     uint32    `json:"id"`     // 区域ID
+	Attr   uint16    `json:"attr"`   // 区域属性
+	Center AreaPoint `json:"center"` // 中心点
+	Radius uint32    `json:"radius"` // 半径，米
+	AreaLimit
+	Name string `json:"name,omitempty"` // 区域名称，2019版本
+}
+
+func (a *CircleArea) decode(pkt []byte, idx *int, ver VersionType) (err error) {
+	if err = hasLen(pkt, *idx, 18); err != nil {
+		return err
+	}
+	a.ID = hex.ReadDoubleWord(pkt, idx)
+	a.Attr = hex.ReadWord(pkt, idx)
+	a.Center = readAreaPoint(pkt, idx)
+	a.Radius = hex.ReadDoubleWord(pkt, idx)
+	if a.StartTime, a.EndTime, err = readTimeRange(pkt, idx, a.Attr&AreaAttrByTime > 0); err != nil {
+		return err
+	}
+	speedLimit := a.Attr&AreaAttrSpeedLimit > 0
+	if err = a.readSpeed(pkt, idx, speedLimit); err != nil {
+		return err
+	}
+	if err = a.readNightSpeed(pkt, idx, speedLimit, ver); err != nil {
+		return err
+	}
+	a.Name, err = readAreaName(pkt, idx, ver)
+	return err
+}
+
+func (a *CircleArea) encode(pkt []byte, ver VersionType) []byte {
+	pkt = hex.WriteDoubleWord(pkt, a.ID)
+	pkt = hex.WriteWord(pkt, a.Attr)
+	pkt = writeAreaPoint(pkt, a.Center)
+	pkt = hex.WriteDoubleWord(pkt, a.Radius)
+	pkt = writeTimeRange(pkt, a.Attr&AreaAttrByTime > 0, a.StartTime, a.EndTime)
+	speedLimit := a.Attr&AreaAttrSpeedLimit > 0
+	pkt = a.writeSpeed(pkt, speedLimit)
+	pkt = a.writeNightSpeed(pkt, speedLimit, ver)
+	return writeAreaName(pkt, a.Name, ver)
+}
+
+// 转为平台侧围栏的形状和限速
+func (a *CircleArea) Geofence() *Geofence {
+	center := a.Center.toPoint(a.Attr&AreaAttrSouth > 0, a.Attr&AreaAttrWest > 0)
+	return &Geofence{
+		ID: a.ID, Name: a.Name, Type: GeofenceCircle, Center: &center, Radius: float64(a.Radius),
+		MaxSpeed: float64(a.MaxSpeed),
+	}
+}
+
+// 设置圆形区域
+type Msg8600 struct {
+	Header *MsgHeader    `json:"header"`
+	Action uint8         `json:"action"` // 设置属性
+	Areas  []*CircleArea `json:"areas"`  // 区域项
+}
+
+func (m *Msg8600) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	pkt, idx := packet.Body, 0
+	if err := hasLen(pkt, idx, 2); err != nil {
+		return err
+	}
+	m.Action = hex.ReadByte(pkt, &idx)
+	cnt := int(hex.ReadByte(pkt, &idx))
+	m.Areas = make([]*CircleArea, 0, cnt)
+	for i := 0; i < cnt; i++ {
+		a := &CircleArea{}
+		if err := a.decode(pkt, &idx, m.Header.Attr.VersionDesc); err != nil {
+			return err
+		}
+		m.Areas = append(m.Areas, a)
+	}
+	return nil
+}
+
+func (m *Msg8600) Encode() (pkt []byte, err error) {
+	pkt = hex.WriteByte(pkt, m.Action)
+	pkt = hex.WriteByte(pkt, uint8(len(m.Areas)))
+	for _, a := range m.Areas {
+		pkt = a.encode(pkt, m.Header.Attr.VersionDesc)
+	}
+	if err = checkBodyLength(pkt); err != nil {
+		return nil, err
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8600) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8600) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
+
+// 删除圆形区域
+type Msg8601 struct {
+	Header *MsgHeader `json:"header"`
+	IDs    []uint32   `json:"ids"` // 区域ID，为空删除全部圆形区域
+}
+
+func (m *Msg8601) Decode(packet *PacketData) (err error) {
+	m.Header = packet.Header
+	m.IDs, err = decodeAreaIDs(packet.Body)
+	return err
+}
+
+func (m *Msg8601) Encode() (pkt []byte, err error) {
+	if pkt, err = encodeAreaIDs(m.IDs); err != nil {
+		return nil, err
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8601) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8601) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
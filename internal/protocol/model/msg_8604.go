@@ -0,0 +1,133 @@
+package model
+
+import (
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 多边形区域
+type PolygonArea struct {
+	ID   uint32 `json:"id"`   // 区域ID
+	Attr uint16 `json:"attr"` // 区域属性
+	AreaLimit
+	Points []AreaPoint `json:"points"`         // 顶点项
+	Name   string      `json:"name,omitempty"` // 区域名称，2019版本
+}
+
+func (a *PolygonArea) decode(pkt []byte, idx *int, ver VersionType) (err error) {
+	if err = hasLen(pkt, *idx, 6); err != nil {
+		return err
+	}
+	a.ID = hex.ReadDoubleWord(pkt, idx)
+	a.Attr = hex.ReadWord(pkt, idx)
+	if a.StartTime, a.EndTime, err = readTimeRange(pkt, idx, a.Attr&AreaAttrByTime > 0); err != nil {
+		return err
+	}
+	speedLimit := a.Attr&AreaAttrSpeedLimit > 0
+	if err = a.readSpeed(pkt, idx, speedLimit); err != nil {
+		return err
+	}
+	if err = hasLen(pkt, *idx, 2); err != nil {
+		return err
+	}
+	cnt := int(hex.ReadWord(pkt, idx))
+	if err = hasLen(pkt, *idx, cnt*8); err != nil {
+		return err
+	}
+	a.Points = make([]AreaPoint, 0, cnt)
+	for i := 0; i < cnt; i++ {
+		a.Points = append(a.Points, readAreaPoint(pkt, idx))
+	}
+	if err = a.readNightSpeed(pkt, idx, speedLimit, ver); err != nil {
+		return err
+	}
+	a.Name, err = readAreaName(pkt, idx, ver)
+	return err
+}
+
+func (a *PolygonArea) encode(pkt []byte, ver VersionType) []byte {
+	pkt = hex.WriteDoubleWord(pkt, a.ID)
+	pkt = hex.WriteWord(pkt, a.Attr)
+	pkt = writeTimeRange(pkt, a.Attr&AreaAttrByTime > 0, a.StartTime, a.EndTime)
+	speedLimit := a.Attr&AreaAttrSpeedLimit > 0
+	pkt = a.writeSpeed(pkt, speedLimit)
+	pkt = hex.WriteWord(pkt, uint16(len(a.Points)))
+	for _, p := range a.Points {
+		pkt = writeAreaPoint(pkt, p)
+	}
+	pkt = a.writeNightSpeed(pkt, speedLimit, ver)
+	return writeAreaName(pkt, a.Name, ver)
+}
+
+// 转为平台侧围栏的形状和限速
+func (a *PolygonArea) Geofence() *Geofence {
+	south, west := a.Attr&AreaAttrSouth > 0, a.Attr&AreaAttrWest > 0
+	f := &Geofence{ID: a.ID, Name: a.Name, Type: GeofencePolygon, MaxSpeed: float64(a.MaxSpeed)}
+	f.Points = make([]geo.Point, 0, len(a.Points))
+	for _, p := range a.Points {
+		f.Points = append(f.Points, p.toPoint(south, west))
+	}
+	return f
+}
+
+// 设置多边形区域，每条消息一个区域
+type Msg8604 struct {
+	Header *MsgHeader   `json:"header"`
+	Area   *PolygonArea `json:"area"`
+}
+
+func (m *Msg8604) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	idx := 0
+	m.Area = &PolygonArea{}
+	return m.Area.decode(packet.Body, &idx, m.Header.Attr.VersionDesc)
+}
+
+func (m *Msg8604) Encode() (pkt []byte, err error) {
+	pkt = m.Area.encode(pkt, m.Header.Attr.VersionDesc)
+	if err = checkBodyLength(pkt); err != nil {
+		return nil, err
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8604) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8604) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
+
+// 删除多边形区域
+type Msg8605 struct {
+	Header *MsgHeader `json:"header"`
+	IDs    []uint32   `json:"ids"` // 区域ID，为空删除全部多边形区域
+}
+
+func (m *Msg8605) Decode(packet *PacketData) (err error) {
+	m.Header = packet.Header
+	m.IDs, err = decodeAreaIDs(packet.Body)
+	return err
+}
+
+func (m *Msg8605) Encode() (pkt []byte, err error) {
+	if pkt, err = encodeAreaIDs(m.IDs); err != nil {
+		return nil, err
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8605) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8605) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
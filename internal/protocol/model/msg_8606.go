@@ -0,0 +1,194 @@
+package model
+
+import (
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 路线拐点，拐点与下一个拐点之间为该拐点的路段
+type RoutePoint struct {
+	ID                uint32    `json:"id"`                          // 拐点ID
+	SegmentID         uint32    `json:"segmentId"`                   // 路段ID
+	Point             AreaPoint `json:"point"`                       // 拐点坐标
+	Width             uint8     `json:"width"`                       // 路段宽度，米
+	Attr              uint8     `json:"attr"`                        // 路段属性
+	MaxDriveTime      uint16    `json:"maxDriveTime,omitempty"`      // 路段行驶过长阈值，秒，路段属性bit0为1时有效
+	MinDriveTime      uint16    `json:"minDriveTime,omitempty"`      // 路段行驶不足阈值，秒
+	MaxSpeed          uint16    `json:"maxSpeed,omitempty"`          // 路段最高速度，km/h，路段属性bit1为1时有效
+	OverspeedDuration uint8     `json:"overspeedDuration,omitempty"` // 路段超速持续时间，秒
+	NightMaxSpeed     uint16    `json:"nightMaxSpeed,omitempty"`     // 路段夜间最高速度，km/h，2019版本
+}
+
+func (p *RoutePoint) decode(pkt []byte, idx *int, ver VersionType) error {
+	if err := hasLen(pkt, *idx, 18); err != nil {
+		return err
+	}
+	p.ID = hex.ReadDoubleWord(pkt, idx)
+	p.SegmentID = hex.ReadDoubleWord(pkt, idx)
+	p.Point = readAreaPoint(pkt, idx)
+	p.Width = hex.ReadByte(pkt, idx)
+	p.Attr = hex.ReadByte(pkt, idx)
+	if p.Attr&SegmentAttrDriveTime > 0 {
+		if err := hasLen(pkt, *idx, 4); err != nil {
+			return err
+		}
+		p.MaxDriveTime = hex.ReadWord(pkt, idx)
+		p.MinDriveTime = hex.ReadWord(pkt, idx)
+	}
+	if p.Attr&SegmentAttrSpeedLimit > 0 {
+		n := 3
+		if ver == Version2019 {
+			n += 2
+		}
+		if err := hasLen(pkt, *idx, n); err != nil {
+			return err
+		}
+		p.MaxSpeed = hex.ReadWord(pkt, idx)
+		p.OverspeedDuration = hex.ReadByte(pkt, idx)
+		if ver == Version2019 {
+			p.NightMaxSpeed = hex.ReadWord(pkt, idx)
+		}
+	}
+	return nil
+}
+
+func (p *RoutePoint) encode(pkt []byte, ver VersionType) []byte {
+	pkt = hex.WriteDoubleWord(pkt, p.ID)
+	pkt = hex.WriteDoubleWord(pkt, p.SegmentID)
+	pkt = writeAreaPoint(pkt, p.Point)
+	pkt = hex.WriteByte(pkt, p.Width)
+	pkt = hex.WriteByte(pkt, p.Attr)
+	if p.Attr&SegmentAttrDriveTime > 0 {
+		pkt = hex.WriteWord(pkt, p.MaxDriveTime)
+		pkt = hex.WriteWord(pkt, p.MinDriveTime)
+	}
+	if p.Attr&SegmentAttrSpeedLimit > 0 {
+		pkt = hex.WriteWord(pkt, p.MaxSpeed)
+		pkt = hex.WriteByte(pkt, p.OverspeedDuration)
+		if ver == Version2019 {
+			pkt = hex.WriteWord(pkt, p.NightMaxSpeed)
+		}
+	}
+	return pkt
+}
+
+// 路线
+type Route struct {
+	ID        uint32        `json:"id"`                  // 路线ID
+	Attr      uint16        `json:"attr"`                // 路线属性
+	StartTime string        `json:"startTime,omitempty"` // 起始时间YYMMDDhhmmss，路线属性bit0为1时有效
+	EndTime   string        `json:"endTime,omitempty"`   // 结束时间YYMMDDhhmmss
+	Points    []*RoutePoint `json:"points"`              // 拐点项
+	Name      string        `json:"name,omitempty"`      // 路线名称，2019版本
+}
+
+func (r *Route) decode(pkt []byte, idx *int, ver VersionType) (err error) {
+	if err = hasLen(pkt, *idx, 6); err != nil {
+		return err
+	}
+	r.ID = hex.ReadDoubleWord(pkt, idx)
+	r.Attr = hex.ReadWord(pkt, idx)
+	if r.StartTime, r.EndTime, err = readTimeRange(pkt, idx, r.Attr&AreaAttrByTime > 0); err != nil {
+		return err
+	}
+	if err = hasLen(pkt, *idx, 2); err != nil {
+		return err
+	}
+	cnt := int(hex.ReadWord(pkt, idx))
+	r.Points = make([]*RoutePoint, 0, cnt)
+	for i := 0; i < cnt; i++ {
+		p := &RoutePoint{}
+		if err = p.decode(pkt, idx, ver); err != nil {
+			return err
+		}
+		r.Points = append(r.Points, p)
+	}
+	r.Name, err = readAreaName(pkt, idx, ver)
+	return err
+}
+
+func (r *Route) encode(pkt []byte, ver VersionType) []byte {
+	pkt = hex.WriteDoubleWord(pkt, r.ID)
+	pkt = hex.WriteWord(pkt, r.Attr)
+	pkt = writeTimeRange(pkt, r.Attr&AreaAttrByTime > 0, r.StartTime, r.EndTime)
+	pkt = hex.WriteWord(pkt, uint16(len(r.Points)))
+	for _, p := range r.Points {
+		pkt = p.encode(pkt, ver)
+	}
+	return writeAreaName(pkt, r.Name, ver)
+}
+
+// 转为平台侧围栏的形状和限速，路段取各拐点的路段设置
+func (r *Route) Geofence() *Geofence {
+	f := &Geofence{ID: r.ID, Name: r.Name, Type: GeofenceRoute}
+	f.Points = make([]geo.Point, 0, len(r.Points))
+	for i, p := range r.Points {
+		f.Points = append(f.Points, p.Point.toPoint(p.Attr&SegmentAttrSouth > 0, p.Attr&SegmentAttrWest > 0))
+		if i+1 < len(r.Points) {
+			f.Segments = append(f.Segments, &RouteSegment{Width: float64(p.Width), MaxSpeed: float64(p.MaxSpeed)})
+		}
+	}
+	return f
+}
+
+// 设置路线，每条消息一条路线
+type Msg8606 struct {
+	Header *MsgHeader `json:"header"`
+	Route  *Route     `json:"route"`
+}
+
+func (m *Msg8606) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	idx := 0
+	m.Route = &Route{}
+	return m.Route.decode(packet.Body, &idx, m.Header.Attr.VersionDesc)
+}
+
+func (m *Msg8606) Encode() (pkt []byte, err error) {
+	pkt = m.Route.encode(pkt, m.Header.Attr.VersionDesc)
+	if err = checkBodyLength(pkt); err != nil {
+		return nil, err
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8606) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8606) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
+
+// 删除路线
+type Msg8607 struct {
+	Header *MsgHeader `json:"header"`
+	IDs    []uint32   `json:"ids"` // 路线ID，为空删除全部路线
+}
+
+func (m *Msg8607) Decode(packet *PacketData) (err error) {
+	m.Header = packet.Header
+	m.IDs, err = decodeAreaIDs(packet.Body)
+	return err
+}
+
+func (m *Msg8607) Encode() (pkt []byte, err error) {
+	if pkt, err = encodeAreaIDs(m.IDs); err != nil {
+		return nil, err
+	}
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8607) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8607) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
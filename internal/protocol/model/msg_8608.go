@@ -0,0 +1,45 @@
+package model
+
+import (
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+// 查询区域或线路数据，2019版本，终端以0x0608应答
+type Msg8608 struct {
+	Header   *MsgHeader `json:"header"`
+	AreaType uint8      `json:"areaType"` // 查询类型
+	IDs      []uint32   `json:"ids"`      // 区域或线路ID，为空查询该类型的全部区域
+}
+
+func (m *Msg8608) Decode(packet *PacketData) error {
+	m.Header = packet.Header
+	pkt, idx := packet.Body, 0
+	if err := hasLen(pkt, idx, 1); err != nil {
+		return err
+	}
+	m.AreaType = hex.ReadByte(pkt, &idx)
+	ids, err := decodeAreaIDs(pkt[idx:])
+	m.IDs = ids
+	return err
+}
+
+func (m *Msg8608) Encode() (pkt []byte, err error) {
+	ids, err := encodeAreaIDs(m.IDs)
+	if err != nil {
+		return nil, err
+	}
+	pkt = hex.WriteByte(pkt, m.AreaType)
+	pkt = hex.WriteBytes(pkt, ids)
+
+	pkt, err = writeHeader(m, pkt)
+	return pkt, err
+}
+
+func (m *Msg8608) GetHeader() *MsgHeader {
+	return m.Header
+}
+
+func (m *Msg8608) GenOutgoing(_ JT808Msg) error {
+	// will not use
+	return nil
+}
@@ -0,0 +1,404 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	GBK "github.com/fakeyanss/jt808-server-go/internal/codec/gbk"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+var (
+	ErrInvalidParamValue     = errors.New("Invalid param value")
+	ErrParamNameNotSupported = errors.New("Param name is not supported")
+)
+
+// 参数值类型，决定编解码方式和json中的取值类型
+type ParamType string
+
+const (
+	ParamTypeUint8  ParamType = "uint8"  // BYTE，json数字
+	ParamTypeUint16 ParamType = "uint16" // WORD，json数字
+	ParamTypeUint32 ParamType = "uint32" // DWORD，json数字
+	ParamTypeString ParamType = "string" // STRING，GBK编码，json字符串
+	ParamTypeBCD    ParamType = "bcd"    // BCD[n]，json数字字符串
+	ParamTypeRaw    ParamType = "raw"    // BYTE[n]，json hex字符串。未知参数ID按此类型透传
+)
+
+// 参数所属协议
+const (
+	ParamProtocolJT808  = "JT808"
+	ParamProtocolJT1078 = "JT1078"
+)
+
+const maxParamLen = math.MaxUint8
+
+// 数值型参数的字节数
+var paramTypeSize = map[ParamType]int{ParamTypeUint8: 1, ParamTypeUint16: 2, ParamTypeUint32: 4}
+
+type ParamRange struct {
+	Min uint32 `json:"min"`
+	Max uint32 `json:"max"`
+}
+
+// 终端参数定义
+type ParamSchema struct {
+	ID       uint32      `json:"id"`
+	Name     string      `json:"name"` // 参数名，设置参数时可代替ID
+	Desc     string      `json:"desc"`
+	Type     ParamType   `json:"type"`
+	Unit     string      `json:"unit,omitempty"`
+	Range    *ParamRange `json:"range,omitempty"` // 数值型参数的取值范围，为空表示不限制
+	Protocol string      `json:"protocol"`
+	Versions []string    `json:"versions"` // 支持的JT808协议版本
+	versions []VersionType
+}
+
+// 该版本的终端是否支持此参数
+func (s *ParamSchema) SupportsVersion(v VersionType) bool {
+	for _, sv := range s.versions {
+		if sv == v {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	allVersions  = []VersionType{Version2011, Version2013, Version2019}
+	until2013    = []VersionType{Version2011, Version2013}
+	since2019    = []VersionType{Version2019}
+	noRange      *ParamRange
+	positiveOnly = &ParamRange{Min: 1, Max: math.MaxUint32}
+)
+
+func rng(min, max uint32) *ParamRange {
+	return &ParamRange{Min: min, Max: max}
+}
+
+func jt808(id uint32, name, desc string, typ ParamType, unit string, r *ParamRange, versions ...VersionType) *ParamSchema {
+	if len(versions) == 0 {
+		versions = allVersions
+	}
+	return &ParamSchema{ID: id, Name: name, Desc: desc, Type: typ, Unit: unit, Range: r, Protocol: ParamProtocolJT808, versions: versions}
+}
+
+func jt1078(id uint32, name, desc string) *ParamSchema {
+	return &ParamSchema{ID: id, Name: name, Desc: desc, Type: ParamTypeRaw, Protocol: ParamProtocolJT1078, versions: allVersions}
+}
+
+var paramSchemas = []*ParamSchema{
+	jt808(0x0001, "heartbeatInterval", "终端心跳发送间隔", ParamTypeUint32, "s", noRange),
+	jt808(0x0002, "tcpAnswerTimeout", "TCP消息应答超时时间", ParamTypeUint32, "s", noRange),
+	jt808(0x0003, "tcpRetransmitTimes", "TCP消息重传次数", ParamTypeUint32, "", noRange),
+	jt808(0x0004, "udpAnswerTimeout", "UDP消息应答超时时间", ParamTypeUint32, "s", noRange),
+	jt808(0x0005, "udpRetransmitTimes", "UDP消息重传次数", ParamTypeUint32, "", noRange),
+	jt808(0x0006, "smsAnswerTimeout", "SMS消息应答超时时间", ParamTypeUint32, "s", noRange),
+	jt808(0x0007, "smsRetransmitTimes", "SMS消息重传次数", ParamTypeUint32, "", noRange),
+	jt808(0x0010, "mainServerAPN", "主服务器APN，无线通信拨号访问点。若网络制式为CDMA，则该处为PPP拨号号码", ParamTypeString, "", noRange),
+	jt808(0x0011, "mainServerUsername", "主服务器无线通信拨号用户名", ParamTypeString, "", noRange),
+	jt808(0x0012, "mainServerPassword", "主服务器无线通信拨号密码", ParamTypeString, "", noRange),
+	jt808(0x0013, "mainServerAddress", "主服务器地址，IP或域名。2019版以冒号分割主机和端口，多个服务器使用分号分隔", ParamTypeString, "", noRange),
+	jt808(0x0014, "backupServerAPN", "备份服务器APN，无线通信拨号访问点", ParamTypeString, "", noRange),
+	jt808(0x0015, "backupServerUsername", "备份服务器无线通信拨号用户名", ParamTypeString, "", noRange),
+	jt808(0x0016, "backupServerPassword", "备份服务器无线通信拨号密码", ParamTypeString, "", noRange),
+	jt808(0x0017, "backupServerAddress", "备份服务器地址，IP或域名。2019版以冒号分割主机和端口，多个服务器使用分号分隔", ParamTypeString, "", noRange),
+	jt808(0x0018, "serverTCPPort", "服务器TCP端口", ParamTypeUint32, "", rng(0, math.MaxUint16), until2013...),
+	jt808(0x0019, "serverUDPPort", "服务器UDP端口", ParamTypeUint32, "", rng(0, math.MaxUint16), until2013...),
+	jt808(0x001A, "icCardServerAddress", "道路运输证IC卡认证主服务器IP地址或域名", ParamTypeString, "", noRange),
+	jt808(0x001B, "icCardServerTCPPort", "道路运输证IC卡认证主服务器TCP端口", ParamTypeUint32, "", rng(0, math.MaxUint16)),
+	jt808(0x001C, "icCardServerUDPPort", "道路运输证IC卡认证主服务器UDP端口", ParamTypeUint32, "", rng(0, math.MaxUint16)),
+	jt808(0x001D, "icCardBackupServerAddress", "道路运输证IC卡认证备份服务器IP地址或域名，端口同主服务器", ParamTypeString, "", noRange),
+	jt808(0x0020, "locationReportStrategy", "位置汇报策略：0.定时汇报 1.定距汇报 2.定时和定距汇报", ParamTypeUint32, "", rng(0, 2)),
+	jt808(0x0021, "locationReportScheme", "位置汇报方案：0.根据ACC状态 1.根据登录状态和ACC状态，先判断登录状态，若登录再根据ACC状态", ParamTypeUint32, "", rng(0, 1)),
+	jt808(0x0022, "unloggedReportInterval", "驾驶员未登录汇报时间间隔", ParamTypeUint32, "s", positiveOnly),
+	jt808(0x0023, "slaveServerAPN", "从服务器APN。该值为空时，终端应使用主服务器相同配置", ParamTypeString, "", noRange, since2019...),
+	jt808(0x0024, "slaveServerUsername", "从服务器无线通信拨号用户名。该值为空时，终端应使用主服务器相同配置", ParamTypeString, "", noRange, since2019...),
+	jt808(0x0025, "slaveServerPassword", "从服务器无线通信拨号密码。该值为空时，终端应使用主服务器相同配置", ParamTypeString, "", noRange, since2019...),
+	jt808(0x0026, "slaveServerBackupAddress", "从服务器备份地址，IP或域名，端口同主服务器", ParamTypeString, "", noRange, since2019...),
+	jt808(0x0027, "sleepReportInterval", "休眠时汇报时间间隔", ParamTypeUint32, "s", positiveOnly),
+	jt808(0x0028, "emergencyReportInterval", "紧急报警时汇报时间间隔", ParamTypeUint32, "s", positiveOnly),
+	jt808(0x0029, "defaultReportInterval", "缺省时间汇报间隔", ParamTypeUint32, "s", positiveOnly),
+	jt808(0x002C, "defaultReportDistance", "缺省距离汇报间隔", ParamTypeUint32, "m", positiveOnly),
+	jt808(0x002D, "unloggedReportDistance", "驾驶员未登录汇报距离间隔", ParamTypeUint32, "m", positiveOnly),
+	jt808(0x002E, "sleepReportDistance", "休眠时汇报距离间隔", ParamTypeUint32, "m", positiveOnly),
+	jt808(0x002F, "emergencyReportDistance", "紧急报警时汇报距离间隔", ParamTypeUint32, "m", positiveOnly),
+	jt808(0x0030, "inflectionAngle", "拐点补传角度", ParamTypeUint32, "°", rng(0, 179)),
+	jt808(0x0031, "geofenceRadius", "电子围栏半径(非法位移阈值)", ParamTypeUint16, "m", noRange),
+	// byte1-byte4依次为违规行驶开始时间的小时、分钟，结束时间的小时、分钟
+	jt808(0x0032, "illegalDrivingPeriod", "违规行驶时段范围，精确到分，格式HHmmHHmm", ParamTypeBCD, "", noRange, since2019...),
+	jt808(0x0040, "platformPhone", "监控平台电话号码", ParamTypeString, "", noRange),
+	jt808(0x0041, "resetPhone", "复位电话号码，可采用此电话号码拨打终端电话让终端复位", ParamTypeString, "", noRange),
+	jt808(0x0042, "factoryResetPhone", "恢复出厂设置电话号码，可采用此电话号码拨打终端电话让终端恢复出厂设置", ParamTypeString, "", noRange),
+	jt808(0x0043, "platformSMSPhone", "监控平台SMS电话号码", ParamTypeString, "", noRange),
+	jt808(0x0044, "alarmSMSPhone", "接收终端SMS文本报警号码", ParamTypeString, "", noRange),
+	jt808(0x0045, "answerStrategy", "终端电话接听策略：0.自动接听 1.ACC ON时自动接听，OFF时手动接听", ParamTypeUint32, "", rng(0, 1)),
+	jt808(0x0046, "maxCallDuration", "每次最长通话时间，0为不允许通话，0xFFFFFFFF为不限制", ParamTypeUint32, "s", noRange),
+	jt808(0x0047, "maxMonthlyCallDuration", "当月最长通话时间，0为不允许通话，0xFFFFFFFF为不限制", ParamTypeUint32, "s", noRange),
+	jt808(0x0048, "monitorPhone", "监听电话号码", ParamTypeString, "", noRange),
+	jt808(0x0049, "privilegedSMSPhone", "监管平台特权短信号码", ParamTypeString, "", noRange),
+	jt808(0x0050, "alarmMask", "报警屏蔽字，与位置信息汇报消息中的报警标志相对应，相应位为1则相应报警被屏蔽", ParamTypeUint32, "", noRange),
+	jt808(0x0051, "alarmSMSSwitch", "报警发送文本SMS开关，相应位为1则相应报警时发送文本SMS", ParamTypeUint32, "", noRange),
+	jt808(0x0052, "alarmPhotoSwitch", "报警拍摄开关，相应位为1则相应报警时摄像头拍摄", ParamTypeUint32, "", noRange),
+	jt808(0x0053, "alarmPhotoStoreFlag", "报警拍摄存储标志，相应位为1则对相应报警时拍的照片进行存储，否则实时上传", ParamTypeUint32, "", noRange),
+	jt808(0x0054, "keyAlarmFlag", "关键标志，相应位为1则对相应报警为关键报警", ParamTypeUint32, "", noRange),
+	jt808(0x0055, "maxSpeed", "最高速度", ParamTypeUint32, "km/h", noRange),
+	jt808(0x0056, "overspeedDuration", "超速持续时间", ParamTypeUint32, "s", noRange),
+	jt808(0x0057, "continuousDrivingThreshold", "连续驾驶时间门限", ParamTypeUint32, "s", noRange),
+	jt808(0x0058, "dailyDrivingThreshold", "当天累计驾驶时间门限", ParamTypeUint32, "s", noRange),
+	jt808(0x0059, "minRestDuration", "最小休息时间", ParamTypeUint32, "s", noRange),
+	jt808(0x005A, "maxParkingDuration", "最长停车时间", ParamTypeUint32, "s", noRange),
+	jt808(0x005B, "overspeedWarningDiff", "超速预警差值", ParamTypeUint16, "0.1km/h", noRange),
+	jt808(0x005C, "fatigueWarningDiff", "疲劳驾驶预警差值", ParamTypeUint16, "s", noRange),
+	// b7-b0为碰撞时间，单位4ms；b15-b8为碰撞加速度，单位0.1g，设置范围0-79，默认为10
+	jt808(0x005D, "collisionAlarmParam", "碰撞报警参数", ParamTypeUint16, "", noRange),
+	jt808(0x005E, "rolloverAlarmParam", "侧翻报警参数，侧翻角度，默认为30", ParamTypeUint16, "°", noRange),
+	jt808(0x0064, "timedPhotoParam", "定时拍照控制", ParamTypeUint32, "", noRange),
+	jt808(0x0065, "distancePhotoParam", "定距拍照控制", ParamTypeUint32, "", noRange),
+	jt808(0x0070, "videoQuality", "图像/视频质量，1最好", ParamTypeUint32, "", rng(1, 10)),
+	jt808(0x0071, "brightness", "亮度", ParamTypeUint32, "", rng(0, 255)),
+	jt808(0x0072, "contrast", "对比度", ParamTypeUint32, "", rng(0, 127)),
+	jt808(0x0073, "saturation", "饱和度", ParamTypeUint32, "", rng(0, 127)),
+	jt808(0x0074, "chroma", "色度", ParamTypeUint32, "", rng(0, 255)),
+	jt808(0x0080, "odometer", "车辆里程表读数", ParamTypeUint32, "0.1km", noRange),
+	jt808(0x0081, "provinceID", "车辆所在的省域ID", ParamTypeUint16, "", noRange),
+	jt808(0x0082, "cityID", "车辆所在的市域ID", ParamTypeUint16, "", noRange),
+	jt808(0x0083, "plate", "公安交通管理部门颁发的机动车号牌", ParamTypeString, "", noRange),
+	jt808(0x0084, "plateColor", "车牌颜色，按照JT/T 415-2006的5.4.12", ParamTypeUint8, "", noRange),
+	// bit0-bit3依次为GPS、北斗、GLONASS、Galileo定位，0禁用，1启用
+	jt808(0x0090, "gnssMode", "GNSS定位模式", ParamTypeUint8, "", rng(0, 0x0F)),
+	// 0x00:4800 0x01:9600 0x02:19200 0x03:38400 0x04:57600 0x05:115200
+	jt808(0x0091, "gnssBaudRate", "GNSS波特率", ParamTypeUint8, "", rng(0, 5)),
+	// 0x00:500ms 0x01:1000ms(默认值) 0x02:2000ms 0x03:3000ms 0x04:4000ms
+	jt808(0x0092, "gnssOutputFrequency", "GNSS模块详细定位数据输出频率", ParamTypeUint8, "", rng(0, 4)),
+	jt808(0x0093, "gnssSampleFrequency", "GNSS模块详细定位数据采集频率，默认为1", ParamTypeUint32, "s", noRange),
+	// 0x00本地存储不上传(默认值) 0x01按时间间隔 0x02按距离间隔 0x0B按累计时间 0x0C按累计距离 0x0D按累计条数
+	jt808(0x0094, "gnssUploadMode", "GNSS模块详细定位数据上传方式", ParamTypeUint8, "", rng(0, 0x0D)),
+	// 上传方式为0x01、0x0B时单位为秒，0x02、0x0C时单位为米，0x0D时单位为条
+	jt808(0x0095, "gnssUploadSetting", "GNSS模块详细定位数据上传设置，关联0x0094", ParamTypeUint32, "", noRange),
+	jt808(0x0100, "can1SampleInterval", "CAN总线通道1采集时间间隔，0表示不采集", ParamTypeUint32, "ms", noRange),
+	jt808(0x0101, "can1UploadInterval", "CAN总线通道1上传时间间隔，0表示不上传", ParamTypeUint16, "s", noRange),
+	jt808(0x0102, "can2SampleInterval", "CAN总线通道2采集时间间隔，0表示不采集", ParamTypeUint32, "ms", noRange),
+	jt808(0x0103, "can2UploadInterval", "CAN总线通道2上传时间间隔，0表示不上传", ParamTypeUint16, "s", noRange),
+	// bit63-bit32为此ID采集时间间隔(ms)，0表示不采集；bit31为CAN通道号，0:CAN1，1:CAN2；
+	// bit30为帧类型，0:标准帧，1:扩展帧；bit29为数据采集方式，0:原始数据，1:采集区间的计算值；bit28-bit0为CAN总线ID
+	jt808(0x0110, "canIDSampleSetting", "CAN总线ID单独采集设置", ParamTypeRaw, "", noRange),
+
+	jt1078(0x0075, "avParams", "音视频参数设置"),
+	jt1078(0x0076, "avChannels", "音视频通道列表设置"),
+	jt1078(0x0077, "channelVideoParams", "单独通道视频参数设置"),
+	jt1078(0x0079, "alarmRecordParams", "特殊报警录像参数设置"),
+	jt1078(0x007A, "videoAlarmMask", "视频相关报警屏蔽字"),
+	jt1078(0x007B, "imageAnalysisAlarmParams", "图像分析报警参数设置"),
+	jt1078(0x007C, "sleepWakeupMode", "终端休眠唤醒模式设置"),
+}
+
+var (
+	paramSchemaByID   = make(map[uint32]*ParamSchema)
+	paramSchemaByName = make(map[string]*ParamSchema)
+)
+
+func init() {
+	sort.Slice(paramSchemas, func(i, j int) bool { return paramSchemas[i].ID < paramSchemas[j].ID })
+	for _, s := range paramSchemas {
+		for _, v := range s.versions {
+			s.Versions = append(s.Versions, v.String())
+		}
+		paramSchemaByID[s.ID] = s
+		paramSchemaByName[s.Name] = s
+	}
+}
+
+// 全部已知参数定义，按ID升序
+func ParamSchemas() []*ParamSchema {
+	return paramSchemas
+}
+
+func LookupParamByID(id uint32) (*ParamSchema, bool) {
+	s, ok := paramSchemaByID[id]
+	return s, ok
+}
+
+func LookupParamByName(name string) (*ParamSchema, bool) {
+	s, ok := paramSchemaByName[name]
+	return s, ok
+}
+
+// 是否为已知参数ID，未知参数按raw类型透传
+func IsParamSupported(id uint32) bool {
+	_, ok := paramSchemaByID[id]
+	return ok
+}
+
+// 该版本的终端是否支持此参数ID，未知参数ID按原样透传，视为支持
+func ParamSupportsVersion(id uint32, v VersionType) bool {
+	s, ok := paramSchemaByID[id]
+	return !ok || s.SupportsVersion(v)
+}
+
+// 参数定义，未知参数ID返回raw类型的定义
+func paramSchemaOf(id uint32) *ParamSchema {
+	if s, ok := paramSchemaByID[id]; ok {
+		return s
+	}
+	return &ParamSchema{ID: id, Type: ParamTypeRaw}
+}
+
+func (s *ParamSchema) label() string {
+	if s.Name != "" {
+		return fmt.Sprintf("0x%04x(%s)", s.ID, s.Name)
+	}
+	return fmt.Sprintf("0x%04x", s.ID)
+}
+
+func (s *ParamSchema) invalid(v any, reason string) error {
+	return errors.Wrapf(ErrInvalidParamValue, "param %s, value %v: %s", s.label(), v, reason)
+}
+
+// 按参数类型校验并转换参数值:
+// 数值型转为对应宽度的无符号整数，接受json数字和十进制数字字符串；
+// string/bcd/raw类型为字符串，bcd仅含数字，raw为hex字符串。
+//
+// !!!特别注意，any类型被encoding/json Unmarshal后，数字会转为float64，所以不能直接断言为整数类型
+func (s *ParamSchema) Normalize(v any) (any, error) {
+	switch s.Type {
+	case ParamTypeUint8, ParamTypeUint16, ParamTypeUint32:
+		return s.normalizeNumber(v)
+	case ParamTypeString:
+		str, ok := v.(string)
+		if !ok {
+			return nil, s.invalid(v, "string expected")
+		}
+		gbk, err := GBK.UTF82GBK([]byte(str))
+		if err != nil {
+			return nil, s.invalid(v, "not encodable in GBK")
+		}
+		if len(gbk) > maxParamLen {
+			return nil, s.invalid(v, "too long")
+		}
+		return str, nil
+	case ParamTypeBCD:
+		str, ok := v.(string)
+		if !ok || strings.Trim(str, "0123456789") != "" {
+			return nil, s.invalid(v, "digit string expected")
+		}
+		if (len(str)+1)/2 > maxParamLen {
+			return nil, s.invalid(v, "too long")
+		}
+		return str, nil
+	default:
+		str, ok := v.(string)
+		if !ok || len(str)%2 != 0 || strings.Trim(strings.ToLower(str), "0123456789abcdef") != "" {
+			return nil, s.invalid(v, "hex string expected")
+		}
+		if len(str)/2 > maxParamLen {
+			return nil, s.invalid(v, "too long")
+		}
+		return strings.ToLower(str), nil
+	}
+}
+
+func (s *ParamSchema) normalizeNumber(v any) (any, error) {
+	var n uint64
+	switch x := v.(type) {
+	case float64:
+		if x < 0 || x != math.Trunc(x) || x > math.MaxUint32 {
+			return nil, s.invalid(v, "unsigned integer expected")
+		}
+		n = uint64(x)
+	case json.Number:
+		u, err := strconv.ParseUint(string(x), 10, 32)
+		if err != nil {
+			return nil, s.invalid(v, "unsigned integer expected")
+		}
+		n = u
+	case string:
+		u, err := strconv.ParseUint(x, 10, 32)
+		if err != nil {
+			return nil, s.invalid(v, "unsigned integer expected")
+		}
+		n = u
+	case uint8:
+		n = uint64(x)
+	case uint16:
+		n = uint64(x)
+	case uint32:
+		n = uint64(x)
+	case int:
+		if x < 0 {
+			return nil, s.invalid(v, "unsigned integer expected")
+		}
+		n = uint64(x)
+	default:
+		return nil, s.invalid(v, "number expected")
+	}
+
+	if n > 1<<(8*paramTypeSize[s.Type])-1 {
+		return nil, s.invalid(v, "overflows "+string(s.Type))
+	}
+	if s.Range != nil && (n < uint64(s.Range.Min) || n > uint64(s.Range.Max)) {
+		return nil, s.invalid(v, fmt.Sprintf("out of range [%d, %d]", s.Range.Min, s.Range.Max))
+	}
+	switch s.Type {
+	case ParamTypeUint8:
+		return uint8(n), nil
+	case ParamTypeUint16:
+		return uint16(n), nil
+	default:
+		return uint32(n), nil
+	}
+}
+
+// 编码参数值，不含参数ID和长度
+func (s *ParamSchema) encode(v any) (pkt []byte, err error) {
+	v, err = s.Normalize(v)
+	if err != nil {
+		return nil, err
+	}
+	switch s.Type {
+	case ParamTypeUint8:
+		return hex.WriteByte(pkt, v.(uint8)), nil
+	case ParamTypeUint16:
+		return hex.WriteWord(pkt, v.(uint16)), nil
+	case ParamTypeUint32:
+		return hex.WriteDoubleWord(pkt, v.(uint32)), nil
+	case ParamTypeString:
+		return GBK.UTF82GBK([]byte(v.(string))) // 已在Normalize中校验
+	case ParamTypeBCD:
+		return hex.WriteBCD(pkt, v.(string)), nil
+	default:
+		return hex.Str2Byte(v.(string)), nil
+	}
+}
+
+// 解码参数值，b为该参数的全部字节
+func (s *ParamSchema) decode(b []byte) (any, error) {
+	idx := 0
+	if n, ok := paramTypeSize[s.Type]; ok && len(b) != n {
+		return nil, errors.Wrapf(ErrDecodeDeviceParams, "param %s expects %d bytes, got %d", s.label(), n, len(b))
+	}
+	switch s.Type {
+	case ParamTypeUint8:
+		return hex.ReadByte(b, &idx), nil
+	case ParamTypeUint16:
+		return hex.ReadWord(b, &idx), nil
+	case ParamTypeUint32:
+		return hex.ReadDoubleWord(b, &idx), nil
+	case ParamTypeString:
+		str, err := GBK.GBK2UTF8(b)
+		if err != nil {
+			return nil, errors.Wrapf(ErrDecodeDeviceParams, "param %s is not GBK encoded", s.label())
+		}
+		return string(str), nil
+	case ParamTypeBCD:
+		return hex.ReadBCD(b, &idx, len(b)), nil
+	default:
+		return hex.Byte2Str(b), nil
+	}
+}
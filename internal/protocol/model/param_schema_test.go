@@ -0,0 +1,207 @@
+package model
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+)
+
+func TestNewParamData(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        uint32
+		paramName string
+		value     any
+		want      *ParamData
+		wantErr   error
+	}{
+		{
+			name: "dword from json number", id: 0x0001, value: float64(30),
+			want: &ParamData{ParamID: 0x0001, ParamName: "heartbeatInterval", ParamValue: uint32(30)},
+		},
+		{
+			name: "by name", paramName: "plateColor", value: "2",
+			want: &ParamData{ParamID: 0x0084, ParamName: "plateColor", ParamValue: uint8(2)},
+		},
+		{
+			name: "gbk string", id: 0x0083, value: "粤B12345",
+			want: &ParamData{ParamID: 0x0083, ParamName: "plate", ParamValue: "粤B12345"},
+		},
+		{
+			name: "unknown id as raw", id: 0xF001, value: "0A0B",
+			want: &ParamData{ParamID: 0xF001, ParamValue: "0a0b"},
+		},
+		{name: "unknown name", paramName: "foo", value: float64(1), wantErr: ErrParamNameNotSupported},
+		{name: "id and name mismatch", id: 0x0002, paramName: "heartbeatInterval", value: float64(1), wantErr: ErrInvalidParamValue},
+		{name: "string for number", id: 0x0001, value: "ten", wantErr: ErrInvalidParamValue},
+		{name: "negative", id: 0x0001, value: float64(-1), wantErr: ErrInvalidParamValue},
+		{name: "fraction", id: 0x0001, value: 1.5, wantErr: ErrInvalidParamValue},
+		{name: "overflow byte", id: 0x0084, value: float64(256), wantErr: ErrInvalidParamValue},
+		{name: "out of range", id: 0x0070, value: float64(0), wantErr: ErrInvalidParamValue},
+		{name: "positive only", id: 0x0029, value: float64(0), wantErr: ErrInvalidParamValue},
+		{name: "number for string", id: 0x0013, value: float64(1), wantErr: ErrInvalidParamValue},
+		{name: "invalid bcd", id: 0x0032, value: "08a0", wantErr: ErrInvalidParamValue},
+		{name: "invalid raw", id: 0xF001, value: "xyz", wantErr: ErrInvalidParamValue},
+		{name: "null", id: 0x0001, value: nil, wantErr: ErrInvalidParamValue},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewParamData(tt.id, tt.paramName, tt.value)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDeviceParams_EncodeDecode(t *testing.T) {
+	params := &DeviceParams{Params: []*ParamData{
+		{ParamID: 0x0001, ParamValue: float64(30)},
+		{ParamID: 0x0031, ParamValue: uint16(500)},
+		{ParamID: 0x0083, ParamValue: "粤B12345"},
+		{ParamID: 0x0084, ParamValue: uint8(2)},
+		{ParamID: 0x0075, ParamValue: "0102030405"},
+		{ParamID: 0xF001, ParamValue: "a1b2"},
+		{ParamID: 0x0002, ParamValue: "invalid"}, // 编码失败的参数被跳过，不计入个数
+	}}
+	pkt, err := params.Encode()
+	require.NoError(t, err)
+	require.Equal(t, uint8(6), pkt[0])
+
+	got := &DeviceParams{}
+	require.NoError(t, got.Decode("13800000000", pkt[0], pkt[1:]))
+	want := []*ParamData{
+		{ParamID: 0x0001, ParamName: "heartbeatInterval", ParamLen: 4, ParamValue: uint32(30)},
+		{ParamID: 0x0031, ParamName: "geofenceRadius", ParamLen: 2, ParamValue: uint16(500)},
+		{ParamID: 0x0083, ParamName: "plate", ParamLen: 8, ParamValue: "粤B12345"},
+		{ParamID: 0x0084, ParamName: "plateColor", ParamLen: 1, ParamValue: uint8(2)},
+		{ParamID: 0x0075, ParamName: "avParams", ParamLen: 5, ParamValue: "0102030405"},
+		{ParamID: 0xF001, ParamLen: 2, ParamValue: "a1b2"},
+	}
+	assert.Equal(t, want, got.Params)
+
+	// 未知参数原样回写
+	again, err := got.Encode()
+	require.NoError(t, err)
+	assert.Equal(t, pkt, again)
+}
+
+func TestParamData_Decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		pkt     string
+		want    *ParamData
+		wantErr bool
+	}{
+		{
+			name: "length mismatch keeps raw bytes",
+			pkt:  "00000001020001",
+			want: &ParamData{ParamID: 0x0001, ParamLen: 2, ParamValue: "0001", Raw: true},
+		},
+		{name: "truncated header", pkt: "000000", wantErr: true},
+		{name: "truncated value", pkt: "000000010400", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &ParamData{}
+			idx := 0
+			err := got.Decode(hex.Str2Byte(tt.pkt), &idx)
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrDecodeDeviceParams)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParamData_RawRoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		pkt  string
+	}{
+		{name: "uint32 param with 2 bytes", pkt: "00000001020102"},
+		{name: "hex letters", pkt: "0000000102ab0c"},
+		{name: "uint8 param with 2 bytes", pkt: "0000008402ff01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &ParamData{}
+			idx := 0
+			require.NoError(t, got.Decode(hex.Str2Byte(tt.pkt), &idx))
+			require.True(t, got.Raw)
+
+			pkt, err := got.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, tt.pkt, hex.Byte2Str(pkt))
+
+			// 持久化后仍原样回写
+			b, err := json.Marshal(got)
+			require.NoError(t, err)
+			reloaded := &ParamData{}
+			require.NoError(t, json.Unmarshal(b, reloaded))
+			pkt, err = reloaded.Encode()
+			require.NoError(t, err)
+			assert.Equal(t, tt.pkt, hex.Byte2Str(pkt))
+		})
+	}
+}
+
+func TestDeviceParams_NormalizeRaw(t *testing.T) {
+	tests := []struct {
+		name    string
+		param   *ParamData
+		wantErr bool
+	}{
+		{name: "raw known param", param: &ParamData{ParamID: 0x0013, ParamValue: "3132372e302e302e31", Raw: true}, wantErr: true},
+		{name: "raw known param with short bytes", param: &ParamData{ParamID: 0x0001, ParamValue: "0102", Raw: true}, wantErr: true},
+		{name: "raw unknown param", param: &ParamData{ParamID: 0xf001, ParamValue: "ab0c", Raw: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &DeviceParams{ParamCnt: 1, Params: []*ParamData{tt.param}}
+			err := p.Normalize()
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidParamValue)
+				return
+			}
+			require.NoError(t, err)
+			pkt, err := p.Params[0].Encode()
+			require.NoError(t, err)
+			assert.Equal(t, "0000f00102ab0c", hex.Byte2Str(pkt))
+		})
+	}
+}
+
+func TestParamSupportsVersion(t *testing.T) {
+	assert.True(t, ParamSupportsVersion(0x0018, Version2013))
+	assert.False(t, ParamSupportsVersion(0x0018, Version2019))
+	assert.True(t, ParamSupportsVersion(0xf001, Version2019)) // 未知参数透传
+}
+
+func TestParamSchema_SupportsVersion(t *testing.T) {
+	require.Len(t, paramSchemaByName, len(paramSchemas), "param names should be unique")
+
+	s, ok := LookupParamByID(0x0018)
+	require.True(t, ok)
+	assert.True(t, s.SupportsVersion(Version2013))
+	assert.False(t, s.SupportsVersion(Version2019))
+	assert.Equal(t, []string{"2011", "2013"}, s.Versions)
+
+	s, ok = LookupParamByName("slaveServerAPN")
+	require.True(t, ok)
+	assert.Equal(t, uint32(0x0023), s.ID)
+	assert.Equal(t, []string{"2019"}, s.Versions)
+
+	s, ok = LookupParamByID(0x0077)
+	require.True(t, ok)
+	assert.Equal(t, ParamProtocolJT1078, s.Protocol)
+}
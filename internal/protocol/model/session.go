@@ -1,9 +1,18 @@
 package model
 
 import (
+	"context"
 	"math"
 	"net"
+	"sync"
 	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+
+	"github.com/fakeyanss/jt808-server-go/pkg/routines"
 )
 
 const (
@@ -29,10 +38,84 @@ type (
 	PacketEncodeCtxKey struct{}
 )
 
+var (
+	ErrOutboundQueueFull = errors.New("Outbound queue is full")
+	ErrSessionWriterDone = errors.New("Session writer is done")
+)
+
+// 下行队列已满时的处理策略
+type OverflowPolicy string
+
+const (
+	OverflowBlock OverflowPolicy = "block" // 阻塞等待，超过EnqueueTimeout后返回ErrOutboundQueueFull
+	OverflowDrop  OverflowPolicy = "drop"  // 直接丢弃新消息，返回ErrOutboundQueueFull
+	OverflowClose OverflowPolicy = "close" // 认为对端消费过慢，关闭session
+)
+
+const (
+	DefaultOutboundQueueSize = 64
+	DefaultWriteTimeout      = 10 * time.Second
+	DefaultEnqueueTimeout    = 3 * time.Second
+)
+
+// session下行写协程的配置
+type WriterOptions struct {
+	QueueSize      int            // 下行队列长度
+	WriteTimeout   time.Duration  // 单次写conn的超时时间，0表示不设置
+	EnqueueTimeout time.Duration  // OverflowBlock策略下，入队等待的最长时间
+	OverflowPolicy OverflowPolicy // 队列已满时的处理策略
+}
+
+func DefaultWriterOptions() *WriterOptions {
+	return &WriterOptions{
+		QueueSize:      DefaultOutboundQueueSize,
+		WriteTimeout:   DefaultWriteTimeout,
+		EnqueueTimeout: DefaultEnqueueTimeout,
+		OverflowPolicy: OverflowBlock,
+	}
+}
+
 type Session struct {
 	ID           string // remote addr
 	Conn         net.Conn
+	Identity     string // TLS双向认证时，终端证书映射出的设备手机号，为空表示不校验
 	serialNumber uint32
+
+	// 下行写协程，所有回复和主动下发都经由outbound队列串行写入conn，避免帧交错
+	opts      *WriterOptions
+	outbound  chan *outFrame
+	done      chan struct{}
+	drained   chan struct{} // 写协程退出并清空队列后close
+	closeOnce sync.Once
+
+	authenticated  atomic.Bool                // 是否已完成注册鉴权
+	handshakeTimer atomic.Pointer[time.Timer] // 鉴权超时计时，鉴权完成或session关闭时停止
+	limiter        *rate.Limiter              // 上行消息速率限制，为nil时不限制
+	limitedCnt     atomic.Uint64              // 因超速被丢弃的帧数
+
+	CreatedAt  time.Time     // 建立连接的时间
+	bytesIn    atomic.Uint64 // 收到的帧字节数
+	bytesOut   atomic.Uint64 // 发出的帧字节数
+	lastActive atomic.Int64  // 最后一次收发帧的时间，unix毫秒
+}
+
+// 下行队列中的帧，sent不为nil时写协程写出后回传结果
+type outFrame struct {
+	payload []byte
+	sent    chan error
+}
+
+// session收发统计
+type SessionStats struct {
+	ID         string    `json:"id"`
+	RemoteAddr string    `json:"remoteAddr"`
+	Identity   string    `json:"identity,omitempty"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastActive time.Time `json:"lastActive"`
+	BytesIn    uint64    `json:"bytesIn"`
+	BytesOut   uint64    `json:"bytesOut"`
+	Authed     bool      `json:"authenticated"`
+	LimitedCnt uint64    `json:"limitedFrameCnt"`
 }
 
 func (s *Session) GetTransProto() TransportProtocol {
@@ -51,6 +134,261 @@ func (s *Session) GetNextSerialNum() uint16 {
 	return uint16(s.serialNumber)
 }
 
+// 标记session已完成鉴权
+func (s *Session) MarkAuthenticated() {
+	s.authenticated.Store(true)
+	s.stopHandshakeTimer()
+}
+
+// 未在timeout内完成鉴权时关闭session
+func (s *Session) CloseIfUnauthenticated(timeout time.Duration) {
+	s.handshakeTimer.Store(time.AfterFunc(timeout, func() {
+		if s.IsAuthenticated() {
+			return
+		}
+		log.Warn().Str("id", s.ID).Dur("timeout", timeout).Msg("Handshake timeout, close connection")
+		s.Close()
+	}))
+}
+
+func (s *Session) stopHandshakeTimer() {
+	if t := s.handshakeTimer.Load(); t != nil {
+		t.Stop()
+	}
+}
+
+func (s *Session) IsAuthenticated() bool {
+	return s.authenticated.Load()
+}
+
+// 设置上行消息速率限制，每秒msgPerSec条，允许突发burst条。msgPerSec<=0时不限制
+func (s *Session) SetRateLimit(msgPerSec float64, burst int) {
+	if msgPerSec <= 0 {
+		s.limiter = nil
+		return
+	}
+	if burst <= 0 {
+		burst = int(msgPerSec) + 1
+	}
+	s.limiter = rate.NewLimiter(rate.Limit(msgPerSec), burst)
+}
+
+// 判断当前收到的帧是否在速率限制内，超出时计数并返回false
+func (s *Session) AllowFrame() bool {
+	if s.limiter == nil || s.limiter.Allow() {
+		return true
+	}
+	s.limitedCnt.Add(1)
+	return false
+}
+
+// 因超出速率限制被丢弃的帧数
+func (s *Session) LimitedFrameCnt() uint64 {
+	return s.limitedCnt.Load()
+}
+
+// 记录收到的帧
+func (s *Session) RecordRecv(n int) {
+	s.bytesIn.Add(uint64(n))
+	s.lastActive.Store(time.Now().UnixMilli())
+}
+
+// 记录发出的帧
+func (s *Session) RecordSent(n int) {
+	s.bytesOut.Add(uint64(n))
+	s.lastActive.Store(time.Now().UnixMilli())
+}
+
+func (s *Session) Stats() *SessionStats {
+	stats := &SessionStats{
+		ID:         s.ID,
+		Identity:   s.Identity,
+		CreatedAt:  s.CreatedAt,
+		BytesIn:    s.bytesIn.Load(),
+		BytesOut:   s.bytesOut.Load(),
+		Authed:     s.IsAuthenticated(),
+		LimitedCnt: s.LimitedFrameCnt(),
+	}
+	if s.Conn != nil {
+		stats.RemoteAddr = s.Conn.RemoteAddr().String()
+	}
+	if last := s.lastActive.Load(); last > 0 {
+		stats.LastActive = time.UnixMilli(last)
+	}
+	return stats
+}
+
+// 启动session的下行写协程。未启动时，Write直接写conn(client端沿用此方式)
+func (s *Session) StartWriter(opts *WriterOptions) {
+	if opts == nil {
+		opts = DefaultWriterOptions()
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = DefaultOutboundQueueSize
+	}
+	s.opts = opts
+	s.outbound = make(chan *outFrame, opts.QueueSize)
+	s.done = make(chan struct{})
+	s.drained = make(chan struct{})
+	routines.GoSafe(s.writeLoop)
+}
+
+func (s *Session) HasWriter() bool {
+	return s.outbound != nil
+}
+
+// 将待发送的帧放入下行队列，由写协程串行写出，入队后即返回
+func (s *Session) Write(payload []byte) error {
+	if !s.HasWriter() {
+		err := writeFull(s.Conn, payload)
+		if err == nil {
+			s.RecordSent(len(payload))
+		}
+		return err
+	}
+	return s.enqueue(&outFrame{payload: payload})
+}
+
+// 同Write，并等待写协程写出该帧。session关闭时仍在队列中的帧返回ErrSessionWriterDone
+func (s *Session) WriteWait(ctx context.Context, payload []byte) error {
+	if !s.HasWriter() {
+		return s.Write(payload)
+	}
+	f := &outFrame{payload: payload, sent: make(chan error, 1)}
+	if err := s.enqueue(f); err != nil {
+		return err
+	}
+	select {
+	case err := <-f.sent:
+		return err
+	case <-s.drained:
+		// 在写协程清空队列之后入队的帧不会被写出
+		select {
+		case err := <-f.sent:
+			return err
+		default:
+			return ErrSessionWriterDone
+		}
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s *Session) enqueue(f *outFrame) error {
+	select {
+	case <-s.done:
+		return ErrSessionWriterDone
+	default:
+	}
+
+	select {
+	case s.outbound <- f:
+		return nil
+	default:
+	}
+
+	// 队列已满
+	switch s.opts.OverflowPolicy {
+	case OverflowDrop:
+		log.Warn().Str("id", s.ID).Int("queue_size", s.opts.QueueSize).Msg("Outbound queue is full, drop frame")
+		return ErrOutboundQueueFull
+	case OverflowClose:
+		log.Warn().Str("id", s.ID).Int("queue_size", s.opts.QueueSize).Msg("Outbound queue is full, close session")
+		s.Close()
+		return ErrOutboundQueueFull
+	default:
+		timer := time.NewTimer(s.opts.EnqueueTimeout)
+		defer timer.Stop()
+		select {
+		case s.outbound <- f:
+			return nil
+		case <-s.done:
+			return ErrSessionWriterDone
+		case <-timer.C:
+			log.Warn().Str("id", s.ID).Dur("timeout", s.opts.EnqueueTimeout).Msg("Timeout waiting for outbound queue")
+			return ErrOutboundQueueFull
+		}
+	}
+}
+
+// 关闭session写协程和conn，可重复调用
+func (s *Session) Close() error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.stopHandshakeTimer()
+		if s.done != nil {
+			close(s.done)
+		}
+		if s.Conn != nil {
+			err = s.Conn.Close()
+		}
+	})
+	return err
+}
+
+// session关闭后，返回的channel会被close
+func (s *Session) Done() <-chan struct{} {
+	return s.done
+}
+
+func (s *Session) writeLoop() {
+	defer s.drain()
+	for {
+		select {
+		case <-s.done:
+			return
+		case f := <-s.outbound:
+			payload := f.payload
+			if s.opts.WriteTimeout > 0 {
+				_ = s.Conn.SetWriteDeadline(time.Now().Add(s.opts.WriteTimeout))
+			}
+			err := writeFull(s.Conn, payload)
+			if f.sent != nil {
+				f.sent <- err
+			}
+			if err != nil {
+				log.Error().Err(err).Str("id", s.ID).Msg("Fail to write frame, close session")
+				s.Close()
+				return
+			}
+			s.RecordSent(len(payload))
+			log.Debug().Str("id", s.ID).Int("frame_len", len(payload)).Hex("frame_payload", payload).Msg("Sent frame.")
+		}
+	}
+}
+
+// 写协程退出后清空队列，未写出的帧不再发送，等待结果的调用方收到ErrSessionWriterDone
+func (s *Session) drain() {
+	dropped := 0
+	for {
+		select {
+		case f := <-s.outbound:
+			dropped++
+			if f.sent != nil {
+				f.sent <- ErrSessionWriterDone
+			}
+		default:
+			close(s.drained)
+			if dropped > 0 {
+				log.Warn().Str("id", s.ID).Int("dropped", dropped).Msg("Session closed, drop queued frames")
+			}
+			return
+		}
+	}
+}
+
+func writeFull(conn net.Conn, payload []byte) error {
+	p := payload
+	for len(p) > 0 {
+		n, err := conn.Write(p)
+		if err != nil {
+			return errors.Wrap(err, "Failed to send payload")
+		}
+		p = p[n:] // 没写完所有数据，再写一次
+	}
+	return nil
+}
+
 // 定义Packet Data结构
 type PacketData struct {
 	Header       *MsgHeader // 消息头
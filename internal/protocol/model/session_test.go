@@ -0,0 +1,150 @@
+package model
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession_WriteSerialized(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+
+	session := &Session{ID: "test", Conn: server}
+	session.StartWriter(DefaultWriterOptions())
+	defer session.Close()
+
+	frames := [][]byte{
+		bytes.Repeat([]byte{0x01}, 128),
+		bytes.Repeat([]byte{0x02}, 128),
+		bytes.Repeat([]byte{0x03}, 128),
+		bytes.Repeat([]byte{0x04}, 128),
+	}
+
+	var wg sync.WaitGroup
+	for _, f := range frames {
+		wg.Add(1)
+		go func(f []byte) {
+			defer wg.Done()
+			assert.NoError(t, session.Write(f))
+		}(f)
+	}
+
+	buf := make([]byte, 128*len(frames))
+	_, err := io.ReadFull(client, buf)
+	require.NoError(t, err)
+	wg.Wait()
+
+	// 每帧必须完整连续，不能与其他帧交错
+	for i := 0; i < len(frames); i++ {
+		frame := buf[i*128 : (i+1)*128]
+		assert.Equal(t, bytes.Repeat(frame[:1], 128), frame)
+	}
+}
+
+func TestSession_WriteOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  OverflowPolicy
+		wantErr error
+		closed  bool
+	}{
+		{name: "drop newest frame", policy: OverflowDrop, wantErr: ErrOutboundQueueFull},
+		{name: "block until timeout", policy: OverflowBlock, wantErr: ErrOutboundQueueFull},
+		{name: "close slow session", policy: OverflowClose, wantErr: ErrOutboundQueueFull, closed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, client := net.Pipe() // 对端不读取，写协程阻塞
+			defer client.Close()
+
+			session := &Session{ID: "test", Conn: server}
+			session.StartWriter(&WriterOptions{
+				QueueSize:      1,
+				EnqueueTimeout: 10 * time.Millisecond,
+				OverflowPolicy: tt.policy,
+			})
+			defer session.Close()
+
+			var err error
+			for i := 0; i < 3 && err == nil; i++ {
+				err = session.Write([]byte{0x7e, byte(i), 0x7e})
+			}
+			assert.ErrorIs(t, err, tt.wantErr)
+
+			select {
+			case <-session.Done():
+				assert.True(t, tt.closed)
+			default:
+				assert.False(t, tt.closed)
+			}
+		})
+	}
+}
+
+func TestSession_WriteWait(t *testing.T) {
+	server, client := net.Pipe()
+	defer client.Close()
+	session := &Session{ID: "test", Conn: server}
+	session.StartWriter(DefaultWriterOptions())
+
+	go func() { _, _ = io.ReadFull(client, make([]byte, 3)) }()
+	require.NoError(t, session.WriteWait(context.Background(), []byte{0x7e, 0x01, 0x7e}))
+
+	// 对端不再读取，第一帧阻塞在写conn，第二帧留在队列中
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func(i int) { errs <- session.WriteWait(context.Background(), []byte{0x7e, byte(i), 0x7e}) }(i)
+	}
+	require.Eventually(t, func() bool { return len(session.outbound) == 1 }, time.Second, time.Millisecond)
+	require.NoError(t, session.Close())
+
+	// 关闭时未写出的帧返回错误，不会被当作已发送
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errs:
+			assert.Error(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("WriteWait not returned after session closed")
+		}
+	}
+	assert.ErrorIs(t, session.WriteWait(context.Background(), []byte{0x7e, 0x7e}), ErrSessionWriterDone)
+}
+
+func TestSession_CloseIfUnauthenticated(t *testing.T) {
+	tests := []struct {
+		name   string
+		authed bool
+		closed bool
+	}{
+		{name: "timeout", authed: false, closed: true},
+		{name: "authenticated stops timer", authed: true, closed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			session := &Session{ID: "test"}
+			session.StartWriter(DefaultWriterOptions())
+			defer session.Close()
+			session.CloseIfUnauthenticated(20 * time.Millisecond)
+			if tt.authed {
+				session.MarkAuthenticated()
+				// 计时已停止，不会再触发
+				assert.False(t, session.handshakeTimer.Load().Stop())
+			}
+			time.Sleep(50 * time.Millisecond)
+			select {
+			case <-session.Done():
+				assert.True(t, tt.closed)
+			default:
+				assert.False(t, tt.closed)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package model
+
+import (
+	"time"
+)
+
+// 0x0200报警标志位的名称，下标为bit位，保留位为空
+var alarmNames = [32]string{
+	"紧急报警", "超速报警", "疲劳驾驶报警", "危险驾驶行为报警", "GNSS模块故障", "GNSS天线未接或被剪断", "GNSS天线短路", "终端主电源欠压",
+	"终端主电源掉电", "终端LCD或显示器故障", "TTS模块故障", "摄像头故障", "道路运输证IC卡模块故障", "超速预警", "疲劳驾驶预警", "违规行驶报警",
+	"胎压预警", "右转盲区异常报警", "当天累计驾驶超时", "超时停车", "进出区域", "进出路线", "路段行驶时间不足/过长", "路线偏离报警",
+	"车辆VSS故障", "车辆油量异常", "车辆被盗", "车辆非法点火", "车辆非法位移", "碰撞侧翻报警", "侧翻预警", "非法开门报警",
+}
+
+// 报警标志位中置位的报警名称，按bit位升序
+func AlarmNames(sign uint32) []string {
+	var res []string
+	for i, name := range alarmNames {
+		if sign&(1<<i) != 0 && name != "" {
+			res = append(res, name)
+		}
+	}
+	return res
+}
+
+// 历史轨迹点，由位置汇报生成，经纬度为WGS-84
+type TrackPoint struct {
+	Time       time.Time `json:"time"`
+	Latitude   float64   `json:"lat"`
+	Longitude  float64   `json:"lng"`
+	Altitude   uint16    `json:"alt"`
+	Speed      float64   `json:"speed"`                // km/h
+	Direction  uint16    `json:"dir"`                  // 0-359，正北为0，顺时针
+	StatusSign uint32    `json:"status"`               // 状态位
+	AlarmSign  uint32    `json:"alarm,omitempty"`      // 终端上报的报警标志位
+	ServerSign uint32    `json:"srvAlarm,omitempty"`   // 平台侧规则判断的报警标志位
+	Mileage    *float64  `json:"mileage,omitempty"`    // 里程表读数，km
+	Region     string    `json:"region,omitempty"`     // 所在行政区代码
+	ReceivedAt time.Time `json:"receivedAt,omitempty"` // 平台接收时间
+	Quality    []string  `json:"quality,omitempty"`    // 数据质量过滤标记的问题，保留原始点用于审计
+}
+
+func NewTrackPoint(dg *DeviceGeo, statusSign, alarmSign, serverSign uint32) *TrackPoint {
+	p := &TrackPoint{
+		Time:       dg.Time,
+		StatusSign: statusSign,
+		AlarmSign:  alarmSign,
+		ServerSign: serverSign,
+		Mileage:    dg.Mileage,
+		ReceivedAt: time.Now(),
+		Quality:    dg.Quality,
+	}
+	if dg.Location != nil {
+		p.Latitude = dg.Location.Latitude
+		p.Longitude = dg.Location.Longitude
+		p.Altitude = dg.Location.Altitude
+	}
+	if dg.Drive != nil {
+		p.Speed = dg.Drive.Speed
+		p.Direction = dg.Drive.Direction
+	}
+	if dg.Region != nil {
+		p.Region = dg.Region.Code
+	}
+	return p
+}
+
+// ACC是否开
+func (p *TrackPoint) ACC() bool {
+	return p.StatusSign&accBit != 0
+}
+
+// 是否已定位
+func (p *TrackPoint) Located() bool {
+	return p.StatusSign&locationStatusBit != 0
+}
+
+// 终端及平台侧报警的名称
+func (p *TrackPoint) Alarms() []string {
+	return AlarmNames(p.AlarmSign | p.ServerSign)
+}
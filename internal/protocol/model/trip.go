@@ -0,0 +1,121 @@
+package model
+
+import (
+	"sort"
+	"time"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+)
+
+// 行程，车辆从开始行驶到熄火、长时间停车或长时间未上报位置为止
+type Trip struct {
+	Phone         string        `json:"phone"`
+	StartTime     time.Time     `json:"startTime"`
+	EndTime       time.Time     `json:"endTime"`
+	StartLocation geo.Point     `json:"startLocation"`
+	EndLocation   geo.Point     `json:"endLocation"`
+	Distance      float64       `json:"distance"`          // 按轨迹点累计的距离，米
+	Mileage       *float64      `json:"mileage,omitempty"` // 按里程表读数计算的里程，km，起止点都上报里程时有效
+	MaxSpeed      float64       `json:"maxSpeed"`          // 最高速度，km/h
+	IdleDuration  time.Duration `json:"idleDuration"`      // 怠速时长，ACC开且静止
+	Stops         []*TripStop   `json:"stops"`             // 行程中的停车，含行程结束时仍在进行的停车
+	Points        int           `json:"points"`            // 轨迹点数
+}
+
+// 行驶时长
+func (t *Trip) Duration() time.Duration {
+	return t.EndTime.Sub(t.StartTime)
+}
+
+// 平均速度，km/h
+func (t *Trip) AvgSpeed() float64 {
+	if d := t.Duration(); d > 0 {
+		return t.Distance / 1000 / d.Hours()
+	}
+	return 0
+}
+
+func (t *Trip) Clone() *Trip {
+	res := *t
+	res.Stops = make([]*TripStop, 0, len(t.Stops))
+	for _, s := range t.Stops {
+		stop := *s
+		res.Stops = append(res.Stops, &stop)
+	}
+	return &res
+}
+
+// 行程中的停车，Idling表示停车期间ACC一直开启
+type TripStop struct {
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Location  geo.Point `json:"location"`
+	Idling    bool      `json:"idling"`
+}
+
+func (s *TripStop) Duration() time.Duration {
+	return s.EndTime.Sub(s.StartTime)
+}
+
+// 行程外的怠速，车辆不在行程中且ACC开、静止
+type IdlePeriod struct {
+	Phone     string    `json:"phone"`
+	StartTime time.Time `json:"startTime"`
+	EndTime   time.Time `json:"endTime"`
+	Location  geo.Point `json:"location"`
+}
+
+func (p *IdlePeriod) Duration() time.Duration {
+	return p.EndTime.Sub(p.StartTime)
+}
+
+// 单日行程汇总，按行程及怠速开始时间所在日期(设备时间)统计
+type TripDaySummary struct {
+	Date          string        `json:"date"` // YYYY-MM-DD
+	Trips         int           `json:"trips"`
+	Distance      float64       `json:"distance"` // 米
+	Mileage       float64       `json:"mileage"`  // km，仅统计上报里程的行程
+	DriveDuration time.Duration `json:"driveDuration"`
+	IdleDuration  time.Duration `json:"idleDuration"` // 行程内外的怠速时长
+	Stops         int           `json:"stops"`
+	StopDuration  time.Duration `json:"stopDuration"`
+	MaxSpeed      float64       `json:"maxSpeed"`
+}
+
+// 按日期汇总行程及行程外的怠速，日期升序
+func SummarizeTrips(trips []*Trip, idles []*IdlePeriod) []*TripDaySummary {
+	res := make([]*TripDaySummary, 0)
+	byDate := make(map[string]*TripDaySummary)
+	dayOf := func(t time.Time) *TripDaySummary {
+		date := t.Format("2006-01-02")
+		s, ok := byDate[date]
+		if !ok {
+			s = &TripDaySummary{Date: date}
+			byDate[date] = s
+			res = append(res, s)
+		}
+		return s
+	}
+	for _, t := range trips {
+		s := dayOf(t.StartTime)
+		s.Trips++
+		s.Distance += t.Distance
+		if t.Mileage != nil {
+			s.Mileage += *t.Mileage
+		}
+		s.DriveDuration += t.Duration()
+		s.IdleDuration += t.IdleDuration
+		s.Stops += len(t.Stops)
+		for _, stop := range t.Stops {
+			s.StopDuration += stop.Duration()
+		}
+		if t.MaxSpeed > s.MaxSpeed {
+			s.MaxSpeed = t.MaxSpeed
+		}
+	}
+	for _, p := range idles {
+		dayOf(p.StartTime).IdleDuration += p.Duration()
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Date < res[j].Date })
+	return res
+}
@@ -13,10 +13,19 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
 	"github.com/fakeyanss/jt808-server-go/internal/codec/hash"
 	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/region"
+	"github.com/fakeyanss/jt808-server-go/internal/driving"
+	"github.com/fakeyanss/jt808-server-go/internal/event"
+	"github.com/fakeyanss/jt808-server-go/internal/geofence"
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
 	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/quality"
+	"github.com/fakeyanss/jt808-server-go/internal/spatial"
 	"github.com/fakeyanss/jt808-server-go/internal/storage"
+	"github.com/fakeyanss/jt808-server-go/internal/trip"
 )
 
 var (
@@ -45,6 +54,7 @@ func initProcessOption() processOptions {
 		genData: func() *model.ProcessData {
 			return &model.ProcessData{Incoming: &model.Msg0001{}} // 无需回复
 		},
+		process: processMsg0001,
 	}
 	options[0x0002] = &action{ // 心跳
 		genData: func() *model.ProcessData {
@@ -74,6 +84,25 @@ func initProcessOption() processOptions {
 		genData: func() *model.ProcessData {
 			return &model.ProcessData{Incoming: &model.Msg0104{}} // 无需回复
 		},
+		process: processMsg0104,
+	}
+	options[0x0107] = &action{ // 查询终端属性应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg0107{}} // 无需回复
+		},
+		process: processMsg0107,
+	}
+	options[0x0108] = &action{ // 终端升级结果通知
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg0108{}, Outgoing: &model.Msg8001{}}
+		},
+		process: processMsg0108,
+	}
+	options[0x0608] = &action{ // 查询区域或线路数据应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg0608{}} // 无需回复
+		},
+		process: processMsg0608,
 	}
 	options[0x0200] = &action{ // 位置信息上报
 		genData: func() *model.ProcessData {
@@ -110,6 +139,83 @@ func initProcessOption() processOptions {
 		},
 		process: processMsg8104,
 	}
+	options[0x8105] = &action{ // 终端控制，模拟终端仅回复通用应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8105{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8106] = &action{ // 查询指定终端参数
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8106{}, Outgoing: &model.Msg0104{}}
+		},
+		process: processMsg8106,
+	}
+	options[0x8107] = &action{ // 查询终端属性
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8107{}, Outgoing: &model.Msg0107{}}
+		},
+		process: processMsg8107,
+	}
+	options[0x8108] = &action{ // 下发终端升级包，模拟终端直接上报升级成功
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8108{}, Outgoing: &model.Msg0108{}}
+		},
+	}
+	options[0x8202] = &action{ // 临时位置跟踪控制
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8202{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8300] = &action{ // 文本信息下发
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8300{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8600] = &action{ // 设置圆形区域，模拟终端仅回复通用应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8600{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8601] = &action{ // 删除圆形区域，模拟终端仅回复通用应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8601{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8602] = &action{ // 设置矩形区域，模拟终端仅回复通用应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8602{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8603] = &action{ // 删除矩形区域，模拟终端仅回复通用应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8603{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8604] = &action{ // 设置多边形区域，模拟终端仅回复通用应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8604{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8605] = &action{ // 删除多边形区域，模拟终端仅回复通用应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8605{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8606] = &action{ // 设置路线，模拟终端仅回复通用应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8606{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8607] = &action{ // 删除路线，模拟终端仅回复通用应答
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8607{}, Outgoing: &model.Msg0001{}}
+		},
+	}
+	options[0x8608] = &action{ // 查询区域或线路数据，模拟终端应答空列表
+		genData: func() *model.ProcessData {
+			return &model.ProcessData{Incoming: &model.Msg8608{}, Outgoing: &model.Msg0608{}}
+		},
+	}
 	options[0x9205] = &action{ // 查询终端音视频资源列表
 		genData: func() *model.ProcessData {
 			return &model.ProcessData{Incoming: &model.Msg9205{}, Outgoing: &model.Msg1205{}}
@@ -159,6 +265,7 @@ func (mp *JT808MsgProcessor) Process(ctx context.Context, pkt *model.PacketData)
 	in := data.Incoming
 	err := in.Decode(pkt)
 	if err != nil {
+		metrics.DecodeFailures.WithLabelValues("decode_msg").Inc()
 		return nil, errors.Wrap(err, "Fail to decode packet to jtmsg")
 	}
 
@@ -220,6 +327,15 @@ func processSegmentPacket(_ context.Context, pkt *model.PacketData) (*model.Proc
 		return nil, errors.Wrapf(err, "Fail to find device cache, phoneNumber=%s", phone)
 	}
 	session, err := storage.GetSession(device.SessionID)
+	if pkt.Header.MsgID&0x8000 != 0 {
+		// 平台下发的分包(此时是作为client进程)，回复终端通用应答
+		return &model.ProcessData{Outgoing: &model.Msg0001{
+			Header:             model.GenMsgHeader(device, 0x0001, session.GetNextSerialNum()),
+			AnswerSerialNumber: pkt.Header.SerialNumber,
+			AnswerMessageID:    pkt.Header.MsgID,
+			Result:             uint8(model.ResultSuccess),
+		}}, nil
+	}
 	header := model.GenMsgHeader(device, 0x8001, session.GetNextSerialNum())
 	outgoingMsg := &model.Msg8001{
 		Header:             header,
@@ -230,6 +346,13 @@ func processSegmentPacket(_ context.Context, pkt *model.PacketData) (*model.Proc
 	return &model.ProcessData{Outgoing: outgoingMsg}, nil
 }
 
+// 收到终端通用应答，投递给等待该流水号应答的下发方
+func processMsg0001(_ context.Context, data *model.ProcessData) error {
+	in := data.Incoming.(*model.Msg0001)
+	deliverAnswer(in.Header.PhoneNumber, in.AnswerSerialNumber, in)
+	return nil
+}
+
 // 收到心跳，应刷新终端缓存有效期
 func processMsg0002(_ context.Context, data *model.ProcessData) error {
 	cache := storage.GetDeviceCache()
@@ -259,6 +382,13 @@ func processMsg0003(_ context.Context, data *model.ProcessData) error {
 	timer.Cancel(device.Phone)
 	// 清楚缓存
 	cache.DelDeviceByPhone(device.Phone)
+	spatial.Forget(device.Phone)
+	quality.Forget(device.Phone)
+	geofence.Forget(device.Phone)
+	trip.Forget(device.Phone)
+	storage.GetTripCache().DelTripsByPhone(device.Phone)
+	driving.Forget(device.Phone)
+	event.PublishStatus(device.Phone, device.Status, model.DeviceStatusOffline)
 	// 为避免连接TIMEWAIT，应等待对方主动关闭
 	return nil
 }
@@ -277,6 +407,13 @@ func processMsg0100(ctx context.Context, data *model.ProcessData) error {
 	}
 
 	session := ctx.Value(model.SessionCtxKey{}).(*model.Session)
+	// 终端证书与注册手机号不一致
+	if session.Identity != "" && session.Identity != in.Header.PhoneNumber {
+		log.Warn().Str("id", session.ID).Str("identity", session.Identity).Str("device", in.Header.PhoneNumber).
+			Msg("Device phone mismatch with tls client certificate")
+		out.Result = model.ResDeviceNotExist
+		return nil
+	}
 	device := model.NewDevice(in, session)
 	out.AuthCode = genAuthCode(device) // 设置鉴权码
 
@@ -288,7 +425,7 @@ func processMsg0100(ctx context.Context, data *model.ProcessData) error {
 }
 
 // 收到鉴权，应校验鉴权token
-func processMsg0102(_ context.Context, data *model.ProcessData) error {
+func processMsg0102(ctx context.Context, data *model.ProcessData) error {
 	in := data.Incoming.(*model.Msg0102)
 
 	cache := storage.GetDeviceCache()
@@ -299,8 +436,9 @@ func processMsg0102(_ context.Context, data *model.ProcessData) error {
 	}
 
 	out := data.Outgoing.(*model.Msg8001)
-	// 校验鉴权逻辑
-	if in.AuthCode != genAuthCode(device) {
+	session, _ := ctx.Value(model.SessionCtxKey{}).(*model.Session)
+	// 校验鉴权逻辑，启用TLS双向认证时终端证书须与设备手机号一致
+	if in.AuthCode != genAuthCode(device) || (session != nil && session.Identity != "" && session.Identity != device.Phone) {
 		out.Result = model.ResultFail
 		// 取消定时任务
 		timer := NewKeepaliveTimer()
@@ -309,12 +447,16 @@ func processMsg0102(_ context.Context, data *model.ProcessData) error {
 		cache.DelDeviceByPhone(device.Phone)
 	} else {
 		// 鉴权通过
+		event.PublishStatus(device.Phone, device.Status, model.DeviceStatusOnline)
 		device.Status = model.DeviceStatusOnline
 		device.LastestComTime = time.Now()
 		device.AuthCode = in.AuthCode
 		device.IMEI = in.IMEI
 		device.SoftwareVersion = in.SoftwareVersion
 		cache.CacheDevice(device)
+		if session != nil {
+			session.MarkAuthenticated()
+		}
 	}
 
 	return nil
@@ -331,9 +473,46 @@ func genAuthCode(d *model.Device) string {
 	return strconv.Itoa(int(hash.FNV32(codeBuilder.String())))
 }
 
-// 收到查询终端参数应答，无需回复，可以在这里做一个一个channel write，由其他地方阻塞式read来完成hook功能。
-func processMsg0104(_ context.Context, _ *model.ProcessData) error {
-	// todo: write channel
+// 收到查询终端参数应答，无需回复，投递给等待该流水号应答的下发方
+func processMsg0104(_ context.Context, data *model.ProcessData) error {
+	in := data.Incoming.(*model.Msg0104)
+	deliverAnswer(in.Header.PhoneNumber, in.AnswerSerialNumber, in)
+	return nil
+}
+
+// 收到查询终端属性应答，更新设备缓存，投递给等待应答的下发方
+func processMsg0107(_ context.Context, data *model.ProcessData) error {
+	in := data.Incoming.(*model.Msg0107)
+
+	cache := storage.GetDeviceCache()
+	device, err := cache.GetDeviceByPhone(in.Header.PhoneNumber)
+	if errors.Is(err, storage.ErrDeviceNotFound) {
+		return errors.Wrapf(err, "Fail to find device cache, phoneNumber=%s", in.Header.PhoneNumber)
+	}
+	in.Properties.UpdatedAt = time.Now()
+	device.Properties = in.Properties
+	device.LastestComTime = time.Now()
+	cache.CacheDevice(device)
+
+	deliverAnswerByMsgID(device.Phone, 0x8107, in)
+	return nil
+}
+
+// 收到终端升级结果，更新升级任务中该设备的状态，投递给等待最后一个分包应答的下发方
+func processMsg0108(_ context.Context, data *model.ProcessData) error {
+	in := data.Incoming.(*model.Msg0108)
+	phone := in.Header.PhoneNumber
+	if taskID, ok := storage.GetUpgradeCache().ReportResult(phone, in.Result); ok {
+		log.Info().Str("device", phone).Str("task", taskID).Uint8("result", in.Result).Msg("Device upgrade finished")
+	}
+	deliverAnswerByMsgID(phone, 0x8108, in)
+	return nil
+}
+
+// 收到查询区域或线路数据应答，应答中没有流水号，按消息ID投递给等待应答的下发方
+func processMsg0608(_ context.Context, data *model.ProcessData) error {
+	in := data.Incoming.(*model.Msg0608)
+	deliverAnswerByMsgID(in.Header.PhoneNumber, 0x8608, in)
 	return nil
 }
 
@@ -354,17 +533,49 @@ func processMsg0200(_ context.Context, data *model.ProcessData) error {
 	if err != nil {
 		return errors.Wrapf(err, "Fail to decode device geo, phoneNumber=%s", device.Phone)
 	}
+	fixed := quality.IsFixed(dg)
+	if fixed { // 离线逆地理编码，未加载边界数据时为nil
+		dg.Region = region.Locate(geo.Point{Lat: dg.Location.Latitude, Lng: dg.Location.Longitude})
+	}
 
 	if dg.Geo.ACCStatus == 0 { // ACC关闭，设备休眠
+		event.PublishStatus(device.Phone, device.Status, model.DeviceStatusSleeping)
 		device.Status = model.DeviceStatusSleeping
 		device.LastestComTime = time.Now()
 		cache.CacheDevice(device)
 	}
 
-	geoCache := storage.GetGeoCache()
-	rb := geoCache.GetGeoRingByPhone(device.Phone)
-	rb.Write(dg)
+	// 数据质量过滤，不合格的点不参与分析，原始点仍写入历史轨迹
+	satellites := -1
+	if _, ok := in.AttachData[0x31]; ok {
+		satellites = int(dg.Sattelite)
+	}
+	dg.Quality = quality.Check(dg, satellites)
+	for _, reason := range dg.Quality {
+		metrics.FilteredLocations.WithLabelValues(reason).Inc()
+	}
 
+	if len(dg.Quality) == 0 || quality.ActionOf() == quality.ActionFlag {
+		geoCache := storage.GetGeoCache()
+		rb := geoCache.GetGeoRingByPhone(device.Phone)
+		rb.Write(dg)
+		event.PublishLocation(device.Phone, in.AlarmSign, dg)
+	}
+
+	var serverSign uint32
+	if len(dg.Quality) == 0 {
+		if fixed { // 未开启过滤时未定位的点同样不更新位置索引和围栏状态
+			spatial.Update(dg)
+			geofence.Evaluate(device, dg)
+		}
+		serverSign = driving.Evaluate(device.Phone, in.AlarmSign, dg)
+		trip.Feed(dg)
+	}
+	tp := model.NewTrackPoint(dg, in.StatusSign, in.AlarmSign, serverSign)
+	tp.Quality = quality.TrackReasons(dg)
+	if err := storage.GetTrackStore().Append(device.Phone, tp); err != nil {
+		log.Warn().Err(err).Str("phone", device.Phone).Msg("Fail to append track point")
+	}
 	return nil
 }
 
@@ -479,6 +690,52 @@ func processMsg8104(_ context.Context, data *model.ProcessData) error {
 	return nil
 }
 
+// 收到查询指定终端参数请求，回复请求的终端参数(此时是作为client进程)
+func processMsg8106(ctx context.Context, data *model.ProcessData) error {
+	in := data.Incoming.(*model.Msg8106)
+	out := data.Outgoing.(*model.Msg0104)
+	if err := processMsg8104(ctx, data); err != nil {
+		return err
+	}
+	wanted := make(map[uint32]bool, len(in.ParamIDs))
+	for _, id := range in.ParamIDs {
+		wanted[id] = true
+	}
+	params := &model.DeviceParams{DevicePhone: out.Parameters.DevicePhone}
+	for _, p := range out.Parameters.Params {
+		if wanted[p.ParamID] {
+			params.Params = append(params.Params, p)
+		}
+	}
+	params.ParamCnt = uint8(len(params.Params))
+	out.Parameters = params
+	return nil
+}
+
+// 收到查询终端属性请求，按设备缓存回复终端属性(此时是作为client进程)
+func processMsg8107(_ context.Context, data *model.ProcessData) error {
+	out := data.Outgoing.(*model.Msg0107)
+	device, err := storage.GetDeviceCache().GetDeviceByPhone(out.Header.PhoneNumber)
+	if errors.Is(err, storage.ErrDeviceNotFound) {
+		return ErrActiveClose
+	}
+	if device.Properties != nil {
+		out.Properties = device.Properties
+		return nil
+	}
+	out.Properties = &model.DeviceProperties{
+		TerminalType:    0x0004, // 普通货运车辆
+		ManufacturerID:  device.ManufacturerID,
+		TerminalModel:   device.DeviceMode,
+		TerminalID:      device.ID,
+		HardwareVersion: "1.0",
+		FirmwareVersion: device.SoftwareVersion,
+		GNSSProperty:    0x03, // GPS + 北斗
+		CommProperty:    0x01, // GPRS
+	}
+	return nil
+}
+
 func processMsg9205(_ context.Context, data *model.ProcessData) error {
 	in := data.Incoming.(*model.Msg9205)
 	out := data.Outgoing.(*model.Msg1205)
@@ -0,0 +1,68 @@
+package protocol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/quality"
+	"github.com/fakeyanss/jt808-server-go/internal/spatial"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+// 未开启数据质量过滤时，未定位的点不进入位置索引，写入历史轨迹时标记为unfixed
+func TestProcessMsg0200_Unfixed(t *testing.T) {
+	phone := "13900000050"
+	storage.GetDeviceCache().CacheDevice(&model.Device{Phone: phone, Status: model.DeviceStatusOnline})
+	store := storage.GetTrackStore()
+	store.Configure(true, t.TempDir(), 0)
+	quality.Configure(nil)
+	t.Cleanup(func() {
+		storage.GetDeviceCache().DelDeviceByPhone(phone)
+		storage.GetGeoCache().DelGeoByPhone(phone)
+		spatial.Forget(phone)
+		store.Configure(true, "track", 0)
+	})
+
+	tests := []struct {
+		name        string
+		statusSign  uint32
+		lat, lng    uint32
+		time        string
+		wantIndexed bool
+	}{
+		{name: "not located", statusSign: 0b01, lat: 39900000, lng: 116400000, time: "240501080000"},
+		{name: "zero coordinate", statusSign: 0b11, time: "240501080100"},
+		{name: "located", statusSign: 0b11, lat: 39900000, lng: 116400000, time: "240501080200", wantIndexed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &model.Msg0200{
+				Header:     &model.MsgHeader{MsgID: 0x0200, PhoneNumber: phone},
+				StatusSign: tt.statusSign,
+				Latitude:   tt.lat,
+				Longitude:  tt.lng,
+				Time:       tt.time,
+			}
+			require.NoError(t, processMsg0200(context.Background(), &model.ProcessData{Incoming: in}))
+			indexed := false
+			for _, r := range spatial.Within(geo.Point{Lat: 90, Lng: -180}, geo.Point{Lat: -90, Lng: 180}, 0, nil) {
+				indexed = indexed || r.Geo.Phone == phone
+			}
+			assert.Equal(t, tt.wantIndexed, indexed)
+		})
+	}
+
+	var reasons [][]string
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Iterate(phone, from, from.AddDate(0, 0, 1), func(p *model.TrackPoint) error {
+		reasons = append(reasons, p.Quality)
+		return nil
+	}))
+	assert.Equal(t, [][]string{{quality.ReasonUnfixed}, {quality.ReasonUnfixed}, nil}, reasons)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
 
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
 	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
 	"github.com/fakeyanss/jt808-server-go/internal/storage"
 )
@@ -69,6 +70,9 @@ func (pc *JT808PacketCodec) Decode(payload []byte) (*model.PacketData, error) {
 	if pd.Header.IsFragmented() {
 		seg := model.NewSegment(pd)
 		pd.SegCompleted = storage.CacheSegment(seg)
+		if pd.SegCompleted {
+			metrics.SegmentsCompleted.Inc()
+		}
 		// 分包接收完成
 		pd.Body = seg.Data
 	}
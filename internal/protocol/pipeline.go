@@ -3,8 +3,15 @@ package protocol
 import (
 	"context"
 	"net"
+	"time"
 
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
 	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/tracing"
 )
 
 // tcp/udp 消息处理组
@@ -36,7 +43,9 @@ func (p *Pipeline) ProcessConnRead(ctx context.Context) error {
 	return p.callWithBlocking(ctx, actions)
 }
 
+// 主动下发消息，server端等待写协程写出后返回，session关闭导致未写出时返回错误
 func (p *Pipeline) ProcessConnWrite(ctx context.Context) error {
+	ctx = context.WithValue(ctx, waitSentCtxKey{}, true)
 	actions := []delegateFunc{
 		encode(),
 		send(),
@@ -44,31 +53,86 @@ func (p *Pipeline) ProcessConnWrite(ctx context.Context) error {
 	return p.callWithBlocking(ctx, actions)
 }
 
+// 发送阶段是否等待帧写出的context key
+type waitSentCtxKey struct{}
+
+// 上行消息span的context key
+type uplinkSpanCtxKey struct{}
+
 func (p *Pipeline) callWithBlocking(ctx context.Context, funcs []delegateFunc) error {
 	// todo: 重构err定义，通过errors.Cause, 区分breakErr, continueErr
 	curCtx := ctx
+	lastCtx := ctx
 	var err error
 	for _, f := range funcs {
 		curCtx, err = f(curCtx, p)
+		if curCtx != nil {
+			lastCtx = curCtx
+		}
 		if curCtx == nil || err != nil {
 			break
 		}
 	}
+	// 结束本条上行消息的span
+	if span, ok := lastCtx.Value(uplinkSpanCtxKey{}).(trace.Span); ok {
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		tracing.End(span, err, result)
+	}
 	return err
 }
 
+// 记录阶段耗时指标，并创建阶段span，返回的函数用于结束该阶段
+func startStage(ctx context.Context, stage string) (context.Context, func(error)) {
+	start := time.Now()
+	spanCtx, span := tracing.Tracer().Start(ctx, "jt808."+stage)
+	return spanCtx, func(err error) {
+		metrics.ObserveStage(stage, start)
+		tracing.End(span, err, "")
+	}
+}
+
 func recv() delegateFunc {
 	return delegateFunc(func(ctx context.Context, p *Pipeline) (context.Context, error) {
 		framePayload, err := p.fh.Recv(ctx)
-		nxtCtx := context.WithValue(ctx, model.FrameCtxKey{}, framePayload)
-		return nxtCtx, err
+		if err != nil {
+			return ctx, err
+		}
+		// 超出session上行速率限制，丢弃该帧，不用后续处理
+		session, _ := ctx.Value(model.SessionCtxKey{}).(*model.Session)
+		if session != nil {
+			session.RecordRecv(len(framePayload))
+		}
+		if session != nil && !session.AllowFrame() {
+			metrics.RateLimitedFrames.Inc()
+			log.Warn().Str("id", session.ID).Uint64("limited_cnt", session.LimitedFrameCnt()).Msg("Exceed msg rate limit, drop frame")
+			return nil, nil
+		}
+		// 收到完整帧后才开始上行span，不计入等待数据的时间
+		nxtCtx, span := tracing.Tracer().Start(ctx, "jt808.uplink", trace.WithSpanKind(trace.SpanKindServer))
+		if session != nil {
+			span.SetAttributes(tracing.AttrSession.String(session.ID))
+		}
+		nxtCtx = context.WithValue(nxtCtx, uplinkSpanCtxKey{}, span)
+		nxtCtx = context.WithValue(nxtCtx, model.FrameCtxKey{}, framePayload)
+		return nxtCtx, nil
 	})
 }
 
 func decode() delegateFunc {
 	return delegateFunc(func(ctx context.Context, p *Pipeline) (context.Context, error) {
+		_, done := startStage(ctx, "decode")
 		framePayload := ctx.Value(model.FrameCtxKey{}).(FramePayload)
 		packet, err := p.pc.Decode(framePayload)
+		if err != nil {
+			metrics.DecodeFailures.WithLabelValues(decodeFailureReason(err)).Inc()
+		} else {
+			h := packet.Header
+			trace.SpanFromContext(ctx).SetAttributes(tracing.MsgAttrs(h.PhoneNumber, h.MsgID, h.SerialNumber, h.GetVersionDesc().String())...)
+		}
+		done(err)
 		nxtCtx := context.WithValue(ctx, model.PacketDecodeCtxKey{}, packet)
 		return nxtCtx, err
 	})
@@ -80,7 +144,10 @@ func process() delegateFunc {
 		if packet == nil { // 不需要处理
 			return nil, nil
 		}
-		pd, err := p.mp.Process(ctx, packet)
+		spanCtx, done := startStage(ctx, "process")
+		metrics.Messages.WithLabelValues(metrics.DirectionIn, metrics.MsgIDLabel(packet.Header.MsgID)).Inc()
+		pd, err := p.mp.Process(spanCtx, packet)
+		done(err)
 		nxtCtx := context.WithValue(ctx, model.ProcessDataCtxKey{}, pd)
 		return nxtCtx, err
 	})
@@ -92,7 +159,14 @@ func encode() delegateFunc {
 		if pd == nil || pd.Outgoing == nil { // 不需要回复，不用后续处理
 			return nil, nil
 		}
+		spanCtx, done := startStage(ctx, "encode")
+		h := pd.Outgoing.GetHeader()
+		trace.SpanFromContext(spanCtx).SetAttributes(tracing.AttrMsgID.String(metrics.MsgIDLabel(h.MsgID)), tracing.AttrSerial.Int(int(h.SerialNumber)))
 		pkt, err := p.pc.Encode(pd.Outgoing)
+		if err == nil {
+			metrics.Messages.WithLabelValues(metrics.DirectionOut, metrics.MsgIDLabel(h.MsgID)).Inc()
+		}
+		done(err)
 		nxtCtx := context.WithValue(ctx, model.PacketEncodeCtxKey{}, pkt)
 		return nxtCtx, err
 	})
@@ -100,8 +174,34 @@ func encode() delegateFunc {
 
 func send() delegateFunc {
 	return delegateFunc(func(ctx context.Context, p *Pipeline) (context.Context, error) {
+		_, done := startStage(ctx, "send")
 		packet := ctx.Value(model.PacketEncodeCtxKey{}).([]byte)
-		err := p.fh.Send(packet)
+		var err error
+		// server端session由写协程串行发送，避免与其他下发消息交错
+		if session, ok := ctx.Value(model.SessionCtxKey{}).(*model.Session); ok && session.HasWriter() {
+			if wait, _ := ctx.Value(waitSentCtxKey{}).(bool); wait {
+				err = session.WriteWait(ctx, packet)
+			} else {
+				err = session.Write(packet)
+			}
+		} else {
+			err = p.fh.Send(packet)
+		}
+		done(err)
 		return ctx, err
 	})
 }
+
+// 解码失败原因，用于监控指标label
+func decodeFailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrVerifyFailed):
+		return "verify_failed"
+	case errors.Is(err, ErrEmptyPacket):
+		return "empty_packet"
+	case errors.Is(err, model.ErrDecodeHeader):
+		return "decode_header"
+	default:
+		return "other"
+	}
+}
@@ -0,0 +1,43 @@
+package protocol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+func TestDecodeFailureReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "case1: verify failed", err: errors.Wrap(ErrVerifyFailed, "checksum"), want: "verify_failed"},
+		{name: "case2: empty packet", err: ErrEmptyPacket, want: "empty_packet"},
+		{name: "case3: decode header", err: errors.Wrap(model.ErrDecodeHeader, "short"), want: "decode_header"},
+		{name: "case4: other", err: errors.New("unknown"), want: "other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, decodeFailureReason(tt.err))
+		})
+	}
+}
+
+func TestPipeline_DecodeFailureMetric(t *testing.T) {
+	counter := metrics.DecodeFailures.WithLabelValues("verify_failed")
+	before := testutil.ToFloat64(counter)
+	// 校验码错误的帧
+	frame := FramePayload{0x7e, 0x00, 0x02, 0x00, 0x00, 0xff, 0x7e}
+	ctx := context.WithValue(context.Background(), model.FrameCtxKey{}, frame)
+	_, err := decode()(ctx, &Pipeline{pc: NewJT808PacketCodec()})
+	require.ErrorIs(t, err, ErrVerifyFailed)
+	assert.Equal(t, before+1, testutil.ToFloat64(counter))
+}
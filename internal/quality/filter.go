@@ -0,0 +1,176 @@
+// Package quality 位置汇报的GNSS数据质量过滤。
+//
+// 按定位状态、卫星数、与上一个有效点之间的平均速度以及定位时间与服务器时间的偏差判断漂移点、
+// 未定位的零坐标、跳点和时间异常的点。不合格的点被标记原因，由调用方决定标记还是丢弃；
+// 只有合格的点作为后续速度判断的参考点。连续MaxJumps个点都因速度不合格时，
+// 认为参考点本身是漂移点，改用当前点作为参考点。
+package quality
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/hex"
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+// 不合格的原因
+const (
+	ReasonUnfixed    = "unfixed"    // 未定位或经纬度为0
+	ReasonSatellites = "satellites" // 卫星数不足
+	ReasonSpeed      = "speed"      // 与上一个有效点之间的平均速度过高，漂移或跳点
+	ReasonFuture     = "future"     // 定位时间超前服务器时间
+	ReasonStale      = "stale"      // 定位时间落后服务器时间
+)
+
+type Action string
+
+const (
+	ActionFlag Action = "flag" // 标记后仍更新最新位置
+	ActionDrop Action = "drop" // 不更新最新位置，只写入历史轨迹
+)
+
+const MaxJumps = 3
+
+// 设备的参考点
+type reference struct {
+	time  time.Time
+	point geo.Point
+	jumps int // 连续因速度不合格的点数
+}
+
+type filter struct {
+	mutex  sync.Mutex
+	enable bool
+	action Action
+	conf   config.QualityConf
+	refs   map[string]*reference
+	now    func() time.Time // 服务器当前时间，按设备时间
+}
+
+var defaultFilter = newFilter()
+
+func newFilter() *filter {
+	return &filter{
+		action: ActionFlag,
+		refs:   make(map[string]*reference),
+		now:    func() time.Time { return hex.ToDeviceTime(time.Now()) },
+	}
+}
+
+// 按配置设置过滤规则，conf为nil或未开启时不过滤
+func Configure(conf *config.QualityConf) {
+	defaultFilter.configure(conf)
+}
+
+// 是否开启过滤
+func Enabled() bool {
+	defaultFilter.mutex.Lock()
+	defer defaultFilter.mutex.Unlock()
+	return defaultFilter.enable
+}
+
+// 不合格的点的处理方式
+func ActionOf() Action {
+	defaultFilter.mutex.Lock()
+	defer defaultFilter.mutex.Unlock()
+	return defaultFilter.action
+}
+
+// 检查位置汇报，返回不合格的原因，合格或未开启过滤时返回nil。
+// satellites为附加信息0x31上报的卫星数，未上报时为-1
+func Check(dg *model.DeviceGeo, satellites int) []string {
+	return defaultFilter.check(dg, satellites)
+}
+
+// 是否已定位且经纬度不为0。未定位的点不论是否开启过滤都不应参与位置相关的计算
+func IsFixed(dg *model.DeviceGeo) bool {
+	return dg.Geo != nil && dg.Geo.LocationStatus == 1 && dg.Location != nil &&
+		(dg.Location.Latitude != 0 || dg.Location.Longitude != 0)
+}
+
+// 写入历史轨迹时的标记原因，未定位的点不论是否开启过滤都标记为unfixed，默认不导出
+func TrackReasons(dg *model.DeviceGeo) []string {
+	if IsFixed(dg) {
+		return dg.Quality
+	}
+	for _, r := range dg.Quality {
+		if r == ReasonUnfixed {
+			return dg.Quality
+		}
+	}
+	return append(append(make([]string, 0, len(dg.Quality)+1), dg.Quality...), ReasonUnfixed)
+}
+
+// 清除设备的参考点
+func Forget(phone string) {
+	defaultFilter.mutex.Lock()
+	defer defaultFilter.mutex.Unlock()
+	delete(defaultFilter.refs, phone)
+}
+
+func (f *filter) configure(conf *config.QualityConf) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if conf == nil {
+		f.enable = false
+		return
+	}
+	f.enable = conf.Enable
+	f.conf = *conf
+	f.action = ActionFlag
+	if strings.EqualFold(conf.Action, string(ActionDrop)) {
+		f.action = ActionDrop
+	}
+}
+
+func (f *filter) check(dg *model.DeviceGeo, satellites int) []string {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if !f.enable {
+		return nil
+	}
+
+	var reasons []string
+	fixed := IsFixed(dg)
+	if !fixed && f.conf.RejectUnfixed {
+		reasons = append(reasons, ReasonUnfixed)
+	}
+	if f.conf.MinSatellites > 0 && satellites >= 0 && satellites < f.conf.MinSatellites {
+		reasons = append(reasons, ReasonSatellites)
+	}
+	now := f.now()
+	if f.conf.MaxFutureSkew > 0 && dg.Time.Sub(now) > time.Duration(f.conf.MaxFutureSkew)*time.Second {
+		reasons = append(reasons, ReasonFuture)
+	}
+	if f.conf.MaxPastSkew > 0 && now.Sub(dg.Time) > time.Duration(f.conf.MaxPastSkew)*time.Second {
+		reasons = append(reasons, ReasonStale)
+	}
+	// 未定位的点没有可比较的坐标，也不作为参考点
+	if !fixed {
+		return reasons
+	}
+
+	p := geo.Point{Lat: dg.Location.Latitude, Lng: dg.Location.Longitude}
+	ref, ok := f.refs[dg.Phone]
+	if ok && f.conf.MaxSpeed > 0 && len(reasons) == 0 {
+		dt := dg.Time.Sub(ref.time)
+		if dt < 0 {
+			dt = -dt
+		}
+		if dt > 0 && geo.Distance(ref.point, p)/dt.Seconds()*3.6 > f.conf.MaxSpeed {
+			ref.jumps++
+			if ref.jumps < MaxJumps {
+				return []string{ReasonSpeed}
+			}
+		}
+	}
+	// 乱序的点不替换更新的参考点
+	if len(reasons) == 0 && (!ok || ref.jumps >= MaxJumps || !dg.Time.Before(ref.time)) {
+		f.refs[dg.Phone] = &reference{time: dg.Time, point: p}
+	}
+	return reasons
+}
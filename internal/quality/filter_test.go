@@ -0,0 +1,128 @@
+package quality
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+var base = time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+func testFilter(conf *config.QualityConf) *filter {
+	f := newFilter()
+	f.now = func() time.Time { return base.Add(time.Hour) }
+	f.configure(conf)
+	return f
+}
+
+func point(offset time.Duration, lat, lng float64) *model.DeviceGeo {
+	return &model.DeviceGeo{
+		Phone:    "13900000050",
+		Geo:      &model.GeoMeta{LocationStatus: 1},
+		Location: &model.Location{Latitude: lat, Longitude: lng},
+		Time:     base.Add(offset),
+	}
+}
+
+func TestFilter_Check(t *testing.T) {
+	conf := &config.QualityConf{
+		Enable: true, RejectUnfixed: true, MinSatellites: 4, MaxSpeed: 200, MaxFutureSkew: 300, MaxPastSkew: 86400,
+	}
+	unfixed := point(0, 39.9, 116.4)
+	unfixed.Geo.LocationStatus = 0
+
+	tests := []struct {
+		name       string
+		dg         *model.DeviceGeo
+		satellites int
+		want       []string
+	}{
+		{name: "valid", dg: point(0, 39.9, 116.4), satellites: 8, want: nil},
+		{name: "satellites not reported", dg: point(0, 39.9, 116.4), satellites: -1, want: nil},
+		{name: "unfixed", dg: unfixed, satellites: -1, want: []string{ReasonUnfixed}},
+		{name: "zero coordinate", dg: point(0, 0, 0), satellites: -1, want: []string{ReasonUnfixed}},
+		{name: "few satellites", dg: point(0, 39.9, 116.4), satellites: 3, want: []string{ReasonSatellites}},
+		{name: "future", dg: point(time.Hour+10*time.Minute, 39.9, 116.4), satellites: -1, want: []string{ReasonFuture}},
+		{name: "stale", dg: point(-48*time.Hour, 39.9, 116.4), satellites: -1, want: []string{ReasonStale}},
+		{name: "multiple", dg: point(time.Hour+10*time.Minute, 0, 0), satellites: 0, want: []string{ReasonUnfixed, ReasonSatellites, ReasonFuture}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := testFilter(conf)
+			assert.Equal(t, tt.want, f.check(tt.dg, tt.satellites))
+		})
+	}
+
+	f := testFilter(&config.QualityConf{Enable: false, RejectUnfixed: true})
+	assert.Nil(t, f.check(point(0, 0, 0), -1))
+}
+
+func TestFilter_Speed(t *testing.T) {
+	f := testFilter(&config.QualityConf{Enable: true, MaxSpeed: 200})
+
+	// 10秒约111m，40km/h
+	assert.Nil(t, f.check(point(0, 39.9, 116.4), -1))
+	assert.Nil(t, f.check(point(10*time.Second, 39.901, 116.4), -1))
+	// 10秒约11km，跳点
+	assert.Equal(t, []string{ReasonSpeed}, f.check(point(20*time.Second, 40, 116.4), -1))
+	// 参考点仍为上一个有效点
+	assert.Nil(t, f.check(point(30*time.Second, 39.902, 116.4), -1))
+	// 乱序的点与参考点比较
+	assert.Nil(t, f.check(point(25*time.Second, 39.9015, 116.4), -1))
+	assert.Equal(t, base.Add(30*time.Second), f.refs["13900000050"].time)
+}
+
+func TestFilter_SpeedResetReference(t *testing.T) {
+	f := testFilter(&config.QualityConf{Enable: true, MaxSpeed: 200})
+
+	// 第一个点为漂移点，后续点连续MaxJumps次不合格后改用当前点作为参考点
+	assert.Nil(t, f.check(point(0, 41, 116.4), -1))
+	for i := 1; i < MaxJumps; i++ {
+		assert.Equal(t, []string{ReasonSpeed}, f.check(point(time.Duration(i)*10*time.Second, 39.9, 116.4), -1))
+	}
+	assert.Nil(t, f.check(point(MaxJumps*10*time.Second, 39.9, 116.4), -1))
+	assert.Nil(t, f.check(point((MaxJumps+1)*10*time.Second, 39.901, 116.4), -1))
+}
+
+func TestFilter_Configure(t *testing.T) {
+	f := testFilter(&config.QualityConf{Enable: true, Action: "DROP"})
+	assert.Equal(t, ActionDrop, f.action)
+	f.configure(&config.QualityConf{Enable: true})
+	assert.Equal(t, ActionFlag, f.action)
+	f.configure(nil)
+	assert.False(t, f.enable)
+}
+
+func TestTrackReasons(t *testing.T) {
+	unfixed := point(0, 39.9, 116.4)
+	unfixed.Geo.LocationStatus = 0
+	flagged := point(0, 0, 0)
+	flagged.Quality = []string{ReasonUnfixed, ReasonStale}
+	stale := point(0, 0, 0)
+	stale.Quality = []string{ReasonStale}
+
+	tests := []struct {
+		name      string
+		dg        *model.DeviceGeo
+		wantFixed bool
+		want      []string
+	}{
+		{name: "fixed", dg: point(0, 39.9, 116.4), wantFixed: true},
+		{name: "unfixed", dg: unfixed, want: []string{ReasonUnfixed}},
+		{name: "zero coordinate", dg: point(0, 0, 0), want: []string{ReasonUnfixed}},
+		{name: "already flagged", dg: flagged, want: []string{ReasonUnfixed, ReasonStale}},
+		{name: "other reason", dg: stale, want: []string{ReasonStale, ReasonUnfixed}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantFixed, IsFixed(tt.dg))
+			assert.Equal(t, tt.want, TrackReasons(tt.dg))
+		})
+	}
+	// 不修改位置汇报的原因
+	assert.Equal(t, []string{ReasonStale}, stale.Quality)
+}
@@ -1,7 +1,10 @@
 package server
 
+import "crypto/tls"
+
 type Server interface {
 	Listen(addr string) error
+	ListenTLS(addr string, tlsConfig *tls.Config) error
 	Start()
 	Stop()
 }
@@ -2,92 +2,366 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
 	"io"
 	"net"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
 
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
 	"github.com/fakeyanss/jt808-server-go/internal/protocol"
 	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
 	"github.com/fakeyanss/jt808-server-go/internal/storage"
+	"github.com/fakeyanss/jt808-server-go/internal/tracing"
+	"github.com/fakeyanss/jt808-server-go/pkg/proxyproto"
 	"github.com/fakeyanss/jt808-server-go/pkg/routines"
 )
 
+var (
+	ErrMaxConnReached      = errors.New("Max connections reached")
+	ErrMaxConnPerIPReached = errors.New("Max connections per ip reached")
+	ErrNoListener          = errors.New("No listener")
+	ErrNotAccepting        = errors.New("Listener not accepting")
+	ErrNoAnswer            = errors.New("No answer from device")
+)
+
+const defaultTLSHandshakeTimeout = 10 * time.Second
+
 type TCPServer struct {
-	listener net.Listener
+	listeners     []*tcpListener            // 明文和TLS监听，分别使用不同端口
+	writerOpts    *model.WriterOptions      // session下行写协程配置
+	limit         *config.LimitConf         // 连接限制配置
+	identityField string                    // 终端证书映射为设备手机号的字段
+	proxyProto    *config.ProxyProtocolConf // PROXY协议配置
+	trustedNets   []*net.IPNet              // 可信的负载均衡网段
+
+	connCnt      int            // 当前连接数
+	connCntByIP  map[string]int // 各来源IP的当前连接数
+	rejectedCnt  atomic.Uint64  // 因超出最大连接数被拒绝的连接数
+	rejectedByIP atomic.Uint64  // 因超出单IP最大连接数被拒绝的连接数
+	accepting    atomic.Int32   // 正在accept的监听数
 
 	// sessions map[string]*model.Session
 	mutex *sync.Mutex
 }
 
-func NewTCPServer() *TCPServer {
+// 监听端口，tlsConfig不为nil时在PROXY头之后进行TLS握手
+type tcpListener struct {
+	net.Listener
+	tlsConfig *tls.Config
+}
+
+// tcp server连接统计
+type ConnStats struct {
+	ConnCnt         int    `json:"connCnt"`
+	RejectedCnt     uint64 `json:"rejectedCnt"`
+	RejectedByIPCnt uint64 `json:"rejectedByIpCnt"`
+}
+
+func NewTCPServer(cfg *config.Config) *TCPServer {
+	limit := cfg.Server.Limit
+	if limit == nil {
+		limit = &config.LimitConf{}
+	}
+	var identityField string
+	if cfg.Server.TLS != nil {
+		identityField = cfg.Server.TLS.IdentityField
+	}
+	proxyProto := cfg.Server.ProxyProtocol
+	if proxyProto == nil {
+		proxyProto = &config.ProxyProtocolConf{}
+	}
+	if proxyProto.Enable && len(proxyProto.TrustedProxies) == 0 {
+		log.Warn().Msg("PROXY protocol is enabled without trusted proxies, no PROXY header will be accepted")
+	}
 	return &TCPServer{
-		mutex: &sync.Mutex{},
+		writerOpts:    parseWriterOptions(cfg.Server.Session),
+		limit:         limit,
+		identityField: identityField,
+		proxyProto:    proxyProto,
+		trustedNets:   parseTrustedNets(proxyProto.TrustedProxies),
+		connCntByIP:   make(map[string]int),
+		mutex:         &sync.Mutex{},
 		// sessions: make(map[string]*model.Session),
 	}
 }
 
+// 解析可信代理列表，支持CIDR和单个IP
+func parseTrustedNets(proxies []string) []*net.IPNet {
+	var nets []*net.IPNet
+	for _, p := range proxies {
+		if !strings.Contains(p, "/") {
+			if ip := net.ParseIP(p); ip != nil && ip.To4() != nil {
+				p += "/32"
+			} else {
+				p += "/128"
+			}
+		}
+		_, ipNet, err := net.ParseCIDR(p)
+		if err != nil {
+			log.Error().Err(err).Str("proxy", p).Msg("Fail to parse trusted proxy, skip it")
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets
+}
+
+func parseWriterOptions(c *config.SessionConf) *model.WriterOptions {
+	opts := model.DefaultWriterOptions()
+	if c == nil {
+		return opts
+	}
+	if c.OutboundQueueSize > 0 {
+		opts.QueueSize = c.OutboundQueueSize
+	}
+	if c.WriteTimeout > 0 {
+		opts.WriteTimeout = c.WriteTimeout
+	}
+	if c.EnqueueTimeout > 0 {
+		opts.EnqueueTimeout = c.EnqueueTimeout
+	}
+	switch model.OverflowPolicy(c.OverflowPolicy) {
+	case model.OverflowDrop, model.OverflowClose:
+		opts.OverflowPolicy = model.OverflowPolicy(c.OverflowPolicy)
+	default:
+		opts.OverflowPolicy = model.OverflowBlock
+	}
+	return opts
+}
+
 func (serv *TCPServer) Listen(addr string) error {
 	l, err := net.Listen("tcp", addr)
 	if err == nil {
-		serv.listener = l
+		serv.listeners = append(serv.listeners, &tcpListener{Listener: l})
 		log.Debug().Msgf("Listening on %v", addr)
 	}
 
 	return err
 }
 
+// 监听TLS端口，可与Listen的明文端口同时使用
+func (serv *TCPServer) ListenTLS(addr string, tlsConfig *tls.Config) error {
+	l, err := net.Listen("tcp", addr)
+	if err == nil {
+		serv.listeners = append(serv.listeners, &tcpListener{Listener: l, tlsConfig: tlsConfig})
+		log.Debug().Msgf("Listening tls on %v", addr)
+	}
+
+	return err
+}
+
 func (serv *TCPServer) Start() {
+	var wg sync.WaitGroup
+	for _, l := range serv.listeners {
+		l := l
+		wg.Add(1)
+		routines.GoSafe(func() {
+			defer wg.Done()
+			serv.acceptLoop(l)
+		})
+	}
+	wg.Wait()
+}
+
+func (serv *TCPServer) acceptLoop(listener *tcpListener) {
+	serv.accepting.Add(1)
+	defer serv.accepting.Add(-1)
 	for {
-		conn, err := serv.listener.Accept()
+		conn, err := listener.Accept()
 		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return // listener closed by Stop
+			}
 			log.Error().Err(err).Msg("Fail to do listener accept")
-		} else {
-			session := serv.accept(conn)
-			routines.GoSafe(func() { serv.serve(session) })
+			continue
+		}
+		routines.GoSafe(func() { serv.handle(conn, listener.tlsConfig) })
+	}
+}
+
+// 处理新连接: 解析PROXY头 -> 连接数限制 -> 处理session消息。
+// PROXY头需在TLS握手之前读取，所以TLS在此处而不是在listener上封装
+func (serv *TCPServer) handle(conn net.Conn, tlsConfig *tls.Config) {
+	conn, err := serv.unwrapProxy(conn)
+	if err != nil {
+		log.Warn().Err(err).Str("id", conn.RemoteAddr().String()).Msg("Fail to read proxy protocol header")
+		conn.Close()
+		return
+	}
+	if tlsConfig != nil {
+		conn = tls.Server(conn, tlsConfig)
+	}
+
+	session, err := serv.accept(conn)
+	if err != nil {
+		log.Warn().Err(err).Str("id", conn.RemoteAddr().String()).
+			Uint64("rejected_cnt", serv.rejectedCnt.Load()).Uint64("rejected_by_ip_cnt", serv.rejectedByIP.Load()).
+			Msg("Reject connection")
+		conn.Close()
+		return
+	}
+	serv.serve(session)
+}
+
+// 来自可信代理的连接，读取PROXY头并以其中的源地址作为conn的RemoteAddr
+func (serv *TCPServer) unwrapProxy(conn net.Conn) (net.Conn, error) {
+	if !serv.proxyProto.Enable || !serv.isTrustedProxy(conn.RemoteAddr()) {
+		return conn, nil
+	}
+	pc, err := proxyproto.Wrap(conn, serv.proxyProto.HeaderTimeout)
+	if err != nil {
+		return conn, err
+	}
+	if h := pc.Header(); h != nil && !h.Local {
+		log.Debug().Str("proxy", conn.RemoteAddr().String()).Str("id", pc.RemoteAddr().String()).Msg("Read proxy protocol header")
+	}
+	return pc, nil
+}
+
+// 可信代理列表为空时不信任任何来源，避免任意客户端伪造源地址
+func (serv *TCPServer) isTrustedProxy(addr net.Addr) bool {
+	ip := net.ParseIP(hostOf(addr.String()))
+	for _, n := range serv.trustedNets {
+		if ip != nil && n.Contains(ip) {
+			return true
 		}
 	}
+	return false
 }
 
 func (serv *TCPServer) Stop() {
-	serv.listener.Close()
+	for _, l := range serv.listeners {
+		l.Close()
+	}
+}
+
+// 所有监听都在accept时返回nil，用于就绪检查
+func (serv *TCPServer) Ready() error {
+	if len(serv.listeners) == 0 {
+		return ErrNoListener
+	}
+	if n := int(serv.accepting.Load()); n < len(serv.listeners) {
+		return errors.Wrapf(ErrNotAccepting, "accepting=%d, listeners=%d", n, len(serv.listeners))
+	}
+	return nil
+}
+
+// 强制断开session
+func (serv *TCPServer) Kick(id string) error {
+	session, err := storage.GetSession(id)
+	if err != nil {
+		return err
+	}
+	log.Info().Str("id", id).Msg("Kick session")
+	serv.remove(session)
+	return nil
+}
+
+// 连接统计
+func (serv *TCPServer) Stats() *ConnStats {
+	serv.mutex.Lock()
+	defer serv.mutex.Unlock()
+	return &ConnStats{
+		ConnCnt:         serv.connCnt,
+		RejectedCnt:     serv.rejectedCnt.Load(),
+		RejectedByIPCnt: serv.rejectedByIP.Load(),
+	}
 }
 
-// 将conn封装为逻辑session
-func (serv *TCPServer) accept(conn net.Conn) *model.Session {
+// 将conn封装为逻辑session。超出连接数限制时返回error，由调用方关闭conn
+func (serv *TCPServer) accept(conn net.Conn) (*model.Session, error) {
 	remoteAddr := conn.RemoteAddr().String()
+	ip := hostOf(remoteAddr)
+
 	serv.mutex.Lock()
+	defer serv.mutex.Unlock()
+
+	if serv.limit.MaxConnections > 0 && serv.connCnt >= serv.limit.MaxConnections {
+		serv.rejectedCnt.Add(1)
+		metrics.RejectedConnections.WithLabelValues("max_connections").Inc()
+		return nil, ErrMaxConnReached
+	}
+	if serv.limit.MaxConnectionsPerIP > 0 && serv.connCntByIP[ip] >= serv.limit.MaxConnectionsPerIP {
+		serv.rejectedByIP.Add(1)
+		metrics.RejectedConnections.WithLabelValues("max_connections_per_ip").Inc()
+		return nil, errors.Wrapf(ErrMaxConnPerIPReached, "ip=%s", ip)
+	}
+	serv.connCnt++
+	serv.connCntByIP[ip]++
+	metrics.Connections.Inc()
+
 	session := &model.Session{
-		Conn: conn,
-		ID:   remoteAddr, // using remote addr default
+		Conn:      conn,
+		ID:        remoteAddr, // using remote addr default
+		CreatedAt: time.Now(),
 	}
+	session.SetRateLimit(serv.limit.MsgRateLimit, serv.limit.MsgRateBurst)
+	session.StartWriter(serv.writerOpts)
 	// serv.sessions[remoteAddr] = session
 	storage.StoreSession(session)
-	serv.mutex.Unlock()
 
-	return session
+	// 未在期限内完成注册鉴权，断开连接
+	if timeout := serv.limit.HandshakeTimeout; timeout > 0 {
+		session.CloseIfUnauthenticated(timeout)
+	}
+
+	return session, nil
 }
 
 func (serv *TCPServer) remove(session *model.Session) {
 	serv.mutex.Lock()
 	defer serv.mutex.Unlock()
 
-	session.Conn.Close()
+	session.Close()
+	if _, err := storage.GetSession(session.ID); err == nil {
+		ip := hostOf(session.ID)
+		serv.connCnt--
+		serv.connCntByIP[ip]--
+		metrics.Connections.Dec()
+		if serv.connCntByIP[ip] <= 0 {
+			delete(serv.connCntByIP, ip)
+		}
+	}
 	storage.ClearSession(session.ID)
 	// delete(serv.sessions, session.ID)
 
 	log.Debug().Str("id", session.ID).Msg("Closing connection from remote.")
 }
 
+// 从ip:port中解析出ip，无法解析时返回原地址
+func hostOf(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
 // 处理每个session的消息
 func (serv *TCPServer) serve(session *model.Session) {
 	defer serv.remove(session)
 
+	if tlsConn, ok := session.Conn.(*tls.Conn); ok {
+		if err := serv.handshake(session, tlsConn); err != nil {
+			log.Warn().Err(err).Str("id", session.ID).Msg("Fail to do tls handshake")
+			return
+		}
+	}
+
 	pg := protocol.NewPipeline(session.Conn)
 	for {
+		if idle := serv.limit.ReadIdleTimeout; idle > 0 {
+			_ = session.Conn.SetReadDeadline(time.Now().Add(idle))
+		}
+
 		// 记录value ctx
 		ctx := context.WithValue(context.Background(), model.SessionCtxKey{}, session)
 
@@ -97,10 +371,17 @@ func (serv *TCPServer) serve(session *model.Session) {
 			continue
 		}
 
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			log.Warn().Str("id", session.ID).Dur("timeout", serv.limit.ReadIdleTimeout).Msg("Read idle timeout, close connection")
+			return
+		}
+
 		log.Error().Err(err).Str("id", session.ID).Msg("Failed to serve session")
 
 		switch {
-		case errors.Is(err, io.EOF), errors.Is(err, io.ErrClosedPipe), errors.Is(err, net.ErrClosed), errors.Is(err, storage.ErrDeviceNotFound):
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrClosedPipe), errors.Is(err, net.ErrClosed), errors.Is(err, storage.ErrDeviceNotFound),
+			errors.Is(err, model.ErrSessionWriterDone):
 			return // close connection when EOF or closed
 		default:
 			time.Sleep(1 * time.Second)
@@ -108,29 +389,101 @@ func (serv *TCPServer) serve(session *model.Session) {
 	}
 }
 
-// 发送消息到终端设备, 外部调用
-func (serv *TCPServer) Send(id string, msg model.JT808Msg) {
+// 完成TLS握手，并从终端证书中解析设备标识
+func (serv *TCPServer) handshake(session *model.Session, conn *tls.Conn) error {
+	timeout := serv.limit.HandshakeTimeout
+	if timeout <= 0 {
+		timeout = defaultTLSHandshakeTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := conn.HandshakeContext(ctx); err != nil {
+		return err
+	}
+	state := conn.ConnectionState()
+	session.Identity = peerIdentity(&state, serv.identityField)
+	if session.Identity != "" {
+		log.Debug().Str("id", session.ID).Str("identity", session.Identity).Msg("Tls client certificate verified")
+	}
+	return nil
+}
+
+// 发送消息到终端设备, 外部调用。
+//
+// 消息经session的下行队列串行写出，与serve中的回复消息不会交错。
+func (serv *TCPServer) Send(id string, msg model.JT808Msg) error {
+	return serv.SendContext(context.Background(), id, msg)
+}
+
+// 同Send，ctx用于延续调用方的trace
+func (serv *TCPServer) SendContext(ctx context.Context, id string, msg model.JT808Msg) (err error) {
+	h := msg.GetHeader()
+	msgID := metrics.MsgIDLabel(h.MsgID)
+	ctx, span := tracing.Tracer().Start(ctx, "jt808.downlink", trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(tracing.MsgAttrs(h.PhoneNumber, h.MsgID, h.SerialNumber, h.GetVersionDesc().String())...),
+		trace.WithAttributes(tracing.AttrSession.String(id)))
+	defer func() {
+		result := downlinkResult(err)
+		metrics.Downlinks.WithLabelValues(msgID, result).Inc()
+		tracing.End(span, err, result)
+	}()
+
 	// session := serv.sessions[id]
 	session, err := storage.GetSession(id)
-	if err != nil && errors.Is(err, storage.ErrSessionClosed) {
+	if err != nil {
 		log.Warn().Str("id", id).Msg("Fail to get session from cache, maybe conn was closed.")
-		return
+		return err
 	}
 
 	pg := protocol.NewPipeline(session.Conn)
 
 	// 记录value ctx
-	ctx := context.WithValue(context.Background(), model.ProcessDataCtxKey{}, &model.ProcessData{Outgoing: msg})
+	ctx = context.WithValue(ctx, model.SessionCtxKey{}, session)
+	ctx = context.WithValue(ctx, model.ProcessDataCtxKey{}, &model.ProcessData{Outgoing: msg})
 
 	err = pg.ProcessConnWrite(ctx)
 
 	if err == nil {
-		return
+		return nil
 	}
 
-	if errors.Is(err, io.ErrClosedPipe) || errors.Is(err, net.ErrClosed) {
+	if errors.Is(err, io.ErrClosedPipe) || errors.Is(err, net.ErrClosed) || errors.Is(err, model.ErrSessionWriterDone) {
 		serv.remove(session)
 	}
 
 	log.Error().Err(err).Str("device", id).Msg("Failed to send jtmsg to device")
+	return err
+}
+
+// 下发消息并等待终端应答(0x0001或专用应答)，ctx结束前未收到应答返回ErrNoAnswer
+func (serv *TCPServer) Request(ctx context.Context, id string, msg model.JT808Msg) (model.JT808Msg, error) {
+	h := msg.GetHeader()
+	answerCh, cancel := protocol.WaitAnswer(h.PhoneNumber, h.MsgID, h.SerialNumber)
+	defer cancel()
+
+	if err := serv.SendContext(ctx, id, msg); err != nil {
+		return nil, err
+	}
+
+	select {
+	case answer := <-answerCh:
+		return answer, nil
+	case <-ctx.Done():
+		return nil, errors.Wrapf(ErrNoAnswer, "phone=%s, serialNumber=%d, %v", h.PhoneNumber, h.SerialNumber, ctx.Err())
+	}
+}
+
+// 下发结果，用于监控指标label
+func downlinkResult(err error) string {
+	switch {
+	case err == nil:
+		return metrics.DownlinkSuccess
+	case errors.Is(err, model.ErrOutboundQueueFull):
+		return metrics.DownlinkQueueFull
+	case errors.Is(err, storage.ErrSessionClosed), errors.Is(err, model.ErrSessionWriterDone), errors.Is(err, net.ErrClosed),
+		errors.Is(err, io.ErrClosedPipe):
+		return metrics.DownlinkClosed
+	default:
+		return metrics.DownlinkError
+	}
 }
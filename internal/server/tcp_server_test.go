@@ -4,14 +4,19 @@ import (
 	"net"
 	"sync"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
 	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
 )
 
 func TestTCPServer_serve(t *testing.T) {
 	type fields struct {
-		listener net.Listener
-		mutex    *sync.Mutex
+		listeners []*tcpListener
+		mutex     *sync.Mutex
 	}
 	type args struct {
 		session *model.Session
@@ -26,10 +31,171 @@ func TestTCPServer_serve(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			serv := &TCPServer{
-				listener: tt.fields.listener,
-				mutex:    tt.fields.mutex,
+				listeners: tt.fields.listeners,
+				mutex:     tt.fields.mutex,
 			}
 			serv.serve(tt.args.session)
 		})
 	}
 }
+
+type addrConn struct {
+	net.Conn
+	addr net.Addr
+}
+
+func (c *addrConn) RemoteAddr() net.Addr {
+	return c.addr
+}
+
+func newAddrConn(t *testing.T, addr string) net.Conn {
+	tcpAddr, err := net.ResolveTCPAddr("tcp", addr)
+	require.NoError(t, err)
+	c1, c2 := net.Pipe()
+	t.Cleanup(func() {
+		c1.Close()
+		c2.Close()
+	})
+	return &addrConn{Conn: c1, addr: tcpAddr}
+}
+
+func TestTCPServer_accept(t *testing.T) {
+	tests := []struct {
+		name    string
+		limit   *config.LimitConf
+		addrs   []string
+		wantErr []error
+	}{
+		{
+			name:    "no limit",
+			limit:   &config.LimitConf{},
+			addrs:   []string{"10.0.0.1:1001", "10.0.0.1:1002", "10.0.0.2:1001"},
+			wantErr: []error{nil, nil, nil},
+		},
+		{
+			name:    "max connections",
+			limit:   &config.LimitConf{MaxConnections: 2},
+			addrs:   []string{"10.0.1.1:1001", "10.0.1.2:1001", "10.0.1.3:1001"},
+			wantErr: []error{nil, nil, ErrMaxConnReached},
+		},
+		{
+			name:    "max connections per ip",
+			limit:   &config.LimitConf{MaxConnectionsPerIP: 1},
+			addrs:   []string{"10.0.2.1:1001", "10.0.2.1:1002", "10.0.2.2:1001"},
+			wantErr: []error{nil, ErrMaxConnPerIPReached, nil},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serv := &TCPServer{
+				writerOpts:  model.DefaultWriterOptions(),
+				limit:       tt.limit,
+				connCntByIP: make(map[string]int),
+				mutex:       &sync.Mutex{},
+			}
+			var sessions []*model.Session
+			for i, addr := range tt.addrs {
+				session, err := serv.accept(newAddrConn(t, addr))
+				if tt.wantErr[i] != nil {
+					assert.ErrorIs(t, err, tt.wantErr[i])
+					continue
+				}
+				require.NoError(t, err)
+				sessions = append(sessions, session)
+			}
+			assert.Equal(t, len(sessions), serv.Stats().ConnCnt)
+
+			for _, session := range sessions {
+				serv.remove(session)
+			}
+			assert.Equal(t, 0, serv.Stats().ConnCnt)
+			assert.Empty(t, serv.connCntByIP)
+		})
+	}
+}
+
+func TestTCPServer_unwrapProxy(t *testing.T) {
+	tests := []struct {
+		name       string
+		proxyProto *config.ProxyProtocolConf
+		peerAddr   string
+		wantAddr   string
+	}{
+		{
+			name:       "disabled",
+			proxyProto: &config.ProxyProtocolConf{},
+			peerAddr:   "10.1.0.1:40000",
+			wantAddr:   "10.1.0.1:40000",
+		},
+		{
+			name:       "no trusted proxies",
+			proxyProto: &config.ProxyProtocolConf{Enable: true, HeaderTimeout: time.Second},
+			peerAddr:   "10.1.0.1:40000",
+			wantAddr:   "10.1.0.1:40000",
+		},
+		{
+			name:       "trusted proxy",
+			proxyProto: &config.ProxyProtocolConf{Enable: true, TrustedProxies: []string{"10.1.0.0/16"}},
+			peerAddr:   "10.1.0.1:40000",
+			wantAddr:   "192.168.0.1:56324",
+		},
+		{
+			name:       "untrusted proxy",
+			proxyProto: &config.ProxyProtocolConf{Enable: true, TrustedProxies: []string{"10.2.0.1"}},
+			peerAddr:   "10.1.0.1:40000",
+			wantAddr:   "10.1.0.1:40000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			serv := &TCPServer{
+				proxyProto:  tt.proxyProto,
+				trustedNets: parseTrustedNets(tt.proxyProto.TrustedProxies),
+			}
+			tcpAddr, err := net.ResolveTCPAddr("tcp", tt.peerAddr)
+			require.NoError(t, err)
+			c1, c2 := net.Pipe()
+			defer c1.Close()
+			defer c2.Close()
+			go func() { _, _ = c2.Write([]byte("PROXY TCP4 192.168.0.1 10.0.0.1 56324 8080\r\n")) }()
+
+			conn, err := serv.unwrapProxy(&addrConn{Conn: c1, addr: tcpAddr})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantAddr, conn.RemoteAddr().String())
+		})
+	}
+}
+
+func TestTCPServer_Ready(t *testing.T) {
+	serv := &TCPServer{}
+	assert.ErrorIs(t, serv.Ready(), ErrNoListener)
+
+	require.NoError(t, serv.Listen("127.0.0.1:0"))
+	assert.ErrorIs(t, serv.Ready(), ErrNotAccepting)
+
+	go serv.Start()
+	assert.Eventually(t, func() bool { return serv.Ready() == nil }, time.Second, 10*time.Millisecond)
+
+	serv.Stop()
+	assert.Eventually(t, func() bool { return serv.Ready() != nil }, time.Second, 10*time.Millisecond)
+}
+
+func TestTCPServer_Kick(t *testing.T) {
+	serv := &TCPServer{
+		writerOpts:  model.DefaultWriterOptions(),
+		limit:       &config.LimitConf{},
+		connCntByIP: make(map[string]int),
+		mutex:       &sync.Mutex{},
+	}
+	session, err := serv.accept(newAddrConn(t, "10.0.3.1:1001"))
+	require.NoError(t, err)
+
+	require.NoError(t, serv.Kick(session.ID))
+	assert.Equal(t, 0, serv.Stats().ConnCnt)
+	assert.Error(t, serv.Kick(session.ID))
+	select {
+	case <-session.Done():
+	default:
+		t.Fatal("session not closed")
+	}
+}
@@ -0,0 +1,154 @@
+package server
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/pkg/routines"
+)
+
+var ErrNoClientCA = errors.New("Fail to parse any client ca certificate")
+
+const (
+	IdentityFieldCN  = "cn"
+	IdentityFieldDNS = "dns"
+)
+
+// 证书热加载。定时检查证书文件的修改时间，有变化时重新加载，新的握手即使用新证书
+type CertReloader struct {
+	conf *config.TLSConf
+
+	cert    atomic.Pointer[tls.Certificate]
+	caPool  atomic.Pointer[x509.CertPool]
+	modTime time.Time
+}
+
+func NewCertReloader(conf *config.TLSConf) (*CertReloader, error) {
+	r := &CertReloader{conf: conf}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	if conf.ReloadInterval > 0 {
+		routines.GoSafe(r.watch)
+	}
+	return r, nil
+}
+
+func (r *CertReloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return r.cert.Load(), nil
+}
+
+// 终端连接使用的tls配置。配置了clientCaFile时校验终端证书
+func (r *CertReloader) TerminalTLSConfig() *tls.Config {
+	clientAuth := tls.NoClientCert
+	if r.conf.ClientCAFile != "" {
+		clientAuth = tls.VerifyClientCertIfGiven
+		if r.conf.RequireClientCert {
+			clientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return r.tlsConfig(clientAuth)
+}
+
+// HTTP API使用的tls配置
+func (r *CertReloader) APITLSConfig() *tls.Config {
+	clientAuth := tls.NoClientCert
+	if r.conf.ClientCAFile != "" && r.conf.APIClientAuth {
+		clientAuth = tls.RequireAndVerifyClientCert
+	}
+	return r.tlsConfig(clientAuth)
+}
+
+func (r *CertReloader) tlsConfig(clientAuth tls.ClientAuthType) *tls.Config {
+	base := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: r.GetCertificate,
+		ClientAuth:     clientAuth,
+	}
+	// 每次握手取最新的CA，以便CA文件也能热加载
+	base.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		c := base.Clone()
+		c.GetConfigForClient = nil
+		c.ClientCAs = r.caPool.Load()
+		return c, nil
+	}
+	return base
+}
+
+func (r *CertReloader) reload() error {
+	cert, err := tls.LoadX509KeyPair(r.conf.CertFile, r.conf.KeyFile)
+	if err != nil {
+		return errors.Wrap(err, "Fail to load server certificate")
+	}
+	r.cert.Store(&cert)
+
+	if r.conf.ClientCAFile != "" {
+		caBytes, err := os.ReadFile(r.conf.ClientCAFile)
+		if err != nil {
+			return errors.Wrap(err, "Fail to read client ca file")
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBytes) {
+			return ErrNoClientCA
+		}
+		r.caPool.Store(pool)
+	}
+
+	r.modTime = r.latestModTime()
+	return nil
+}
+
+func (r *CertReloader) watch() {
+	ticker := time.NewTicker(r.conf.ReloadInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		if !r.latestModTime().After(r.modTime) {
+			continue
+		}
+		if err := r.reload(); err != nil {
+			// 加载失败时继续使用旧证书
+			log.Error().Err(err).Str("cert", r.conf.CertFile).Msg("Fail to reload tls certificate")
+			continue
+		}
+		log.Info().Str("cert", r.conf.CertFile).Msg("Reloaded tls certificate")
+	}
+}
+
+func (r *CertReloader) latestModTime() time.Time {
+	var latest time.Time
+	for _, f := range []string{r.conf.CertFile, r.conf.KeyFile, r.conf.ClientCAFile} {
+		if f == "" {
+			continue
+		}
+		info, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		if info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// 从终端的客户端证书中解析设备标识(手机号)，未提供证书时返回空
+func peerIdentity(state *tls.ConnectionState, field string) string {
+	if len(state.PeerCertificates) == 0 {
+		return ""
+	}
+	leaf := state.PeerCertificates[0]
+	if field == IdentityFieldDNS {
+		if len(leaf.DNSNames) > 0 {
+			return leaf.DNSNames[0]
+		}
+		return ""
+	}
+	return leaf.Subject.CommonName
+}
@@ -0,0 +1,127 @@
+package server
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+)
+
+type testCert struct {
+	cert    *x509.Certificate
+	key     *ecdsa.PrivateKey
+	certPEM []byte
+	keyPEM  []byte
+}
+
+func genTestCert(t *testing.T, cn string, parent *testCert) *testCert {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{cn},
+	}
+	signer, signerKey := tmpl, key
+	if parent == nil {
+		tmpl.IsCA = true
+		tmpl.BasicConstraintsValid = true
+	} else {
+		signer, signerKey = parent.cert, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return &testCert{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func writeTLSConf(t *testing.T, ca, serv *testCert) *config.TLSConf {
+	dir := t.TempDir()
+	conf := &config.TLSConf{
+		Enable:            true,
+		CertFile:          filepath.Join(dir, "server.crt"),
+		KeyFile:           filepath.Join(dir, "server.key"),
+		ClientCAFile:      filepath.Join(dir, "ca.crt"),
+		RequireClientCert: true,
+		IdentityField:     IdentityFieldCN,
+	}
+	require.NoError(t, os.WriteFile(conf.CertFile, serv.certPEM, 0600))
+	require.NoError(t, os.WriteFile(conf.KeyFile, serv.keyPEM, 0600))
+	require.NoError(t, os.WriteFile(conf.ClientCAFile, ca.certPEM, 0600))
+	return conf
+}
+
+func TestCertReloader_TerminalIdentity(t *testing.T) {
+	ca := genTestCert(t, "test-ca", nil)
+	servCert := genTestCert(t, "localhost", ca)
+	cliCert := genTestCert(t, "13800000000", ca)
+
+	reloader, err := NewCertReloader(writeTLSConf(t, ca, servCert))
+	require.NoError(t, err)
+
+	servConn, cliConn := net.Pipe()
+	defer servConn.Close()
+	defer cliConn.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.cert)
+	cli := tls.Client(cliConn, &tls.Config{
+		ServerName: "localhost",
+		RootCAs:    roots,
+		Certificates: []tls.Certificate{{
+			Certificate: [][]byte{cliCert.cert.Raw},
+			PrivateKey:  cliCert.key,
+		}},
+	})
+	go func() { _ = cli.Handshake() }()
+
+	serv := tls.Server(servConn, reloader.TerminalTLSConfig())
+	require.NoError(t, serv.Handshake())
+	state := serv.ConnectionState()
+	assert.Equal(t, "13800000000", peerIdentity(&state, IdentityFieldCN))
+	assert.Equal(t, "13800000000", peerIdentity(&state, IdentityFieldDNS))
+}
+
+func TestCertReloader_reload(t *testing.T) {
+	ca := genTestCert(t, "test-ca", nil)
+	conf := writeTLSConf(t, ca, genTestCert(t, "old", ca))
+
+	reloader, err := NewCertReloader(conf)
+	require.NoError(t, err)
+	old, _ := reloader.GetCertificate(nil)
+
+	newCert := genTestCert(t, "new", ca)
+	require.NoError(t, os.WriteFile(conf.CertFile, newCert.certPEM, 0600))
+	require.NoError(t, os.WriteFile(conf.KeyFile, newCert.keyPEM, 0600))
+	require.NoError(t, reloader.reload())
+
+	cur, _ := reloader.GetCertificate(nil)
+	assert.NotEqual(t, old.Certificate[0], cur.Certificate[0])
+	assert.Equal(t, newCert.cert.Raw, cur.Certificate[0])
+}
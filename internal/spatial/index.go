@@ -0,0 +1,255 @@
+// Package spatial 维护各设备最新位置的空间索引，用于查询附近车辆和矩形范围内的车辆。
+//
+// 索引按CellSize度划分经纬度网格，查询时只扫描与查询范围相交的网格；
+// 相交网格数多于非空网格数时直接遍历全部设备。坐标为WGS-84，
+// 跨越180度经线的查询范围拆分为两段经度范围分别扫描。
+package spatial
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+const CellSize = 0.05 // 网格边长，度，纬度方向约5.5km
+
+type cell struct {
+	lat, lng int32
+}
+
+func cellOf(p geo.Point) cell {
+	return cell{lat: int32(math.Floor(p.Lat / CellSize)), lng: int32(math.Floor(p.Lng / CellSize))}
+}
+
+type entry struct {
+	point geo.Point
+	cell  cell
+	dg    *model.DeviceGeo
+}
+
+// 查询结果，Distance为到查询中心的距离，米，矩形查询时为0
+type Result struct {
+	Geo      *model.DeviceGeo
+	Distance float64
+}
+
+type Index struct {
+	mutex   sync.RWMutex
+	entries map[string]*entry
+	cells   map[cell]map[string]*entry
+}
+
+func NewIndex() *Index {
+	return &Index{
+		entries: make(map[string]*entry),
+		cells:   make(map[cell]map[string]*entry),
+	}
+}
+
+var defaultIndex = NewIndex()
+
+// 用位置汇报更新默认索引
+func Update(dg *model.DeviceGeo) {
+	defaultIndex.Update(dg)
+}
+
+// 从默认索引中删除设备
+func Forget(phone string) {
+	defaultIndex.Remove(phone)
+}
+
+// 在默认索引中查询圆形范围内的设备
+func Nearby(center geo.Point, radius float64, limit int, filter func(phone string) bool) []*Result {
+	return defaultIndex.Nearby(center, radius, limit, filter)
+}
+
+// 在默认索引中查询矩形范围内的设备
+func Within(leftTop, rightBottom geo.Point, limit int, filter func(phone string) bool) []*Result {
+	return defaultIndex.Within(leftTop, rightBottom, limit, filter)
+}
+
+// 更新设备位置。未定位的汇报不改变索引，保留最后一次定位的位置；早于已索引位置的汇报被忽略
+func (idx *Index) Update(dg *model.DeviceGeo) {
+	if dg.Location == nil || dg.Geo == nil || dg.Geo.LocationStatus != 1 {
+		return
+	}
+	p := geo.Point{Lat: dg.Location.Latitude, Lng: dg.Location.Longitude}
+	c := cellOf(p)
+
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	if old, ok := idx.entries[dg.Phone]; ok {
+		if dg.Time.Before(old.dg.Time) {
+			return
+		}
+		idx.removeFromCell(dg.Phone, old.cell)
+	}
+	e := &entry{point: p, cell: c, dg: dg}
+	idx.entries[dg.Phone] = e
+	members, ok := idx.cells[c]
+	if !ok {
+		members = make(map[string]*entry)
+		idx.cells[c] = members
+	}
+	members[dg.Phone] = e
+}
+
+func (idx *Index) Remove(phone string) {
+	idx.mutex.Lock()
+	defer idx.mutex.Unlock()
+	if e, ok := idx.entries[phone]; ok {
+		idx.removeFromCell(phone, e.cell)
+		delete(idx.entries, phone)
+	}
+}
+
+func (idx *Index) removeFromCell(phone string, c cell) {
+	members := idx.cells[c]
+	delete(members, phone)
+	if len(members) == 0 {
+		delete(idx.cells, c)
+	}
+}
+
+func (idx *Index) Len() int {
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	return len(idx.entries)
+}
+
+// 查询到center距离不超过radius(米)的设备，按距离升序，limit大于0时最多返回limit个。
+// filter不为nil时只返回filter为true的设备，filter在释放索引锁后按结果顺序调用，可以访问其它缓存
+func (idx *Index) Nearby(center geo.Point, radius float64, limit int, filter func(phone string) bool) []*Result {
+	dLat := radius / geo.EarthRadius * 180 / math.Pi
+	// 高纬度时经度跨度按范围内离赤道最远的纬度计算
+	maxLat := math.Min(90, math.Abs(center.Lat)+dLat)
+	dLng := 360.0
+	if cos := math.Cos(maxLat * math.Pi / 180); cos > 1e-9 {
+		dLng = math.Min(360, dLat/cos)
+	}
+	lngs := splitLng(center.Lng-dLng, center.Lng+dLng)
+
+	res := idx.scan(center.Lat-dLat, center.Lat+dLat, lngs, func(p geo.Point) (float64, bool) {
+		d := geo.Distance(center, p)
+		return d, d <= radius
+	})
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Distance != res[j].Distance {
+			return res[i].Distance < res[j].Distance
+		}
+		return res[i].Geo.Phone < res[j].Geo.Phone
+	})
+	return filterResults(res, limit, filter)
+}
+
+// 查询矩形范围内(含边界)的设备，按手机号升序，limit大于0时最多返回limit个。
+// 左上角经度大于右下角经度时表示矩形跨越180度经线。
+// filter不为nil时只返回filter为true的设备，filter在释放索引锁后按结果顺序调用，可以访问其它缓存
+func (idx *Index) Within(leftTop, rightBottom geo.Point, limit int, filter func(phone string) bool) []*Result {
+	lngs := splitLng(leftTop.Lng, rightBottom.Lng)
+	res := idx.scan(rightBottom.Lat, leftTop.Lat, lngs, func(p geo.Point) (float64, bool) {
+		if p.Lat < rightBottom.Lat || p.Lat > leftTop.Lat {
+			return 0, false
+		}
+		for _, r := range lngs {
+			if p.Lng >= r.min && p.Lng <= r.max {
+				return 0, true
+			}
+		}
+		return 0, false
+	})
+	sort.Slice(res, func(i, j int) bool { return res[i].Geo.Phone < res[j].Geo.Phone })
+	return filterResults(res, limit, filter)
+}
+
+// 经度范围，含边界
+type lngRange struct {
+	min, max float64
+}
+
+// 将经度范围拆分为不跨越180度经线的范围，拆分时东侧范围在前。
+// min小于-180或max大于180时超出部分绕到另一侧，min大于max时表示从min向东经过180度经线到max
+func splitLng(min, max float64) []lngRange {
+	switch {
+	case max-min >= 360:
+		return []lngRange{{min: -180, max: 180}}
+	case min < -180:
+		return []lngRange{{min: min + 360, max: 180}, {min: -180, max: max}}
+	case max > 180:
+		return []lngRange{{min: min, max: 180}, {min: -180, max: max - 360}}
+	case min > max:
+		return []lngRange{{min: min, max: 180}, {min: -180, max: max}}
+	}
+	return []lngRange{{min: min, max: max}}
+}
+
+// 在读锁内遍历与各经度范围相交的网格，只收集match为true的设备，match返回到查询中心的距离
+func (idx *Index) scan(minLat, maxLat float64, lngs []lngRange, match func(p geo.Point) (float64, bool)) []*Result {
+	type cellRange struct{ min, max cell }
+	ranges := make([]cellRange, 0, len(lngs))
+	for _, r := range lngs {
+		cr := cellRange{min: cellOf(geo.Point{Lat: minLat, Lng: r.min}), max: cellOf(geo.Point{Lat: maxLat, Lng: r.max})}
+		if cr.min.lat <= cr.max.lat && cr.min.lng <= cr.max.lng {
+			ranges = append(ranges, cr)
+		}
+	}
+	// 两段范围的间隔小于一个网格时落在同一列网格，合并为一段避免重复收集
+	if len(ranges) == 2 && ranges[1].max.lng >= ranges[0].min.lng {
+		ranges = []cellRange{{min: ranges[1].min, max: ranges[0].max}}
+	}
+	var cells int64
+	for _, cr := range ranges {
+		cells += (int64(cr.max.lat-cr.min.lat) + 1) * (int64(cr.max.lng-cr.min.lng) + 1)
+	}
+	res := make([]*Result, 0)
+	collect := func(e *entry) {
+		if d, ok := match(e.point); ok {
+			res = append(res, &Result{Geo: e.dg, Distance: d})
+		}
+	}
+
+	idx.mutex.RLock()
+	defer idx.mutex.RUnlock()
+	if len(ranges) == 0 {
+		return res
+	}
+	if cells > int64(len(idx.cells)) {
+		for _, e := range idx.entries {
+			collect(e)
+		}
+		return res
+	}
+	for _, cr := range ranges {
+		for lat := cr.min.lat; lat <= cr.max.lat; lat++ {
+			for lng := cr.min.lng; lng <= cr.max.lng; lng++ {
+				for _, e := range idx.cells[cell{lat: lat, lng: lng}] {
+					collect(e)
+				}
+			}
+		}
+	}
+	return res
+}
+
+// 按顺序保留filter为true的结果，达到limit后不再调用filter
+func filterResults(res []*Result, limit int, filter func(phone string) bool) []*Result {
+	if filter == nil {
+		if limit > 0 && len(res) > limit {
+			res = res[:limit]
+		}
+		return res
+	}
+	kept := res[:0]
+	for _, r := range res {
+		if limit > 0 && len(kept) >= limit {
+			break
+		}
+		if filter(r.Geo.Phone) {
+			kept = append(kept, r)
+		}
+	}
+	return kept
+}
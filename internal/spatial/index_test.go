@@ -0,0 +1,269 @@
+package spatial
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+func located(phone string, lat, lng float64, t time.Time) *model.DeviceGeo {
+	return &model.DeviceGeo{
+		Phone:    phone,
+		Geo:      &model.GeoMeta{LocationStatus: 1},
+		Location: &model.Location{Latitude: lat, Longitude: lng},
+		Time:     t,
+	}
+}
+
+func phones(results []*Result) []string {
+	res := make([]string, 0, len(results))
+	for _, r := range results {
+		res = append(res, r.Geo.Phone)
+	}
+	return res
+}
+
+func TestIndex_Update(t *testing.T) {
+	idx := NewIndex()
+	now := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	center := geo.Point{Lat: 39.9, Lng: 116.4}
+
+	idx.Update(located("a", 39.9, 116.4, now))
+	// 未定位的汇报保留最后定位的位置
+	idx.Update(&model.DeviceGeo{Phone: "a", Geo: &model.GeoMeta{}, Location: &model.Location{}, Time: now.Add(time.Minute)})
+	assert.Equal(t, []string{"a"}, phones(idx.Nearby(center, 100, 0, nil)))
+
+	// 移动到其它网格
+	idx.Update(located("a", 31.2, 121.5, now.Add(2*time.Minute)))
+	assert.Empty(t, idx.Nearby(center, 10000, 0, nil))
+	// 乱序的汇报被忽略
+	idx.Update(located("a", 39.9, 116.4, now.Add(time.Minute)))
+	assert.Empty(t, idx.Nearby(center, 10000, 0, nil))
+	assert.Equal(t, 1, idx.Len())
+
+	idx.Remove("a")
+	assert.Equal(t, 0, idx.Len())
+	assert.Empty(t, idx.cells)
+}
+
+func TestIndex_Nearby(t *testing.T) {
+	idx := NewIndex()
+	now := time.Now()
+	center := geo.Point{Lat: 39.9, Lng: 116.4}
+	idx.Update(located("far", 39.9, 116.5, now))   // 约8.5km
+	idx.Update(located("near", 39.91, 116.4, now)) // 约1.1km
+	idx.Update(located("mid", 39.9, 116.44, now))  // 约3.4km
+	idx.Update(located("other", 39.91, 116.41, now))
+
+	tests := []struct {
+		name   string
+		radius float64
+		limit  int
+		filter func(string) bool
+		want   []string
+	}{
+		{name: "5km", radius: 5000, want: []string{"near", "other", "mid"}},
+		{name: "10km", radius: 10000, want: []string{"near", "other", "mid", "far"}},
+		{name: "limit", radius: 10000, limit: 2, want: []string{"near", "other"}},
+		{name: "filter", radius: 10000, filter: func(p string) bool { return p != "other" }, want: []string{"near", "mid", "far"}},
+		{name: "none", radius: 500, want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := idx.Nearby(center, tt.radius, tt.limit, tt.filter)
+			assert.Equal(t, tt.want, phones(res))
+			for i := 1; i < len(res); i++ {
+				assert.LessOrEqual(t, res[i-1].Distance, res[i].Distance)
+			}
+		})
+	}
+}
+
+func TestIndex_Within(t *testing.T) {
+	idx := NewIndex()
+	now := time.Now()
+	idx.Update(located("b", 39.95, 116.45, now))
+	idx.Update(located("a", 39.9, 116.4, now))
+	idx.Update(located("c", 40.5, 116.4, now))
+
+	res := idx.Within(geo.Point{Lat: 40, Lng: 116.3}, geo.Point{Lat: 39.8, Lng: 116.5}, 0, nil)
+	assert.Equal(t, []string{"a", "b"}, phones(res))
+	res = idx.Within(geo.Point{Lat: 90, Lng: -180}, geo.Point{Lat: -90, Lng: 180}, 2, nil)
+	assert.Equal(t, []string{"a", "b"}, phones(res))
+}
+
+func TestIndex_Antimeridian(t *testing.T) {
+	idx := NewIndex()
+	now := time.Now()
+	idx.Update(located("east", 0, 179.99, now))
+	idx.Update(located("west", 0, -179.99, now))
+	idx.Update(located("far", 0, 170, now))
+	idx.Update(located("origin", 0, 0.001, now))
+
+	tests := []struct {
+		name string
+		fn   func() []*Result
+		want []string
+	}{
+		{name: "nearby east side", fn: func() []*Result { return idx.Nearby(geo.Point{Lng: 179.995}, 5000, 0, nil) }, want: []string{"east", "west"}},
+		{name: "nearby west side", fn: func() []*Result { return idx.Nearby(geo.Point{Lng: -179.999}, 5000, 0, nil) }, want: []string{"west", "east"}},
+		{
+			name: "within crossing",
+			fn: func() []*Result {
+				return idx.Within(geo.Point{Lat: 10, Lng: 179}, geo.Point{Lat: -10, Lng: -179}, 0, nil)
+			},
+			want: []string{"east", "west"},
+		},
+		{
+			name: "within crossing almost whole world",
+			fn: func() []*Result {
+				return idx.Within(geo.Point{Lat: 10, Lng: 0.01}, geo.Point{Lat: -10, Lng: 0.005}, 0, nil)
+			},
+			want: []string{"east", "far", "origin", "west"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, phones(tt.fn()))
+		})
+	}
+}
+
+// 180度经线两侧的设备，与遍历全部设备的结果比较
+func TestIndex_AntimeridianMatchesBruteForce(t *testing.T) {
+	idx := NewIndex()
+	rnd := rand.New(rand.NewSource(1))
+	now := time.Now()
+	points := make(map[string]geo.Point)
+	for i := 0; i < 5000; i++ {
+		phone := fmt.Sprintf("%011d", i)
+		p := geo.Point{Lat: -1 + rnd.Float64()*2, Lng: 179 + rnd.Float64()*2}
+		if p.Lng > 180 {
+			p.Lng -= 360
+		}
+		points[phone] = p
+		idx.Update(located(phone, p.Lat, p.Lng, now))
+	}
+	for i := 0; i < 20; i++ {
+		center := geo.Point{Lat: -1 + rnd.Float64()*2, Lng: 179.8 + rnd.Float64()*0.4}
+		if center.Lng > 180 {
+			center.Lng -= 360
+		}
+		radius := 1000 + rnd.Float64()*30000
+		want := 0
+		for _, p := range points {
+			if geo.Distance(center, p) <= radius {
+				want++
+			}
+		}
+		require.Len(t, idx.Nearby(center, radius, 0, nil), want)
+	}
+
+	want := 0
+	for _, p := range points {
+		if p.Lat >= -0.5 && p.Lat <= 0.5 && (p.Lng >= 179.5 || p.Lng <= -179.5) {
+			want++
+		}
+	}
+	require.Len(t, idx.Within(geo.Point{Lat: 0.5, Lng: 179.5}, geo.Point{Lat: -0.5, Lng: -179.5}, 0, nil), want)
+}
+
+// 与遍历全部设备的结果比较
+func TestIndex_NearbyMatchesBruteForce(t *testing.T) {
+	idx := NewIndex()
+	rnd := rand.New(rand.NewSource(1))
+	now := time.Now()
+	points := make(map[string]geo.Point)
+	for i := 0; i < 5000; i++ {
+		phone := fmt.Sprintf("%011d", i)
+		p := geo.Point{Lat: 39 + rnd.Float64()*2, Lng: 116 + rnd.Float64()*2}
+		points[phone] = p
+		idx.Update(located(phone, p.Lat, p.Lng, now))
+	}
+	for i := 0; i < 20; i++ {
+		center := geo.Point{Lat: 39 + rnd.Float64()*2, Lng: 116 + rnd.Float64()*2}
+		radius := 1000 + rnd.Float64()*30000
+		want := 0
+		for _, p := range points {
+			if geo.Distance(center, p) <= radius {
+				want++
+			}
+		}
+		require.Len(t, idx.Nearby(center, radius, 0, nil), want)
+	}
+}
+
+// filter在释放索引锁后调用，filter内更新索引不会死锁
+func TestIndex_FilterOutsideLock(t *testing.T) {
+	idx := NewIndex()
+	now := time.Now()
+	idx.Update(located("a", 39.9, 116.4, now))
+	idx.Update(located("b", 39.91, 116.4, now))
+	idx.Update(located("c", 45, 116.4, now))
+
+	var called []string
+	filter := func(phone string) bool {
+		called = append(called, phone)
+		idx.Update(located(phone, 39.9, 116.4, now.Add(time.Minute)))
+		return true
+	}
+	res := idx.Nearby(geo.Point{Lat: 39.9, Lng: 116.4}, 5000, 1, filter)
+	assert.Equal(t, []string{"a"}, phones(res))
+	// 先按几何范围过滤，达到limit后不再调用filter
+	assert.Equal(t, []string{"a"}, called)
+
+	called = nil
+	res = idx.Within(geo.Point{Lat: 40, Lng: 116.3}, geo.Point{Lat: 39.8, Lng: 116.5}, 0, filter)
+	assert.Equal(t, []string{"a", "b"}, phones(res))
+	assert.Equal(t, []string{"a", "b"}, called)
+}
+
+// 10万台设备分布在约200km x 170km范围内
+func benchmarkIndex(b *testing.B) *Index {
+	idx := NewIndex()
+	rnd := rand.New(rand.NewSource(1))
+	now := time.Now()
+	for i := 0; i < 100000; i++ {
+		idx.Update(located(fmt.Sprintf("%011d", i), 39+rnd.Float64()*2, 116+rnd.Float64()*2, now))
+	}
+	b.ResetTimer()
+	return idx
+}
+
+func BenchmarkIndex_Nearby100k(b *testing.B) {
+	idx := benchmarkIndex(b)
+	filter := func(phone string) bool { return phone[len(phone)-1] != '0' }
+	for i := 0; i < b.N; i++ {
+		idx.Nearby(geo.Point{Lat: 40, Lng: 117}, 5000, 100, filter)
+	}
+}
+
+func BenchmarkIndex_Within100k(b *testing.B) {
+	idx := benchmarkIndex(b)
+	filter := func(phone string) bool { return phone[len(phone)-1] != '0' }
+	for i := 0; i < b.N; i++ {
+		idx.Within(geo.Point{Lat: 40.05, Lng: 116.95}, geo.Point{Lat: 39.95, Lng: 117.05}, 100, filter)
+	}
+}
+
+// 查询与位置更新并发，衡量查询持有读锁对更新的影响
+func BenchmarkIndex_NearbyWithUpdates100k(b *testing.B) {
+	idx := benchmarkIndex(b)
+	b.RunParallel(func(pb *testing.PB) {
+		rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+		for i := 0; pb.Next(); i++ {
+			if i%2 == 0 {
+				idx.Nearby(geo.Point{Lat: 40, Lng: 117}, 10000, 100, nil)
+				continue
+			}
+			idx.Update(located(fmt.Sprintf("%011d", rnd.Intn(100000)), 39+rnd.Float64()*2, 116+rnd.Float64()*2, time.Now()))
+		}
+	})
+}
@@ -42,9 +42,22 @@ func (cache *DeviceCache) IsUpdated() bool {
 }
 
 func (cache *DeviceCache) ListDevice() []*model.Device {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
 	return maps.Values(cache.CacheByPhone)
 }
 
+// 按设备状态统计设备数
+func (cache *DeviceCache) CountByStatus() map[model.DeviceStatus]int {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	res := make(map[model.DeviceStatus]int)
+	for _, d := range cache.CacheByPhone {
+		res[d.Status]++
+	}
+	return res
+}
+
 func (cache *DeviceCache) GetDeviceByPhone(phone string) (*model.Device, error) {
 	cache.mutex.Lock()
 	defer cache.mutex.Unlock()
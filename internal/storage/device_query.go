@@ -0,0 +1,249 @@
+package storage
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrInvalidSortBy = errors.New("invalid sort field")
+)
+
+const (
+	DefaultPageLimit = 100
+	MaxPageLimit     = 1000
+)
+
+// 设备列表排序字段
+const (
+	SortByPhone       = "phone"
+	SortByPlate       = "plate"
+	SortByLastComTime = "lastComTime"
+	SortByStatus      = "status"
+)
+
+// 设备查询条件，零值字段表示不过滤
+type DeviceQuery struct {
+	Status      *model.DeviceStatus
+	VersionDesc *model.VersionType
+	PlatePrefix string
+	PhonePrefix string
+	ComTimeFrom time.Time // 最近通信时间下限(含)
+	ComTimeTo   time.Time // 最近通信时间上限(不含)
+	RegionCode  string    // GB/T 2260 行政区划代码前缀，如 "44" 或 "4403"
+	Group       string    // 设备分组名
+
+	Filter func(*model.Device) bool // 额外的过滤条件，如调用方可访问的设备
+
+	SortBy string // 默认按phone排序
+	Desc   bool
+	Cursor string // 上一页返回的NextCursor
+	Limit  int    // 每页条数，默认DefaultPageLimit，最大MaxPageLimit
+}
+
+type DevicePage struct {
+	Devices    []*model.Device `json:"devices"`
+	Total      int             `json:"total"`      // 满足条件的设备总数
+	NextCursor string          `json:"nextCursor"` // 为空表示没有下一页
+}
+
+// 游标记录上一页最后一个设备的排序值，phone保证排序唯一
+type deviceCursor struct {
+	Key   string `json:"k"`
+	Phone string `json:"p"`
+}
+
+// 过滤设备，按排序和游标分页，返回的设备为查询时的快照
+func (cache *DeviceCache) QueryDevice(q *DeviceQuery) (*DevicePage, error) {
+	var after *deviceCursor
+	if q.Cursor != "" {
+		var err error
+		if after, err = decodeCursor(q.Cursor); err != nil {
+			return nil, err
+		}
+	}
+	limit := q.Limit
+	if limit <= 0 {
+		limit = DefaultPageLimit
+	}
+	if limit > MaxPageLimit {
+		limit = MaxPageLimit
+	}
+
+	items, err := cache.sortedDevice(q)
+	if err != nil {
+		return nil, err
+	}
+
+	start := 0
+	if after != nil {
+		start = sort.Search(len(items), func(i int) bool {
+			return q.less(after.Key, after.Phone, items[i].key, items[i].phone)
+		})
+	}
+	end := start + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	page := &DevicePage{Devices: make([]*model.Device, 0, end-start), Total: len(items)}
+	for _, it := range items[start:end] {
+		page.Devices = append(page.Devices, it.d)
+	}
+	if end < len(items) {
+		last := items[end-1]
+		page.NextCursor = encodeCursor(&deviceCursor{Key: last.key, Phone: last.phone})
+	}
+	return page, nil
+}
+
+// 返回满足条件的全部设备，按排序不分页，忽略Cursor和Limit
+func (cache *DeviceCache) FilterDevice(q *DeviceQuery) ([]*model.Device, error) {
+	items, err := cache.sortedDevice(q)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*model.Device, 0, len(items))
+	for _, it := range items {
+		res = append(res, it.d)
+	}
+	return res, nil
+}
+
+// 在设备缓存锁内生成的设备快照及其排序值，消息处理会原地修改缓存中的设备
+type keyedDevice struct {
+	key   string
+	phone string
+	d     *model.Device
+}
+
+func (cache *DeviceCache) sortedDevice(q *DeviceQuery) ([]keyedDevice, error) {
+	keyFn, err := sortKeyFunc(q.SortBy)
+	if err != nil {
+		return nil, err
+	}
+	items, err := cache.matchDevice(q, keyFn)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(items, func(i, j int) bool {
+		return q.less(items[i].key, items[i].phone, items[j].key, items[j].phone)
+	})
+	return items, nil
+}
+
+// 先比较排序值，相同时比较phone
+func (q *DeviceQuery) less(k1, p1, k2, p2 string) bool {
+	if q.Desc {
+		k1, p1, k2, p2 = k2, p2, k1, p1
+	}
+	if k1 != k2 {
+		return k1 < k2
+	}
+	return p1 < p2
+}
+
+// 统计满足条件的设备数
+func (cache *DeviceCache) CountDevice(q *DeviceQuery) (int, error) {
+	matched, err := cache.matchDevice(q, nil)
+	return len(matched), err
+}
+
+// 在锁内过滤并复制设备、计算排序值，keyFn为nil时不计算；Filter在锁外对快照执行
+func (cache *DeviceCache) matchDevice(q *DeviceQuery, keyFn func(*model.Device) string) ([]keyedDevice, error) {
+	// 分组在设备缓存锁外获取，避免两个缓存的锁嵌套
+	var group *model.DeviceGroup
+	if q.Group != "" {
+		var err error
+		if group, err = GetGroupCache().GetGroup(q.Group); err != nil {
+			return nil, err
+		}
+	}
+
+	cache.mutex.Lock()
+	candidates := make([]keyedDevice, 0)
+	for _, d := range cache.CacheByPhone {
+		if !q.match(d) || (group != nil && !group.Match(d)) {
+			continue
+		}
+		snapshot := *d
+		it := keyedDevice{phone: d.Phone, d: &snapshot}
+		if keyFn != nil {
+			it.key = keyFn(d)
+		}
+		candidates = append(candidates, it)
+	}
+	cache.mutex.Unlock()
+
+	// Filter可能访问其他缓存(如调用方的分组权限)，在设备缓存锁外执行
+	if q.Filter == nil {
+		return candidates, nil
+	}
+	res := candidates[:0]
+	for _, it := range candidates {
+		if q.Filter(it.d) {
+			res = append(res, it)
+		}
+	}
+	return res, nil
+}
+
+func (q *DeviceQuery) match(d *model.Device) bool {
+	switch {
+	case q.Status != nil && d.Status != *q.Status:
+		return false
+	case q.VersionDesc != nil && d.VersionDesc != *q.VersionDesc:
+		return false
+	case !strings.HasPrefix(d.Plate, q.PlatePrefix), !strings.HasPrefix(d.Phone, q.PhonePrefix):
+		return false
+	case !q.ComTimeFrom.IsZero() && d.LastestComTime.Before(q.ComTimeFrom):
+		return false
+	case !q.ComTimeTo.IsZero() && !d.LastestComTime.Before(q.ComTimeTo):
+		return false
+	case q.RegionCode != "" && !strings.HasPrefix(d.RegionCode(), q.RegionCode):
+		return false
+	}
+	return true
+}
+
+// 排序值统一转为可按字典序比较的字符串
+func sortKeyFunc(sortBy string) (func(*model.Device) string, error) {
+	switch sortBy {
+	case "", SortByPhone:
+		return func(d *model.Device) string { return d.Phone }, nil
+	case SortByPlate:
+		return func(d *model.Device) string { return d.Plate }, nil
+	case SortByLastComTime:
+		return func(d *model.Device) string { return fmt.Sprintf("%020d", d.LastestComTime.UnixNano()) }, nil
+	case SortByStatus:
+		return func(d *model.Device) string { return fmt.Sprintf("%03d", d.Status) }, nil
+	default:
+		return nil, errors.Wrapf(ErrInvalidSortBy, "sort=%s", sortBy)
+	}
+}
+
+func encodeCursor(c *deviceCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeCursor(s string) (*deviceCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+	c := &deviceCursor{}
+	if err := json.Unmarshal(b, c); err != nil {
+		return nil, errors.Wrap(ErrInvalidCursor, err.Error())
+	}
+	return c, nil
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+func newTestDeviceCache(n int) *DeviceCache {
+	cache := &DeviceCache{
+		CacheByPhone: make(map[string]*model.Device),
+		mutex:        &sync.Mutex{},
+	}
+	base := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < n; i++ {
+		d := &model.Device{
+			Phone:          fmt.Sprintf("1380000%04d", i),
+			Plate:          fmt.Sprintf("粤B%05d", n-i),
+			Status:         model.DeviceStatus(i % 2),
+			VersionDesc:    model.Version2019,
+			LastestComTime: base.Add(time.Duration(i) * time.Minute),
+			ProvinceID:     44,
+			CityID:         uint16(300 + i%2),
+		}
+		cache.CacheByPhone[d.Phone] = d
+	}
+	return cache
+}
+
+func TestDeviceCache_QueryDevice(t *testing.T) {
+	cache := newTestDeviceCache(10)
+	online := model.DeviceStatusOnline
+
+	tests := []struct {
+		name      string
+		query     *DeviceQuery
+		wantTotal int
+		wantFirst string
+	}{
+		{name: "all", query: &DeviceQuery{}, wantTotal: 10, wantFirst: "13800000000"},
+		{name: "status", query: &DeviceQuery{Status: &online}, wantTotal: 5, wantFirst: "13800000001"},
+		{name: "phone prefix", query: &DeviceQuery{PhonePrefix: "138000000"}, wantTotal: 10, wantFirst: "13800000000"},
+		{name: "plate prefix", query: &DeviceQuery{PlatePrefix: "粤B0001"}, wantTotal: 1, wantFirst: "13800000000"},
+		{name: "region", query: &DeviceQuery{RegionCode: "440301"}, wantTotal: 5, wantFirst: "13800000001"},
+		{
+			name: "com time range",
+			query: &DeviceQuery{
+				ComTimeFrom: time.Date(2023, 1, 1, 0, 2, 0, 0, time.UTC),
+				ComTimeTo:   time.Date(2023, 1, 1, 0, 5, 0, 0, time.UTC),
+			},
+			wantTotal: 3,
+			wantFirst: "13800000002",
+		},
+		{name: "sort by plate", query: &DeviceQuery{SortBy: SortByPlate}, wantTotal: 10, wantFirst: "13800000009"},
+		{name: "sort desc", query: &DeviceQuery{SortBy: SortByLastComTime, Desc: true}, wantTotal: 10, wantFirst: "13800000009"},
+		{
+			// 过滤条件再次获取设备缓存锁，不能死锁
+			name: "filter outside lock",
+			query: &DeviceQuery{Filter: func(d *model.Device) bool {
+				got, err := cache.GetDeviceByPhone(d.Phone)
+				return err == nil && got.CityID == 300
+			}},
+			wantTotal: 5,
+			wantFirst: "13800000000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page, err := cache.QueryDevice(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, page.Total)
+			require.NotEmpty(t, page.Devices)
+			assert.Equal(t, tt.wantFirst, page.Devices[0].Phone)
+
+			cnt, err := cache.CountDevice(tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, cnt)
+		})
+	}
+}
+
+func TestDeviceCache_QueryDevicePaging(t *testing.T) {
+	cache := newTestDeviceCache(25)
+	for _, desc := range []bool{false, true} {
+		q := &DeviceQuery{SortBy: SortByStatus, Desc: desc, Limit: 10}
+		var phones []string
+		for {
+			page, err := cache.QueryDevice(q)
+			require.NoError(t, err)
+			for _, d := range page.Devices {
+				phones = append(phones, d.Phone)
+			}
+			if page.NextCursor == "" {
+				break
+			}
+			q.Cursor = page.NextCursor
+		}
+		assert.Len(t, phones, 25)
+		seen := make(map[string]bool)
+		for _, p := range phones {
+			assert.False(t, seen[p], "duplicated %s", p)
+			seen[p] = true
+		}
+	}
+
+	_, err := cache.QueryDevice(&DeviceQuery{Cursor: "!!"})
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+	_, err = cache.QueryDevice(&DeviceQuery{SortBy: "imei"})
+	assert.ErrorIs(t, err, ErrInvalidSortBy)
+}
+
+func TestDeviceCache_FilterDevice(t *testing.T) {
+	cache := newTestDeviceCache(DefaultPageLimit + 20)
+	online := model.DeviceStatusOnline
+
+	devices, err := cache.FilterDevice(&DeviceQuery{Limit: 10, Cursor: "ignored"})
+	require.NoError(t, err)
+	assert.Len(t, devices, DefaultPageLimit+20) // 不分页
+	assert.Equal(t, "13800000000", devices[0].Phone)
+
+	devices, err = cache.FilterDevice(&DeviceQuery{Status: &online, SortBy: SortByPlate})
+	require.NoError(t, err)
+	assert.Len(t, devices, (DefaultPageLimit+20)/2)
+	assert.Equal(t, "13800000119", devices[0].Phone)
+
+	_, err = cache.FilterDevice(&DeviceQuery{SortBy: "imei"})
+	assert.ErrorIs(t, err, ErrInvalidSortBy)
+}
+
+// 排序值在锁内计算，与在锁内原地修改设备的写入方不会产生数据竞争(go test -race)
+func TestDeviceCache_QueryDeviceConcurrentUpdate(t *testing.T) {
+	cache := newTestDeviceCache(50)
+	stop, done := make(chan struct{}), make(chan struct{})
+	var updates atomic.Int32
+	go func() {
+		defer close(done)
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			cache.Lock()
+			for _, d := range cache.CacheByPhone {
+				d.Plate = fmt.Sprintf("粤B%05d", i)
+				d.LastestComTime = d.LastestComTime.Add(time.Second)
+				d.Status = model.DeviceStatus(i % 2)
+			}
+			cache.Unlock()
+			updates.Add(1)
+		}
+	}()
+	filter := func(d *model.Device) bool { return d.Plate != "" && d.Status >= 0 }
+	sorts := []string{SortByPlate, SortByLastComTime, SortByStatus}
+	for i := 0; i < 30 || updates.Load() < 30; i++ {
+		page, err := cache.QueryDevice(&DeviceQuery{SortBy: sorts[i%len(sorts)], Filter: filter})
+		require.NoError(t, err)
+		require.Equal(t, 50, page.Total)
+	}
+	close(stop)
+	<-done
+}
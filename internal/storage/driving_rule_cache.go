@@ -0,0 +1,59 @@
+package storage
+
+import (
+	"sync"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+// 设备驾驶规则覆盖项缓存
+type DrivingRuleCache struct {
+	CacheByPhone map[string]*model.DrivingRuleOverride
+	mutex        *sync.Mutex
+	updated      bool
+}
+
+var drivingRuleCacheSingleton *DrivingRuleCache
+var drivingRuleCacheInitOnce sync.Once
+
+func GetDrivingRuleCache() *DrivingRuleCache {
+	drivingRuleCacheInitOnce.Do(func() {
+		drivingRuleCacheSingleton = &DrivingRuleCache{
+			CacheByPhone: make(map[string]*model.DrivingRuleOverride),
+			mutex:        &sync.Mutex{},
+		}
+		NewPersister("driving_rule.json", drivingRuleCacheSingleton) //启动自动持久化
+	})
+	return drivingRuleCacheSingleton
+}
+
+func (cache *DrivingRuleCache) Lock() {
+	cache.mutex.Lock()
+}
+func (cache *DrivingRuleCache) Unlock() {
+	cache.mutex.Unlock()
+}
+func (cache *DrivingRuleCache) IsUpdated() bool {
+	return cache.updated
+}
+
+// 设备的覆盖项，没有时返回nil
+func (cache *DrivingRuleCache) GetOverride(phone string) *model.DrivingRuleOverride {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	return cache.CacheByPhone[phone]
+}
+
+func (cache *DrivingRuleCache) CacheOverride(phone string, o *model.DrivingRuleOverride) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.updated = true
+	cache.CacheByPhone[phone] = o
+}
+
+func (cache *DrivingRuleCache) DelOverride(phone string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.updated = true
+	delete(cache.CacheByPhone, phone)
+}
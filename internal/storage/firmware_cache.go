@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+var ErrFirmwareNotFound = errors.New("firmware not found")
+
+// 升级包内容的存储目录，元数据持久化在firmware.json
+const firmwareDir = "firmware"
+
+type FirmwareCache struct {
+	CacheByID map[string]*model.Firmware
+	mutex     *sync.Mutex
+	updated   bool
+}
+
+var firmwareCacheSingleton *FirmwareCache
+var firmwareCacheInitOnce sync.Once
+
+func GetFirmwareCache() *FirmwareCache {
+	firmwareCacheInitOnce.Do(func() {
+		firmwareCacheSingleton = &FirmwareCache{
+			CacheByID: make(map[string]*model.Firmware),
+			mutex:     &sync.Mutex{},
+		}
+		NewPersister("firmware.json", firmwareCacheSingleton) //启动自动持久化
+	})
+	return firmwareCacheSingleton
+}
+
+func (cache *FirmwareCache) Lock() {
+	cache.mutex.Lock()
+}
+func (cache *FirmwareCache) Unlock() {
+	cache.mutex.Unlock()
+}
+func (cache *FirmwareCache) IsUpdated() bool {
+	return cache.updated
+}
+
+func firmwarePath(id string) string {
+	return filepath.Join(firmwareDir, id+".bin")
+}
+
+// 保存升级包内容及元数据
+func (cache *FirmwareCache) SaveFirmware(fw *model.Firmware, image []byte) error {
+	if err := os.MkdirAll(firmwareDir, 0755); err != nil {
+		return wrapError(err, "create firmware directory failed")
+	}
+	tmpFile := firmwarePath(fw.ID) + ".tmp"
+	if err := os.WriteFile(tmpFile, image, 0644); err != nil {
+		return wrapError(err, "write firmware failed")
+	}
+	if err := os.Rename(tmpFile, firmwarePath(fw.ID)); err != nil {
+		return wrapError(err, "write firmware failed")
+	}
+
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.updated = true
+	cache.CacheByID[fw.ID] = fw
+	return nil
+}
+
+func (cache *FirmwareCache) GetFirmware(id string) (*model.Firmware, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if fw, ok := cache.CacheByID[id]; ok {
+		return fw, nil
+	}
+	return nil, ErrFirmwareNotFound
+}
+
+// 读取升级包内容
+func (cache *FirmwareCache) ReadImage(id string) ([]byte, error) {
+	if _, err := cache.GetFirmware(id); err != nil {
+		return nil, err
+	}
+	image, err := os.ReadFile(firmwarePath(id))
+	if err != nil {
+		return nil, wrapError(err, "read firmware failed")
+	}
+	return image, nil
+}
+
+func (cache *FirmwareCache) DelFirmware(id string) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if _, ok := cache.CacheByID[id]; !ok {
+		return ErrFirmwareNotFound
+	}
+	cache.updated = true
+	delete(cache.CacheByID, id)
+	if err := os.Remove(firmwarePath(id)); err != nil && !os.IsNotExist(err) {
+		return wrapError(err, "remove firmware failed")
+	}
+	return nil
+}
+
+// 按上传时间倒序返回所有升级包
+func (cache *FirmwareCache) ListFirmware() []*model.Firmware {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	res := make([]*model.Firmware, 0, len(cache.CacheByID))
+	for _, fw := range cache.CacheByID {
+		res = append(res, fw)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].CreatedAt.After(res[j].CreatedAt) })
+	return res
+}
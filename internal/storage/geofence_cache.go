@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+var ErrGeofenceNotFound = errors.New("geofence not found")
+
+type GeofenceCache struct {
+	CacheByID map[uint32]*model.Geofence
+	mutex     *sync.Mutex
+	updated   bool
+}
+
+var geofenceCacheSingleton *GeofenceCache
+var geofenceCacheInitOnce sync.Once
+
+func GetGeofenceCache() *GeofenceCache {
+	geofenceCacheInitOnce.Do(func() {
+		geofenceCacheSingleton = &GeofenceCache{
+			CacheByID: make(map[uint32]*model.Geofence),
+			mutex:     &sync.Mutex{},
+		}
+		NewPersister("geofence.json", geofenceCacheSingleton) //启动自动持久化
+	})
+	return geofenceCacheSingleton
+}
+
+func (cache *GeofenceCache) Lock() {
+	cache.mutex.Lock()
+}
+func (cache *GeofenceCache) Unlock() {
+	cache.mutex.Unlock()
+}
+func (cache *GeofenceCache) IsUpdated() bool {
+	return cache.updated
+}
+
+func (cache *GeofenceCache) GetGeofence(id uint32) (*model.Geofence, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if f, ok := cache.CacheByID[id]; ok {
+		return f, nil
+	}
+	return nil, ErrGeofenceNotFound
+}
+
+// 围栏创建后不修改，更新时整体替换
+func (cache *GeofenceCache) CacheGeofence(f *model.Geofence) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.updated = true
+	cache.CacheByID[f.ID] = f
+}
+
+func (cache *GeofenceCache) DelGeofence(id uint32) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if _, ok := cache.CacheByID[id]; !ok {
+		return ErrGeofenceNotFound
+	}
+	cache.updated = true
+	delete(cache.CacheByID, id)
+	return nil
+}
+
+// 按ID排序返回所有围栏
+func (cache *GeofenceCache) ListGeofence() []*model.Geofence {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	res := make([]*model.Geofence, 0, len(cache.CacheByID))
+	for _, f := range cache.CacheByID {
+		res = append(res, f)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].ID < res[j].ID })
+	return res
+}
+
+// 适用于设备的围栏，按ID排序
+func (cache *GeofenceCache) GeofencesOfDevice(d *model.Device) []*model.Geofence {
+	var groups []string
+	groupsLoaded := false
+	var res []*model.Geofence
+	for _, f := range cache.ListGeofence() {
+		if len(f.Groups) > 0 && !groupsLoaded {
+			groups = GetGroupCache().GroupsOfDevice(d)
+			groupsLoaded = true
+		}
+		if f.AppliesTo(d.Phone, groups) {
+			res = append(res, f)
+		}
+	}
+	return res
+}
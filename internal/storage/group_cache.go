@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+var ErrGroupNotFound = errors.New("group not found")
+
+type GroupCache struct {
+	CacheByName map[string]*model.DeviceGroup
+	mutex       *sync.Mutex
+	updated     bool
+}
+
+var groupCacheSingleton *GroupCache
+var groupCacheInitOnce sync.Once
+
+func GetGroupCache() *GroupCache {
+	groupCacheInitOnce.Do(func() {
+		groupCacheSingleton = &GroupCache{
+			CacheByName: make(map[string]*model.DeviceGroup),
+			mutex:       &sync.Mutex{},
+		}
+		NewPersister("device_group.json", groupCacheSingleton) //启动自动持久化
+	})
+	return groupCacheSingleton
+}
+
+func (cache *GroupCache) Lock() {
+	cache.mutex.Lock()
+}
+func (cache *GroupCache) Unlock() {
+	cache.mutex.Unlock()
+}
+func (cache *GroupCache) IsUpdated() bool {
+	return cache.updated
+}
+
+func (cache *GroupCache) GetGroup(name string) (*model.DeviceGroup, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if g, ok := cache.CacheByName[name]; ok {
+		return g, nil
+	}
+	return nil, ErrGroupNotFound
+}
+
+func (cache *GroupCache) CacheGroup(g *model.DeviceGroup) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.updated = true
+	cache.CacheByName[g.Name] = g
+}
+
+func (cache *GroupCache) DelGroup(name string) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if _, ok := cache.CacheByName[name]; !ok {
+		return ErrGroupNotFound
+	}
+	cache.updated = true
+	delete(cache.CacheByName, name)
+	return nil
+}
+
+// 按名称排序返回所有分组
+func (cache *GroupCache) ListGroup() []*model.DeviceGroup {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	res := make([]*model.DeviceGroup, 0, len(cache.CacheByName))
+	for _, g := range cache.CacheByName {
+		res = append(res, g)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// 设备所属的所有分组名
+func (cache *GroupCache) GroupsOfDevice(d *model.Device) []string {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	var res []string
+	for name, g := range cache.CacheByName {
+		if g.Match(d) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
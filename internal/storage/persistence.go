@@ -8,6 +8,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
 )
 
 type Persistent interface {
@@ -29,6 +31,11 @@ type Persister struct {
 	lastSaveErr error
 }
 
+var (
+	persisters   []*Persister // 所有已启动的持久化任务，用于手动刷盘和就绪检查
+	persistersMu sync.Mutex
+)
+
 func NewPersister(filePath string, obj Persistent) (*Persister, error) {
 	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
 		return nil, wrapError(err, "create storage directory failed")
@@ -44,6 +51,10 @@ func NewPersister(filePath string, obj Persistent) (*Persister, error) {
 		slog.Error(wrapError(err, "load data failed").Error())
 	}
 
+	persistersMu.Lock()
+	persisters = append(persisters, p)
+	persistersMu.Unlock()
+
 	go p.autoSave()
 	return p, nil
 }
@@ -62,6 +73,62 @@ func (p *Persister) Load(data Persistent) error {
 	return p.loadWithRetry(data)
 }
 
+// 立即将对象写入文件，不论是否有更新
+func (p *Persister) Flush() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastSaveErr = p.Save(p.Obj)
+	if p.lastSaveErr != nil {
+		metrics.PersistenceSaveErrors.WithLabelValues(p.filePath).Inc()
+	}
+	return p.lastSaveErr
+}
+
+// 检查存储目录是否可写
+func (p *Persister) CheckWritable() error {
+	probe := p.filePath + ".probe"
+	if err := os.WriteFile(probe, nil, 0644); err != nil {
+		return wrapError(err, "storage not writable")
+	}
+	return os.Remove(probe)
+}
+
+// 刷新所有持久化对象
+func FlushAll() error {
+	persistersMu.Lock()
+	ps := append([]*Persister(nil), persisters...)
+	persistersMu.Unlock()
+
+	var errs []error
+	for _, p := range ps {
+		if err := p.Flush(); err != nil {
+			errs = append(errs, wrapError(err, p.filePath))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// 检查所有持久化文件是否可写
+func CheckPersistence() error {
+	persistersMu.Lock()
+	ps := append([]*Persister(nil), persisters...)
+	persistersMu.Unlock()
+
+	var errs []error
+	for _, p := range ps {
+		if err := p.CheckWritable(); err != nil {
+			errs = append(errs, wrapError(err, p.filePath))
+		}
+		p.mu.Lock()
+		lastErr := p.lastSaveErr
+		p.mu.Unlock()
+		if lastErr != nil {
+			errs = append(errs, wrapError(lastErr, p.filePath))
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (p *Persister) autoSave() {
 	ticker := time.NewTicker(defaultSaveInterval)
 	defer ticker.Stop()
@@ -72,6 +139,10 @@ func (p *Persister) autoSave() {
 			p.mu.Lock()
 			if p.Obj.IsUpdated() {
 				p.lastSaveErr = p.saveWithRetry(p.Obj)
+				if p.lastSaveErr != nil {
+					metrics.PersistenceSaveErrors.WithLabelValues(p.filePath).Inc()
+					slog.Error(p.lastSaveErr.Error())
+				}
 			}
 			p.mu.Unlock()
 		}
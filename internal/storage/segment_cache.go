@@ -4,6 +4,7 @@ import (
 	"strings"
 	"sync"
 
+	"github.com/fakeyanss/jt808-server-go/internal/metrics"
 	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
 )
 
@@ -34,6 +35,8 @@ func CacheSegment(seg *model.Segment) bool {
 	cache := getSegmentCache()
 	cache.mutex.Lock()
 	defer cache.mutex.Unlock()
+	defer func() { metrics.SegmentsPending.Set(float64(len(cache.cacheByKey))) }()
+
 	s, ok := cache.cacheByKey[key]
 	if !ok {
 		cache.cacheByKey[key] = seg
@@ -71,8 +71,22 @@ func ClearSession(id string) {
 	delete(c.cacheByID, id)
 }
 
+// 列出所有session
+func ListSessions() []*model.Session {
+	c := getSessionCache()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	res := make([]*model.Session, 0, len(c.cacheByID))
+	for _, s := range c.cacheByID {
+		res = append(res, s)
+	}
+	return res
+}
+
 // 统计session个数
 func countSession() int {
 	c := getSessionCache()
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
 	return len(c.cacheByID)
 }
@@ -0,0 +1,317 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+const (
+	defaultTrackDir    = "track"
+	trackDayLayout     = "2006-01-02"
+	trackFileExt       = ".jsonl"
+	trackQueueSize     = 8192
+	trackFlushInterval = time.Second
+	trackIdleTimeout   = 5 * time.Minute // 超过该时长未写入的文件被关闭
+)
+
+var (
+	ErrInvalidTrackPhone = errors.New("invalid track phone")
+	ErrTrackQueueFull    = errors.New("track queue full")
+)
+
+// 写入队列中的轨迹点，done不为nil时为刷新请求
+type trackRecord struct {
+	path string
+	line []byte
+	done chan struct{}
+}
+
+// 打开的轨迹文件，写入经缓冲后定时刷新
+type trackFile struct {
+	f         *os.File
+	w         *bufio.Writer
+	lastWrite time.Time
+}
+
+// 历史轨迹存储。每台设备每天一个JSON Lines文件(dir/phone/YYYY-MM-DD.jsonl，按设备时间)，
+// 轨迹点按接收顺序追加，读取时逐行解析，不会一次加载整个时间范围。
+//
+// 追加只写入队列，由写入协程写入各文件的缓冲，每秒刷新到文件，读取和清理前先刷新
+type TrackStore struct {
+	dir       string
+	retention time.Duration // 保留时长，0表示不清理
+	disabled  bool
+	files     map[string]*trackFile // 文件路径 -> 打开的文件
+	mutex     *sync.Mutex
+	queue     chan *trackRecord
+	pruneOnce sync.Once
+}
+
+var trackStoreSingleton *TrackStore
+var trackStoreInitOnce sync.Once
+
+func GetTrackStore() *TrackStore {
+	trackStoreInitOnce.Do(func() {
+		trackStoreSingleton = newTrackStore(defaultTrackDir)
+	})
+	return trackStoreSingleton
+}
+
+func newTrackStore(dir string) *TrackStore {
+	s := &TrackStore{
+		dir:   dir,
+		files: make(map[string]*trackFile),
+		mutex: &sync.Mutex{},
+		queue: make(chan *trackRecord, trackQueueSize),
+	}
+	go s.run()
+	return s
+}
+
+// 设置是否开启、存储目录和保留天数，保留天数大于0时每天清理过期文件
+func (s *TrackStore) Configure(enable bool, dir string, retentionDays int) {
+	s.Flush()
+	s.mutex.Lock()
+	s.disabled = !enable
+	if dir != "" && dir != s.dir {
+		s.closeFiles()
+		s.dir = dir
+	}
+	s.retention = time.Duration(retentionDays) * 24 * time.Hour
+	s.mutex.Unlock()
+	if retentionDays > 0 {
+		s.pruneOnce.Do(func() { go s.autoPrune() })
+	}
+}
+
+func (s *TrackStore) autoPrune() {
+	for {
+		s.mutex.Lock()
+		before := time.Now().Add(-s.retention)
+		s.mutex.Unlock()
+		if err := s.Prune(before); err != nil {
+			slog.Error(wrapError(err, "prune track failed").Error())
+		}
+		time.Sleep(24 * time.Hour)
+	}
+}
+
+func (s *TrackStore) deviceDir(phone string) (string, error) {
+	if phone == "" || strings.ContainsAny(phone, `/\.`) {
+		return "", ErrInvalidTrackPhone
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return filepath.Join(s.dir, phone), nil
+}
+
+// 追加轨迹点，未开启时忽略。写入队列已满时返回ErrTrackQueueFull
+func (s *TrackStore) Append(phone string, p *model.TrackPoint) error {
+	dir, err := s.deviceDir(phone)
+	if err != nil {
+		return err
+	}
+	s.mutex.Lock()
+	disabled := s.disabled
+	s.mutex.Unlock()
+	if disabled {
+		return nil
+	}
+	line, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	select {
+	case s.queue <- &trackRecord{path: filepath.Join(dir, p.Time.Format(trackDayLayout)+trackFileExt), line: line}:
+		return nil
+	default:
+		return ErrTrackQueueFull
+	}
+}
+
+// 等待已追加的轨迹点写入文件
+func (s *TrackStore) Flush() {
+	done := make(chan struct{})
+	s.queue <- &trackRecord{done: done}
+	<-done
+}
+
+// 刷新并关闭所有打开的文件，用于退出前
+func (s *TrackStore) Close() {
+	s.Flush()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closeFiles()
+}
+
+// 写入协程，串行处理队列中的轨迹点，定时刷新缓冲并关闭空闲文件
+func (s *TrackStore) run() {
+	ticker := time.NewTicker(trackFlushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case r := <-s.queue:
+			if r.done != nil {
+				s.flushFiles(false)
+				close(r.done)
+				continue
+			}
+			if err := s.write(r); err != nil {
+				slog.Error(err.Error(), slog.String("path", r.path))
+			}
+		case <-ticker.C:
+			s.flushFiles(true)
+		}
+	}
+}
+
+func (s *TrackStore) write(r *trackRecord) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	tf, ok := s.files[r.path]
+	if !ok {
+		if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+			return wrapError(err, "create track directory failed")
+		}
+		f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return wrapError(err, "open track file failed")
+		}
+		tf = &trackFile{f: f, w: bufio.NewWriter(f)}
+		s.files[r.path] = tf
+	}
+	tf.lastWrite = time.Now()
+	if _, err := tf.w.Write(r.line); err != nil {
+		return wrapError(err, "write track file failed")
+	}
+	return nil
+}
+
+// 刷新所有文件的缓冲，closeIdle为true时关闭空闲的文件
+func (s *TrackStore) flushFiles(closeIdle bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for path, tf := range s.files {
+		if err := tf.w.Flush(); err != nil {
+			slog.Error(wrapError(err, "flush track file failed").Error(), slog.String("path", path))
+		}
+		if closeIdle && time.Since(tf.lastWrite) > trackIdleTimeout {
+			_ = tf.f.Close()
+			delete(s.files, path)
+		}
+	}
+}
+
+// 刷新并关闭所有文件，调用方持有锁
+func (s *TrackStore) closeFiles() {
+	for path, tf := range s.files {
+		if err := tf.w.Flush(); err != nil {
+			slog.Error(wrapError(err, "flush track file failed").Error(), slog.String("path", path))
+		}
+		_ = tf.f.Close()
+		delete(s.files, path)
+	}
+}
+
+// 按日期顺序遍历时间在[from, to)内的轨迹点，fn返回错误时停止遍历并返回该错误。
+// 损坏的行被跳过
+func (s *TrackStore) Iterate(phone string, from, to time.Time, fn func(p *model.TrackPoint) error) error {
+	dir, err := s.deviceDir(phone)
+	if err != nil {
+		return err
+	}
+	s.Flush()
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var days []string
+	fromDay, toDay := from.Format(trackDayLayout), to.Format(trackDayLayout)
+	for _, e := range entries {
+		day := strings.TrimSuffix(e.Name(), trackFileExt)
+		if e.IsDir() || day == e.Name() || day < fromDay || day > toDay {
+			continue
+		}
+		days = append(days, day)
+	}
+	sort.Strings(days)
+
+	for _, day := range days {
+		if err := iterateFile(filepath.Join(dir, day+trackFileExt), from, to, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func iterateFile(path string, from, to time.Time, fn func(p *model.TrackPoint) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		p := &model.TrackPoint{}
+		if err := json.Unmarshal(scanner.Bytes(), p); err != nil {
+			continue
+		}
+		if p.Time.Before(from) || !p.Time.Before(to) {
+			continue
+		}
+		if err := fn(p); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// 删除日期早于before的轨迹文件
+func (s *TrackStore) Prune(before time.Time) error {
+	s.Flush()
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.closeFiles() // 待删除的文件可能仍被打开
+	phones, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	beforeDay := before.Format(trackDayLayout)
+	for _, phone := range phones {
+		if !phone.IsDir() {
+			continue
+		}
+		dir := filepath.Join(s.dir, phone.Name())
+		files, err := os.ReadDir(dir)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			day := strings.TrimSuffix(f.Name(), trackFileExt)
+			if day != f.Name() && day < beforeDay {
+				if err := os.Remove(filepath.Join(dir, f.Name())); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
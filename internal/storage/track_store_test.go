@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+func TestTrackStore_AppendIterate(t *testing.T) {
+	store := newTrackStore(t.TempDir())
+	phone := "13900000048"
+	day := time.Date(2024, 5, 1, 23, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		p := &model.TrackPoint{Time: day.Add(time.Duration(i) * 30 * time.Minute), Speed: float64(i)}
+		require.NoError(t, store.Append(phone, p))
+	}
+	// 损坏的行被跳过
+	store.Flush()
+	f, err := os.OpenFile(filepath.Join(store.dir, phone, "2024-05-01.jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	require.NoError(t, err)
+	_, err = f.WriteString("{broken\n")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		want     []float64
+	}{
+		{name: "all", from: day, to: day.Add(2 * time.Hour), want: []float64{0, 1, 2, 3}},
+		{name: "cross day", from: day.Add(30 * time.Minute), to: day.Add(90 * time.Minute), want: []float64{1, 2}},
+		{name: "next day", from: day.Add(time.Hour), to: day.Add(48 * time.Hour), want: []float64{2, 3}},
+		{name: "empty", from: day.Add(-time.Hour), to: day, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []float64
+			err := store.Iterate(phone, tt.from, tt.to, func(p *model.TrackPoint) error {
+				got = append(got, p.Speed)
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	require.NoError(t, store.Iterate("13900000000", day, day.Add(time.Hour), func(*model.TrackPoint) error {
+		t.Fatal("unexpected point")
+		return nil
+	}))
+	assert.ErrorIs(t, store.Append("../x", &model.TrackPoint{Time: day}), ErrInvalidTrackPhone)
+}
+
+func TestTrackStore_Prune(t *testing.T) {
+	store := newTrackStore(t.TempDir())
+	phone := "13900000048"
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	require.NoError(t, store.Append(phone, &model.TrackPoint{Time: day}))
+	require.NoError(t, store.Append(phone, &model.TrackPoint{Time: day.AddDate(0, 0, 1)}))
+
+	require.NoError(t, store.Prune(day.AddDate(0, 0, 1)))
+	files, err := os.ReadDir(filepath.Join(store.dir, phone))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+	assert.Equal(t, "2024-05-02.jsonl", files[0].Name())
+}
+
+func TestTrackStore_Buffered(t *testing.T) {
+	store := newTrackStore(t.TempDir())
+	phone := "13900000048"
+	day := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	path := filepath.Join(store.dir, phone, "2024-05-01.jsonl")
+
+	tests := []struct {
+		name    string
+		enable  bool
+		wantLen int
+	}{
+		{name: "disabled", enable: false, wantLen: 0},
+		{name: "enabled", enable: true, wantLen: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store.Configure(tt.enable, "", 0)
+			require.NoError(t, store.Append(phone, &model.TrackPoint{Time: day}))
+			require.NoError(t, store.Append(phone, &model.TrackPoint{Time: day.Add(time.Minute)}))
+			store.Close()
+			data, err := os.ReadFile(path)
+			if tt.wantLen == 0 {
+				assert.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLen, bytes.Count(data, []byte("\n")))
+		})
+	}
+}
+
+func BenchmarkTrackStore_Append(b *testing.B) {
+	store := newTrackStore(b.TempDir())
+	p := &model.TrackPoint{Time: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC), Latitude: 39.9, Longitude: 116.4}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for errors.Is(store.Append("1390000"+strconv.Itoa(i%1000), p), ErrTrackQueueFull) {
+			store.Flush()
+		}
+	}
+	store.Flush()
+}
@@ -0,0 +1,116 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+// 每台设备保留的已完成行程数及行程外怠速数，超出时丢弃最早的记录
+const TripRetention = 1000
+
+// 已完成行程及行程外怠速缓存，按设备保存，按开始时间升序
+type TripCache struct {
+	CacheByPhone map[string][]*model.Trip
+	IdleByPhone  map[string][]*model.IdlePeriod
+	mutex        *sync.Mutex
+	updated      bool
+}
+
+var tripCacheSingleton *TripCache
+var tripCacheInitOnce sync.Once
+
+func GetTripCache() *TripCache {
+	tripCacheInitOnce.Do(func() {
+		tripCacheSingleton = &TripCache{
+			CacheByPhone: make(map[string][]*model.Trip),
+			IdleByPhone:  make(map[string][]*model.IdlePeriod),
+			mutex:        &sync.Mutex{},
+		}
+		NewPersister("trip.json", tripCacheSingleton) //启动自动持久化
+	})
+	return tripCacheSingleton
+}
+
+func (cache *TripCache) Lock() {
+	cache.mutex.Lock()
+}
+func (cache *TripCache) Unlock() {
+	cache.mutex.Unlock()
+}
+func (cache *TripCache) IsUpdated() bool {
+	return cache.updated
+}
+
+func (cache *TripCache) AddTrip(t *model.Trip) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.updated = true
+	trips := append(cache.CacheByPhone[t.Phone], t)
+	sort.SliceStable(trips, func(i, j int) bool { return trips[i].StartTime.Before(trips[j].StartTime) })
+	if len(trips) > TripRetention {
+		trips = trips[len(trips)-TripRetention:]
+	}
+	cache.CacheByPhone[t.Phone] = trips
+}
+
+// 开始时间在[from, to)内的行程，零值表示不限
+func (cache *TripCache) ListTrips(phone string, from, to time.Time) []*model.Trip {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	res := make([]*model.Trip, 0)
+	for _, t := range cache.CacheByPhone[phone] {
+		if !from.IsZero() && t.StartTime.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !t.StartTime.Before(to) {
+			continue
+		}
+		res = append(res, t.Clone())
+	}
+	return res
+}
+
+func (cache *TripCache) AddIdle(p *model.IdlePeriod) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.updated = true
+	if cache.IdleByPhone == nil { // 旧版本持久化文件中没有怠速
+		cache.IdleByPhone = make(map[string][]*model.IdlePeriod)
+	}
+	idles := append(cache.IdleByPhone[p.Phone], p)
+	sort.SliceStable(idles, func(i, j int) bool { return idles[i].StartTime.Before(idles[j].StartTime) })
+	if len(idles) > TripRetention {
+		idles = idles[len(idles)-TripRetention:]
+	}
+	cache.IdleByPhone[p.Phone] = idles
+}
+
+// 开始时间在[from, to)内的行程外怠速，零值表示不限
+func (cache *TripCache) ListIdles(phone string, from, to time.Time) []*model.IdlePeriod {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	res := make([]*model.IdlePeriod, 0)
+	for _, p := range cache.IdleByPhone[phone] {
+		if !from.IsZero() && p.StartTime.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !p.StartTime.Before(to) {
+			continue
+		}
+		idle := *p
+		res = append(res, &idle)
+	}
+	return res
+}
+
+// 删除设备的行程及行程外怠速
+func (cache *TripCache) DelTripsByPhone(phone string) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.updated = true
+	delete(cache.CacheByPhone, phone)
+	delete(cache.IdleByPhone, phone)
+}
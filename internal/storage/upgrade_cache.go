@@ -0,0 +1,148 @@
+package storage
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+var ErrUpgradeTaskNotFound = errors.New("upgrade task not found")
+
+// 升级任务缓存。任务由下发协程和0x0108处理并发修改，读取时返回拷贝
+type UpgradeCache struct {
+	CacheByID map[string]*model.UpgradeTask
+	mutex     *sync.Mutex
+	updated   bool
+}
+
+var upgradeCacheSingleton *UpgradeCache
+var upgradeCacheInitOnce sync.Once
+
+func GetUpgradeCache() *UpgradeCache {
+	upgradeCacheInitOnce.Do(func() {
+		upgradeCacheSingleton = &UpgradeCache{
+			CacheByID: make(map[string]*model.UpgradeTask),
+			mutex:     &sync.Mutex{},
+		}
+		NewPersister("upgrade_task.json", upgradeCacheSingleton) //启动自动持久化
+	})
+	return upgradeCacheSingleton
+}
+
+func (cache *UpgradeCache) Lock() {
+	cache.mutex.Lock()
+}
+func (cache *UpgradeCache) Unlock() {
+	cache.mutex.Unlock()
+}
+func (cache *UpgradeCache) IsUpdated() bool {
+	return cache.updated
+}
+
+func (cache *UpgradeCache) CacheTask(t *model.UpgradeTask) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	cache.updated = true
+	cache.CacheByID[t.ID] = t
+}
+
+func (cache *UpgradeCache) GetTask(id string) (*model.UpgradeTask, error) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	if t, ok := cache.CacheByID[id]; ok {
+		return t.Clone(), nil
+	}
+	return nil, ErrUpgradeTaskNotFound
+}
+
+// 按创建时间倒序返回所有任务
+func (cache *UpgradeCache) ListTask() []*model.UpgradeTask {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	res := make([]*model.UpgradeTask, 0, len(cache.CacheByID))
+	for _, t := range cache.CacheByID {
+		res = append(res, t.Clone())
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].CreatedAt.After(res[j].CreatedAt) })
+	return res
+}
+
+// 在锁内修改任务，fn返回error时不标记更新
+func (cache *UpgradeCache) UpdateTask(id string, fn func(t *model.UpgradeTask) error) error {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	t, ok := cache.CacheByID[id]
+	if !ok {
+		return ErrUpgradeTaskNotFound
+	}
+	if err := fn(t); err != nil {
+		return err
+	}
+	cache.updated = true
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// 修改任务中单个设备的升级状态
+func (cache *UpgradeCache) UpdateDevice(id, phone string, fn func(d *model.DeviceUpgrade)) error {
+	return cache.UpdateTask(id, func(t *model.UpgradeTask) error {
+		for _, d := range t.Devices {
+			if d.Phone == phone {
+				fn(d)
+				d.UpdatedAt = time.Now()
+				return nil
+			}
+		}
+		return ErrDeviceNotFound
+	})
+}
+
+// 记录终端上报的升级结果，更新最近一个已向该设备下发升级包且未结束的任务，返回任务ID
+func (cache *UpgradeCache) ReportResult(phone string, result uint8) (string, bool) {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	var task *model.UpgradeTask
+	var device *model.DeviceUpgrade
+	for _, t := range cache.CacheByID {
+		for _, d := range t.Devices {
+			if d.Phone != phone || (d.State != model.DeviceUpgradeSent && d.State != model.DeviceUpgradeSending) {
+				continue
+			}
+			if task == nil || t.CreatedAt.After(task.CreatedAt) {
+				task, device = t, d
+			}
+		}
+	}
+	if task == nil {
+		return "", false
+	}
+	switch result {
+	case model.UpgradeResultSuccess:
+		device.State = model.DeviceUpgradeSucceeded
+	case model.UpgradeResultCanceled:
+		device.State = model.DeviceUpgradeCanceled
+	default:
+		device.State = model.DeviceUpgradeFailed
+		device.Error = "terminal reported upgrade failure"
+	}
+	now := time.Now()
+	device.UpdatedAt = now
+	task.UpdatedAt = now
+	cache.updated = true
+	return task.ID, true
+}
+
+// 升级包是否被未结束的任务使用
+func (cache *UpgradeCache) IsFirmwareInUse(firmwareID string) bool {
+	cache.mutex.Lock()
+	defer cache.mutex.Unlock()
+	for _, t := range cache.CacheByID {
+		if t.FirmwareID == firmwareID && (t.State == model.UpgradeTaskRunning || t.State == model.UpgradeTaskPaused) {
+			return true
+		}
+	}
+	return false
+}
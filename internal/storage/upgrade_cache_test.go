@@ -0,0 +1,36 @@
+package storage
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+func TestUpgradeCache_ReportResult(t *testing.T) {
+	cache := GetUpgradeCache()
+	phone := "13900000109"
+	now := time.Now()
+	older := &model.UpgradeTask{ID: "up-test-1", State: model.UpgradeTaskCompleted, CreatedAt: now.Add(-time.Hour),
+		Devices: []*model.DeviceUpgrade{{Phone: phone, State: model.DeviceUpgradeSent}}}
+	newer := &model.UpgradeTask{ID: "up-test-2", State: model.UpgradeTaskRunning, CreatedAt: now,
+		Devices: []*model.DeviceUpgrade{{Phone: phone, State: model.DeviceUpgradeSent}}}
+	cache.CacheTask(older)
+	cache.CacheTask(newer)
+
+	id, ok := cache.ReportResult(phone, model.UpgradeResultFail)
+	require.True(t, ok)
+	assert.Equal(t, newer.ID, id)
+	got, err := cache.GetTask(newer.ID)
+	require.NoError(t, err)
+	assert.Equal(t, model.DeviceUpgradeFailed, got.Devices[0].State)
+
+	id, ok = cache.ReportResult(phone, model.UpgradeResultSuccess)
+	require.True(t, ok)
+	assert.Equal(t, older.ID, id)
+	_, ok = cache.ReportResult(phone, model.UpgradeResultSuccess)
+	assert.False(t, ok)
+}
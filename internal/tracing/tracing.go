@@ -0,0 +1,112 @@
+// Package tracing 基于OpenTelemetry追踪终端消息在pipeline中的处理过程。
+//
+// 默认使用otel全局的no-op TracerProvider，配置server.tracing.enable后通过OTLP/HTTP导出。
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/fakeyanss/jt808-server-go/internal/config"
+)
+
+const (
+	instrumentationName = "github.com/fakeyanss/jt808-server-go"
+	defaultServiceName  = "jt808-server-go"
+)
+
+// span attribute keys
+const (
+	AttrPhone   = attribute.Key("jt808.phone")
+	AttrMsgID   = attribute.Key("jt808.msg_id")
+	AttrSerial  = attribute.Key("jt808.serial_number")
+	AttrVersion = attribute.Key("jt808.version")
+	AttrResult  = attribute.Key("jt808.result")
+	AttrSession = attribute.Key("jt808.session")
+)
+
+var provider *sdktrace.TracerProvider
+
+// 初始化全局TracerProvider，未开启时保持otel默认的no-op实现
+func Init(cfg *config.TracingConf) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	if cfg == nil || !cfg.Enable {
+		return nil
+	}
+
+	opts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		opts = append(opts, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(context.Background(), opts...)
+	if err != nil {
+		return errors.Wrap(err, "Fail to create otlp trace exporter")
+	}
+
+	serviceName := cfg.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+	ratio := cfg.SampleRatio
+	if ratio <= 0 || ratio > 1 {
+		ratio = 1
+	}
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+	)
+	otel.SetTracerProvider(provider)
+	return nil
+}
+
+// 刷新并关闭exporter
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// 消息头相关的span属性
+func MsgAttrs(phone string, msgID, serialNumber uint16, version string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		AttrPhone.String(phone),
+		AttrMsgID.String(fmt.Sprintf("0x%04x", msgID)),
+		AttrSerial.Int(int(serialNumber)),
+		AttrVersion.String(version),
+	}
+}
+
+// 根据处理结果结束span
+func End(span trace.Span, err error, result string) {
+	if result != "" {
+		span.SetAttributes(AttrResult.String(result))
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// 将trace上下文注入到对外的http请求头，webhook/sink等对外调用应使用此方法以延续trace
+func InjectHTTPHeader(ctx context.Context, header http.Header) {
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 替换全局TracerProvider，测试结束后恢复
+func useTestProvider(t *testing.T, tp *sdktrace.TracerProvider) {
+	prevGlobal, prevProvider := otel.GetTracerProvider(), provider
+	otel.SetTracerProvider(tp)
+	provider = tp
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevGlobal)
+		provider = prevProvider
+	})
+}
+
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+// 记录导出的span，与tracetest.InMemoryExporter不同，Shutdown后不清空
+type recordExporter struct {
+	mutex sync.Mutex
+	spans []sdktrace.ReadOnlySpan
+}
+
+func (e *recordExporter) ExportSpans(_ context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	e.spans = append(e.spans, spans...)
+	return nil
+}
+
+func (e *recordExporter) Shutdown(context.Context) error { return nil }
+
+func (e *recordExporter) Spans() []sdktrace.ReadOnlySpan {
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+	return e.spans
+}
+
+func TestShutdown_FlushBatchedSpans(t *testing.T) {
+	exporter := &recordExporter{}
+	useTestProvider(t, sdktrace.NewTracerProvider(sdktrace.WithBatcher(exporter)))
+
+	_, span := Tracer().Start(context.Background(), "jt808.uplink")
+	End(span, nil, "ok")
+	assert.Empty(t, exporter.Spans()) // 批量导出，尚未刷新
+
+	require.NoError(t, Shutdown(context.Background()))
+	spans := exporter.Spans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "jt808.uplink", spans[0].Name())
+}
+
+func TestMiddleware(t *testing.T) {
+	require.NoError(t, Init(nil)) // 设置propagator
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		target     string
+		wantName   string
+		wantStatus int
+		wantCode   codes.Code
+	}{
+		{name: "case1: matched route", target: "/device/123", wantName: "/device/:phone", wantStatus: http.StatusOK, wantCode: codes.Unset},
+		{name: "case2: server error", target: "/fail", wantName: "/fail", wantStatus: http.StatusInternalServerError, wantCode: codes.Error},
+		{name: "case3: route not found", target: "/missing", wantName: "HTTP GET route not found", wantStatus: http.StatusNotFound, wantCode: codes.Unset},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := tracetest.NewSpanRecorder()
+			useTestProvider(t, sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+			var handlerSpan trace.SpanContext
+			router := gin.New()
+			router.Use(Middleware())
+			router.GET("/device/:phone", func(c *gin.Context) {
+				handlerSpan = trace.SpanContextFromContext(c.Request.Context())
+				c.Status(http.StatusOK)
+			})
+			router.GET("/fail", func(c *gin.Context) {
+				c.Status(http.StatusInternalServerError)
+			})
+
+			parent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+			req := httptest.NewRequest(http.MethodGet, tt.target, http.NoBody)
+			req.Header.Set("traceparent", parent)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			require.Equal(t, tt.wantStatus, w.Code)
+
+			spans := recorder.Ended()
+			require.Len(t, spans, 1)
+			span := spans[0]
+			assert.Equal(t, tt.wantName, span.Name())
+			assert.Equal(t, trace.SpanKindServer, span.SpanKind())
+			assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+			assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+			assert.Equal(t, int64(tt.wantStatus), spanAttr(span, semconv.HTTPStatusCodeKey).AsInt64())
+			assert.Equal(t, tt.wantCode, span.Status().Code)
+			if handlerSpan.IsValid() {
+				assert.Equal(t, span.SpanContext().SpanID(), handlerSpan.SpanID())
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+// Package trip 按位置汇报切分行程。
+//
+// 车辆速度达到MovingSpeed时开始行程；ACC由开变关、静止超过ParkDuration
+// 或两次汇报间隔超过GapDuration时结束行程。行程终点为最后一个行驶点或停车开始的位置，
+// 结束前的静止时间不计入行程时长。行程中静止超过StopDuration记为一次停车，
+// 停车期间ACC一直开启的记为怠速停车；ACC开且静止的时间累计为怠速时长。
+// 行程结束时仍在静止的，这段静止作为行程最后一次停车记录到行程结束时刻，其怠速计入行程。
+//
+// 不在行程中时，ACC开且静止的时段记为行程外的怠速，
+// 在开始行驶、ACC关闭或汇报间隔超过GapDuration时结束。
+package trip
+
+import (
+	"sync"
+	"time"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+	"github.com/fakeyanss/jt808-server-go/internal/quality"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+)
+
+const (
+	MovingSpeed  float64 = 5 // km/h，低于该速度视为静止
+	StopDuration         = 3 * time.Minute
+	ParkDuration         = 10 * time.Minute
+	GapDuration          = 10 * time.Minute
+)
+
+type point struct {
+	time     time.Time
+	loc      geo.Point
+	acc      uint8
+	fixed    bool
+	moving   bool
+	received bool
+}
+
+type deviceState struct {
+	last point
+	trip *model.Trip
+
+	startMileage *float64
+	endMileage   *float64
+
+	stationarySince time.Time // 行程中静止开始时间，行驶中为零值
+	stationaryLoc   geo.Point
+	idling          bool          // 静止期间ACC一直开启
+	pendingIdle     time.Duration // 本次静止期间的怠速时长，恢复行驶或行程结束时计入行程
+
+	idleSince time.Time // 行程外怠速开始时间，未怠速为零值
+	idleLoc   geo.Point
+	idleLast  time.Time // 行程外怠速的最后一个汇报时间
+}
+
+type analyzer struct {
+	mutex    sync.Mutex
+	states   map[string]*deviceState
+	save     func(*model.Trip)
+	saveIdle func(*model.IdlePeriod)
+}
+
+var defaultAnalyzer = &analyzer{
+	states:   make(map[string]*deviceState),
+	save:     func(t *model.Trip) { storage.GetTripCache().AddTrip(t) },
+	saveIdle: func(p *model.IdlePeriod) { storage.GetTripCache().AddIdle(p) },
+}
+
+// 处理位置汇报，已完成的行程和行程外怠速写入行程缓存，返回已完成的行程。乱序的汇报被忽略
+func Feed(dg *model.DeviceGeo) []*model.Trip {
+	return defaultAnalyzer.feed(dg)
+}
+
+// 设备进行中的行程，没有时返回nil
+func Current(phone string) *model.Trip {
+	return defaultAnalyzer.current(phone)
+}
+
+// 清除设备的行程状态，进行中的行程被丢弃
+func Forget(phone string) {
+	defaultAnalyzer.mutex.Lock()
+	defer defaultAnalyzer.mutex.Unlock()
+	delete(defaultAnalyzer.states, phone)
+}
+
+func (a *analyzer) current(phone string) *model.Trip {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	if st, ok := a.states[phone]; ok && st.trip != nil {
+		return st.trip.Clone()
+	}
+	return nil
+}
+
+func (a *analyzer) feed(dg *model.DeviceGeo) []*model.Trip {
+	if dg.Geo == nil {
+		return nil
+	}
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	st, ok := a.states[dg.Phone]
+	if !ok {
+		st = &deviceState{}
+		a.states[dg.Phone] = st
+	}
+	if st.last.received && !dg.Time.After(st.last.time) {
+		return nil
+	}
+
+	cur := point{time: dg.Time, acc: dg.Geo.ACCStatus, received: true}
+	cur.fixed = quality.IsFixed(dg)
+	if cur.fixed {
+		cur.loc = geo.Point{Lat: dg.Location.Latitude, Lng: dg.Location.Longitude}
+	}
+	if dg.Drive != nil {
+		cur.moving = dg.Drive.Speed >= MovingSpeed
+	}
+
+	var done []*model.Trip
+	var idles []*model.IdlePeriod
+	finish := func(end time.Time) {
+		if t := st.finish(end); t != nil {
+			done = append(done, t)
+		}
+	}
+	endIdle := func(end time.Time) {
+		if p := st.endIdle(dg.Phone, end); p != nil {
+			idles = append(idles, p)
+		}
+	}
+
+	if dg.Time.Sub(st.last.time) > GapDuration {
+		if st.trip != nil {
+			finish(st.last.time)
+		}
+		if !st.idleSince.IsZero() {
+			endIdle(st.idleLast)
+		}
+	}
+
+	if cur.fixed {
+		switch {
+		case st.trip == nil:
+			if cur.moving {
+				st.start(dg, cur)
+			}
+		case cur.moving:
+			st.resume(dg, cur)
+		default:
+			if st.halt(dg, cur) >= ParkDuration {
+				finish(cur.time)
+			}
+		}
+	}
+	if st.trip != nil && st.last.acc == 1 && cur.acc == 0 {
+		finish(cur.time)
+	}
+
+	switch {
+	case st.trip == nil && cur.fixed && !cur.moving && cur.acc == 1:
+		if st.idleSince.IsZero() {
+			st.idleSince, st.idleLoc = cur.time, cur.loc
+		}
+		st.idleLast = cur.time
+	case !st.idleSince.IsZero() && (cur.fixed || cur.acc == 0): // 开始行驶或ACC关闭
+		endIdle(cur.time)
+	}
+
+	if cur.fixed || !st.last.fixed {
+		st.last = cur
+	} else { // 未定位的汇报只更新时间和ACC，保留最后的定位点用于计算距离
+		st.last.time, st.last.acc = cur.time, cur.acc
+	}
+
+	for _, t := range done {
+		a.save(t)
+	}
+	for _, p := range idles {
+		a.saveIdle(p)
+	}
+	return done
+}
+
+func (st *deviceState) start(dg *model.DeviceGeo, cur point) {
+	st.trip = &model.Trip{
+		Phone:         dg.Phone,
+		StartTime:     cur.time,
+		StartLocation: cur.loc,
+		Stops:         make([]*model.TripStop, 0),
+	}
+	st.startMileage, st.endMileage = dg.Mileage, nil
+	st.stationarySince = time.Time{}
+	st.extend(dg, cur)
+}
+
+// 行驶中的点，结束可能的静止
+func (st *deviceState) resume(dg *model.DeviceGeo, cur point) {
+	if !st.stationarySince.IsZero() {
+		st.trip.IdleDuration += st.pendingIdle
+		if cur.time.Sub(st.stationarySince) >= StopDuration {
+			st.trip.Stops = append(st.trip.Stops, &model.TripStop{
+				StartTime: st.stationarySince,
+				EndTime:   cur.time,
+				Location:  st.stationaryLoc,
+				Idling:    st.idling,
+			})
+		}
+		st.stationarySince = time.Time{}
+	}
+	st.extend(dg, cur)
+}
+
+// 静止的点，返回已静止的时长
+func (st *deviceState) halt(dg *model.DeviceGeo, cur point) time.Duration {
+	if st.stationarySince.IsZero() {
+		st.stationarySince = cur.time
+		st.stationaryLoc = cur.loc
+		st.idling = cur.acc == 1
+		st.pendingIdle = 0
+		st.extend(dg, cur)
+		return 0
+	}
+	if st.last.acc == 1 && cur.acc == 1 {
+		st.pendingIdle += cur.time.Sub(st.last.time)
+	}
+	st.idling = st.idling && cur.acc == 1
+	return cur.time.Sub(st.stationarySince)
+}
+
+// 将点计入行程终点，累计距离和最高速度
+func (st *deviceState) extend(dg *model.DeviceGeo, cur point) {
+	t := st.trip
+	if t.Points > 0 && st.last.fixed {
+		t.Distance += geo.Distance(st.last.loc, cur.loc)
+	}
+	t.Points++
+	t.EndTime = cur.time
+	t.EndLocation = cur.loc
+	if dg.Drive != nil && dg.Drive.Speed > t.MaxSpeed {
+		t.MaxSpeed = dg.Drive.Speed
+	}
+	if dg.Mileage != nil {
+		st.endMileage = dg.Mileage
+	}
+}
+
+// 在end时刻结束行程，进行中的静止计入行程最后一次停车。只有起点的行程被丢弃
+func (st *deviceState) finish(end time.Time) *model.Trip {
+	t := st.trip
+	st.trip = nil
+	if t != nil && !st.stationarySince.IsZero() {
+		t.IdleDuration += st.pendingIdle
+		if end.Sub(st.stationarySince) >= StopDuration {
+			t.Stops = append(t.Stops, &model.TripStop{
+				StartTime: st.stationarySince,
+				EndTime:   end,
+				Location:  st.stationaryLoc,
+				Idling:    st.idling,
+			})
+		}
+	}
+	st.stationarySince = time.Time{}
+	if t == nil || !t.EndTime.After(t.StartTime) {
+		return nil
+	}
+	if st.startMileage != nil && st.endMileage != nil && *st.endMileage >= *st.startMileage {
+		mileage := *st.endMileage - *st.startMileage
+		t.Mileage = &mileage
+	}
+	st.startMileage, st.endMileage = nil, nil
+	return t
+}
+
+// 在end时刻结束行程外的怠速，时长为零的被丢弃
+func (st *deviceState) endIdle(phone string, end time.Time) *model.IdlePeriod {
+	p := &model.IdlePeriod{Phone: phone, StartTime: st.idleSince, EndTime: end, Location: st.idleLoc}
+	st.idleSince, st.idleLast = time.Time{}, time.Time{}
+	if !p.EndTime.After(p.StartTime) {
+		return nil
+	}
+	return p
+}
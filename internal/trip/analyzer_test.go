@@ -0,0 +1,296 @@
+package trip
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/fakeyanss/jt808-server-go/internal/codec/geo"
+	"github.com/fakeyanss/jt808-server-go/internal/protocol/model"
+)
+
+const phone = "13800000044"
+
+var t0 = time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+// 沿赤道从东经100度向东，lng为相对经度，每0.001度约111米
+func newGeo(minute float64, lng, speed float64, acc uint8) *model.DeviceGeo {
+	p := pointAt(lng)
+	return &model.DeviceGeo{
+		Phone:    phone,
+		Geo:      &model.GeoMeta{LocationStatus: 1, ACCStatus: acc},
+		Location: &model.Location{Latitude: p.Lat, Longitude: p.Lng},
+		Drive:    &model.Drive{Speed: speed},
+		Time:     t0.Add(time.Duration(minute * float64(time.Minute))),
+	}
+}
+
+func withMileage(dg *model.DeviceGeo, km float64) *model.DeviceGeo {
+	dg.Mileage = &km
+	return dg
+}
+
+func newTestAnalyzer() (*analyzer, *[]*model.Trip, *[]*model.IdlePeriod) {
+	saved := &[]*model.Trip{}
+	idles := &[]*model.IdlePeriod{}
+	return &analyzer{
+		states:   make(map[string]*deviceState),
+		save:     func(t *model.Trip) { *saved = append(*saved, t) },
+		saveIdle: func(p *model.IdlePeriod) { *idles = append(*idles, p) },
+	}, saved, idles
+}
+
+func pointAt(lng float64) geo.Point {
+	return geo.Point{Lng: 100 + lng}
+}
+
+func minute(m float64) time.Time {
+	return t0.Add(time.Duration(m * float64(time.Minute)))
+}
+
+func feedAll(a *analyzer, geos ...*model.DeviceGeo) {
+	for _, dg := range geos {
+		a.feed(dg)
+	}
+}
+
+func TestAnalyzer_Segmentation(t *testing.T) {
+	tests := []struct {
+		name      string
+		geos      []*model.DeviceGeo
+		wantTrips int
+		wantEnd   float64 // 第一个行程结束的分钟
+	}{
+		{
+			name: "acc off",
+			geos: []*model.DeviceGeo{
+				newGeo(0, 0, 0, 1), newGeo(1, 0.001, 30, 1), newGeo(2, 0.002, 30, 1), newGeo(3, 0.003, 0, 0),
+			},
+			wantTrips: 1, wantEnd: 3,
+		},
+		{
+			name: "parked",
+			geos: []*model.DeviceGeo{
+				newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1), newGeo(2, 0.002, 0, 1),
+				newGeo(8, 0.002, 0, 1), newGeo(12, 0.002, 0, 1), newGeo(13, 0.002, 0, 1),
+			},
+			wantTrips: 1, wantEnd: 2,
+		},
+		{
+			name: "report gap",
+			geos: []*model.DeviceGeo{
+				newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1), newGeo(20, 0.1, 30, 1), newGeo(21, 0.101, 30, 1),
+			},
+			wantTrips: 1, wantEnd: 1,
+		},
+		{
+			name:      "ongoing",
+			geos:      []*model.DeviceGeo{newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1)},
+			wantTrips: 0,
+		},
+		{
+			name:      "never moving",
+			geos:      []*model.DeviceGeo{newGeo(0, 0, 0, 1), newGeo(1, 0, 2, 1), newGeo(2, 0, 0, 0)},
+			wantTrips: 0,
+		},
+		{
+			name: "out of order ignored",
+			geos: []*model.DeviceGeo{
+				newGeo(0, 0, 30, 1), newGeo(2, 0.002, 30, 1), newGeo(1, 0.5, 0, 0), newGeo(3, 0.003, 0, 0),
+			},
+			wantTrips: 1, wantEnd: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, saved, _ := newTestAnalyzer()
+			feedAll(a, tt.geos...)
+			require.Len(t, *saved, tt.wantTrips)
+			if tt.wantTrips > 0 {
+				trip := (*saved)[0]
+				assert.Equal(t, phone, trip.Phone)
+				assert.Equal(t, t0.Add(time.Duration(tt.wantEnd*float64(time.Minute))), trip.EndTime)
+				assert.Less(t, trip.Distance, 400.0)
+			}
+		})
+	}
+}
+
+func TestAnalyzer_StopsAndIdle(t *testing.T) {
+	a, saved, _ := newTestAnalyzer()
+	feedAll(a,
+		withMileage(newGeo(0, 0, 40, 1), 100),
+		newGeo(1, 0.01, 40, 1),
+		newGeo(2, 0.011, 0, 1),
+		newGeo(4, 0.011, 0, 1),
+		newGeo(7, 0.011, 0, 1),
+		newGeo(8, 0.02, 40, 1),
+		newGeo(9, 0.021, 0, 1),
+		newGeo(10, 0.021, 0, 1),
+		withMileage(newGeo(11, 0.03, 60, 1), 103.5),
+	)
+	require.Empty(t, *saved)
+	cur := a.current(phone)
+	require.NotNil(t, cur)
+	assert.Equal(t, 6*time.Minute, cur.IdleDuration)
+	require.Len(t, cur.Stops, 1)
+	assert.Equal(t, t0.Add(2*time.Minute), cur.Stops[0].StartTime)
+	assert.Equal(t, 6*time.Minute, cur.Stops[0].Duration())
+	assert.True(t, cur.Stops[0].Idling)
+	assert.Equal(t, 60.0, cur.MaxSpeed)
+	assert.InDelta(t, 3336, cur.Distance, 5)
+
+	a.feed(newGeo(12, 0.031, 0, 0))
+	require.Len(t, *saved, 1)
+	trip := (*saved)[0]
+	assert.Equal(t, t0.Add(12*time.Minute), trip.EndTime)
+	require.NotNil(t, trip.Mileage)
+	assert.InDelta(t, 3.5, *trip.Mileage, 1e-9)
+	assert.Nil(t, a.current(phone))
+}
+
+func TestAnalyzer_Unfixed(t *testing.T) {
+	a, saved, _ := newTestAnalyzer()
+	feedAll(a, newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1))
+	unfixed := newGeo(2, 0, 0, 0)
+	unfixed.Geo.LocationStatus = 0
+	a.feed(unfixed)
+	require.Len(t, *saved, 1)
+	assert.Equal(t, t0.Add(time.Minute), (*saved)[0].EndTime)
+	assert.InDelta(t, 111, (*saved)[0].Distance, 1)
+
+	// 零坐标的点同样视为未定位，不计入距离
+	a, saved, _ = newTestAnalyzer()
+	zero := newGeo(2, 0, 30, 1)
+	zero.Location.Longitude = 0
+	feedAll(a, newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1), zero, newGeo(3, 0.002, 30, 1))
+	cur := a.current(phone)
+	require.NotNil(t, cur)
+	assert.Equal(t, 3, cur.Points)
+	assert.InDelta(t, 222, cur.Distance, 1)
+}
+
+func TestAnalyzer_TrailingStop(t *testing.T) {
+	tests := []struct {
+		name     string
+		geos     []*model.DeviceGeo
+		wantStop *model.TripStop
+		wantIdle time.Duration
+	}{
+		{
+			name: "parked with acc on",
+			geos: []*model.DeviceGeo{
+				newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1), newGeo(2, 0.002, 0, 1),
+				newGeo(8, 0.002, 0, 1), newGeo(12, 0.002, 0, 1),
+			},
+			wantStop: &model.TripStop{StartTime: minute(2), EndTime: minute(12), Location: pointAt(0.002), Idling: true},
+			wantIdle: 10 * time.Minute,
+		},
+		{
+			name: "acc off after stop",
+			geos: []*model.DeviceGeo{
+				newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1), newGeo(2, 0.002, 0, 1),
+				newGeo(6, 0.002, 0, 1), newGeo(7, 0.002, 0, 0),
+			},
+			wantStop: &model.TripStop{StartTime: minute(2), EndTime: minute(7), Location: pointAt(0.002)},
+			wantIdle: 4 * time.Minute,
+		},
+		{
+			name: "report gap after stop",
+			geos: []*model.DeviceGeo{
+				newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1), newGeo(2, 0.002, 0, 1),
+				newGeo(6, 0.002, 0, 1), newGeo(30, 0.002, 0, 1),
+			},
+			wantStop: &model.TripStop{StartTime: minute(2), EndTime: minute(6), Location: pointAt(0.002), Idling: true},
+			wantIdle: 4 * time.Minute,
+		},
+		{
+			name: "short stop before acc off",
+			geos: []*model.DeviceGeo{
+				newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1), newGeo(2, 0.002, 0, 1), newGeo(3, 0.002, 0, 0),
+			},
+			wantStop: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, saved, _ := newTestAnalyzer()
+			feedAll(a, tt.geos...)
+			require.Len(t, *saved, 1)
+			trip := (*saved)[0]
+			assert.Equal(t, minute(2), trip.EndTime)
+			assert.Equal(t, tt.wantIdle, trip.IdleDuration)
+			if tt.wantStop == nil {
+				assert.Empty(t, trip.Stops)
+				return
+			}
+			require.Len(t, trip.Stops, 1)
+			assert.Equal(t, tt.wantStop, trip.Stops[0])
+		})
+	}
+}
+
+func TestAnalyzer_IdleOutsideTrip(t *testing.T) {
+	unfixedACCOff := newGeo(2, 0, 0, 0)
+	unfixedACCOff.Geo.LocationStatus = 0
+	tests := []struct {
+		name  string
+		geos  []*model.DeviceGeo
+		want  [][2]float64 // 行程外怠速的起止分钟
+		trips int
+	}{
+		{
+			name:  "warm up before trip",
+			geos:  []*model.DeviceGeo{newGeo(0, 0, 0, 1), newGeo(3, 0, 0, 1), newGeo(5, 0.001, 30, 1)},
+			want:  [][2]float64{{0, 5}},
+			trips: 0,
+		},
+		{
+			name:  "acc off",
+			geos:  []*model.DeviceGeo{newGeo(0, 0, 0, 1), newGeo(1, 0, 0, 1), newGeo(4, 0, 0, 0), newGeo(5, 0, 0, 0)},
+			want:  [][2]float64{{0, 4}},
+			trips: 0,
+		},
+		{
+			name:  "acc off without location",
+			geos:  []*model.DeviceGeo{newGeo(0, 0, 0, 1), newGeo(1, 0, 0, 1), unfixedACCOff},
+			want:  [][2]float64{{0, 2}},
+			trips: 0,
+		},
+		{
+			name:  "report gap",
+			geos:  []*model.DeviceGeo{newGeo(0, 0, 0, 1), newGeo(2, 0, 0, 1), newGeo(30, 0, 0, 1), newGeo(31, 0, 0, 0)},
+			want:  [][2]float64{{0, 2}, {30, 31}},
+			trips: 0,
+		},
+		{
+			name: "idling after parked",
+			geos: []*model.DeviceGeo{
+				newGeo(0, 0, 30, 1), newGeo(1, 0.001, 30, 1), newGeo(2, 0.002, 0, 1),
+				newGeo(12, 0.002, 0, 1), newGeo(15, 0.002, 0, 1), newGeo(16, 0.003, 30, 1),
+			},
+			want:  [][2]float64{{12, 16}},
+			trips: 1,
+		},
+		{
+			name:  "acc off all the time",
+			geos:  []*model.DeviceGeo{newGeo(0, 0, 0, 0), newGeo(5, 0, 0, 0)},
+			trips: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, saved, idles := newTestAnalyzer()
+			feedAll(a, tt.geos...)
+			require.Len(t, *saved, tt.trips)
+			require.Len(t, *idles, len(tt.want))
+			for i, want := range tt.want {
+				assert.Equal(t, phone, (*idles)[i].Phone)
+				assert.Equal(t, minute(want[0]), (*idles)[i].StartTime)
+				assert.Equal(t, minute(want[1]), (*idles)[i].EndTime)
+			}
+		})
+	}
+}
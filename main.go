@@ -1,19 +1,31 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/rs/zerolog/log"
 
 	"github.com/fakeyanss/jt808-server-go/internal/api"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/coord"
+	"github.com/fakeyanss/jt808-server-go/internal/codec/region"
 	"github.com/fakeyanss/jt808-server-go/internal/config"
+	"github.com/fakeyanss/jt808-server-go/internal/driving"
+	"github.com/fakeyanss/jt808-server-go/internal/quality"
 	"github.com/fakeyanss/jt808-server-go/internal/server"
+	"github.com/fakeyanss/jt808-server-go/internal/storage"
+	"github.com/fakeyanss/jt808-server-go/internal/tracing"
 	"github.com/fakeyanss/jt808-server-go/pkg/logger"
 	"github.com/fakeyanss/jt808-server-go/pkg/routines"
 )
 
+const shutdownTimeout = 5 * time.Second
+
 func main() {
 	routines.Recover()
 
@@ -26,6 +38,28 @@ func main() {
 	logConfig := cfg.ParseLogConf()
 	log.Logger = *logger.Configure(logConfig).Logger
 
+	if err := tracing.Init(cfg.Server.Tracing); err != nil {
+		log.Error().Err(err).Msg("Fail to init tracing")
+		os.Exit(1)
+	}
+	driving.Configure(cfg.Server.DrivingRule)
+	quality.Configure(cfg.Server.Quality)
+	if tc := cfg.Server.Track; tc != nil {
+		storage.GetTrackStore().Configure(tc.Enable, tc.Dir, tc.RetentionDays)
+	}
+	if rc := cfg.Server.Region; rc != nil && rc.Enable {
+		idx, err := loadRegionIndex(rc)
+		if err != nil {
+			log.Error().Err(err).Msg("Fail to load region boundary")
+			os.Exit(1)
+		}
+		region.SetIndex(idx)
+		log.Info().Int("regions", idx.Len()).Str("file", rc.BoundaryFile).Msg("Region boundary loaded")
+		if idx.Len() == 0 {
+			log.Warn().Msg("Region boundary is empty, run make region-data or set server.region.boundaryFile")
+		}
+	}
+
 	if cfg.Server.Banner.Enable {
 		bannerBytes, err := os.ReadFile(cfg.Server.Banner.BannerPath)
 		var banner string
@@ -37,16 +71,70 @@ func main() {
 		fmt.Println(banner)
 	}
 
-	serv := server.NewTCPServer()
-	addr := ":" + cfg.Server.Port.TCPPort
-	err := serv.Listen(addr)
-	if err != nil {
-		log.Error().Err(err).Str("addr", addr).Msg("Fail to listen tcp addr")
-		os.Exit(1)
+	var certReloader *server.CertReloader
+	if cfg.Server.TLS != nil && cfg.Server.TLS.Enable {
+		var err error
+		certReloader, err = server.NewCertReloader(cfg.Server.TLS)
+		if err != nil {
+			log.Error().Err(err).Msg("Fail to load tls certificate")
+			os.Exit(1)
+		}
+	}
+
+	serv := server.NewTCPServer(cfg)
+	if cfg.Server.Port.TCPPort != "" {
+		addr := ":" + cfg.Server.Port.TCPPort
+		err := serv.Listen(addr)
+		if err != nil {
+			log.Error().Err(err).Str("addr", addr).Msg("Fail to listen tcp addr")
+			os.Exit(1)
+		}
+	}
+	if certReloader != nil && cfg.Server.Port.TCPTLSPort != "" {
+		addr := ":" + cfg.Server.Port.TCPTLSPort
+		err := serv.ListenTLS(addr, certReloader.TerminalTLSConfig())
+		if err != nil {
+			log.Error().Err(err).Str("addr", addr).Msg("Fail to listen tcp tls addr")
+			os.Exit(1)
+		}
 	}
 	routines.GoSafe(func() { serv.Start() })
 
-	routines.GoSafe(func() { api.Run(serv, cfg) })
+	apiErrCh := make(chan error, 1)
+	routines.GoSafe(func() { apiErrCh <- api.Run(serv, cfg, certReloader) })
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	exitCode := 0
+	select { // block here
+	case err := <-apiErrCh:
+		log.Error().Err(err).Msg("Fail to run api server")
+		exitCode = 1
+	case sig := <-sigCh:
+		log.Info().Str("signal", sig.String()).Msg("Shutting down")
+	}
+	shutdown()
+	os.Exit(exitCode)
+}
+
+// 退出前刷新尚未导出的数据
+func shutdown() {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := tracing.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Fail to flush tracing spans")
+	}
+	storage.GetTrackStore().Close()
+}
 
-	select {} // block here
+// 未配置边界文件时使用内置边界数据
+func loadRegionIndex(rc *config.RegionConf) (*region.Index, error) {
+	if rc.BoundaryFile == "" {
+		return region.LoadDefault()
+	}
+	datum, err := coord.ParseDatum(rc.Datum)
+	if err != nil {
+		return nil, err
+	}
+	return region.LoadFile(rc.BoundaryFile, datum)
 }
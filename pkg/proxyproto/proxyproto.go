@@ -0,0 +1,208 @@
+// Package proxyproto parses HAProxy PROXY protocol v1/v2 headers on accepted connections,
+// so that servers behind a TCP load balancer can see the real client address.
+//
+// See https://www.haproxy.org/download/2.8/doc/proxy-protocol.txt
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	v1Prefix    = "PROXY "
+	v1MaxLen    = 107 // including CRLF
+	v2HeaderLen = 16
+)
+
+var v2Signature = []byte{0x0D, 0x0A, 0x0D, 0x0A, 0x00, 0x0D, 0x0A, 0x51, 0x55, 0x49, 0x54, 0x0A}
+
+var (
+	ErrInvalidHeader      = errors.New("Invalid proxy protocol header")
+	ErrUnsupportedVersion = errors.New("Unsupported proxy protocol version")
+)
+
+// Header is the parsed PROXY protocol header.
+type Header struct {
+	Version     int      // 1 or 2
+	Local       bool     // v2 LOCAL command or v1 UNKNOWN, the connection is made by the proxy itself
+	Source      net.Addr // real client address, nil when Local
+	Destination net.Addr // original destination address, nil when Local
+}
+
+// Conn wraps a net.Conn whose PROXY header has been consumed.
+// RemoteAddr and LocalAddr report the addresses carried in the header.
+type Conn struct {
+	net.Conn
+	reader *bufio.Reader
+	header *Header
+}
+
+func (c *Conn) Read(b []byte) (int, error) {
+	return c.reader.Read(b)
+}
+
+func (c *Conn) RemoteAddr() net.Addr {
+	if c.header != nil && c.header.Source != nil {
+		return c.header.Source
+	}
+	return c.Conn.RemoteAddr()
+}
+
+func (c *Conn) LocalAddr() net.Addr {
+	if c.header != nil && c.header.Destination != nil {
+		return c.header.Destination
+	}
+	return c.Conn.LocalAddr()
+}
+
+// Header returns the parsed header, nil if the connection carries none.
+func (c *Conn) Header() *Header {
+	return c.header
+}
+
+// Wrap reads an optional PROXY header from conn within timeout.
+// Connections without a header are returned wrapped with their original addresses.
+func Wrap(conn net.Conn, timeout time.Duration) (*Conn, error) {
+	if timeout > 0 {
+		if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+			return nil, err
+		}
+		defer func() { _ = conn.SetReadDeadline(time.Time{}) }()
+	}
+
+	reader := bufio.NewReader(conn)
+	header, err := ReadHeader(reader)
+	if err != nil {
+		return nil, err
+	}
+	return &Conn{Conn: conn, reader: reader, header: header}, nil
+}
+
+// ReadHeader parses a v1 or v2 header from r. It returns nil without consuming
+// any bytes when the stream does not start with a PROXY header.
+func ReadHeader(r *bufio.Reader) (*Header, error) {
+	first, err := r.Peek(1)
+	if err != nil {
+		return nil, err
+	}
+	switch first[0] {
+	case v1Prefix[0]:
+		prefix, err := r.Peek(len(v1Prefix))
+		if err != nil || string(prefix) != v1Prefix {
+			return nil, err
+		}
+		return readV1(r)
+	case v2Signature[0]:
+		sig, err := r.Peek(len(v2Signature))
+		if err != nil || !bytes.Equal(sig, v2Signature) {
+			return nil, err
+		}
+		return readV2(r)
+	default:
+		return nil, nil
+	}
+}
+
+func readV1(r *bufio.Reader) (*Header, error) {
+	var line []byte
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		line = append(line, b)
+		if b == '\n' {
+			break
+		}
+		if len(line) >= v1MaxLen {
+			return nil, errors.Wrap(ErrInvalidHeader, "v1 header too long")
+		}
+	}
+	if !bytes.HasSuffix(line, []byte("\r\n")) {
+		return nil, errors.Wrap(ErrInvalidHeader, "v1 header not end with CRLF")
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		return &Header{Version: 1, Local: true}, nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return nil, errors.Wrapf(ErrInvalidHeader, "v1 header %q", line)
+	}
+	src, err := parseV1Addr(fields[2], fields[4])
+	if err != nil {
+		return nil, err
+	}
+	dst, err := parseV1Addr(fields[3], fields[5])
+	if err != nil {
+		return nil, err
+	}
+	return &Header{Version: 1, Source: src, Destination: dst}, nil
+}
+
+func parseV1Addr(ipStr, portStr string) (*net.TCPAddr, error) {
+	ip := net.ParseIP(ipStr)
+	if ip == nil {
+		return nil, errors.Wrapf(ErrInvalidHeader, "invalid ip %q", ipStr)
+	}
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, errors.Wrapf(ErrInvalidHeader, "invalid port %q", portStr)
+	}
+	return &net.TCPAddr{IP: ip, Port: int(port)}, nil
+}
+
+func readV2(r *bufio.Reader) (*Header, error) {
+	fixed := make([]byte, v2HeaderLen)
+	if _, err := io.ReadFull(r, fixed); err != nil {
+		return nil, err
+	}
+	verCmd, fam := fixed[12], fixed[13]
+	if verCmd>>4 != 2 {
+		return nil, ErrUnsupportedVersion
+	}
+	payload := make([]byte, binary.BigEndian.Uint16(fixed[14:16]))
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	header := &Header{Version: 2}
+	switch verCmd & 0x0F {
+	case 0x00: // LOCAL
+		header.Local = true
+		return header, nil
+	case 0x01: // PROXY
+	default:
+		return nil, errors.Wrapf(ErrInvalidHeader, "v2 command 0x%x", verCmd&0x0F)
+	}
+
+	var ipLen int
+	switch fam >> 4 {
+	case 0x1: // AF_INET
+		ipLen = net.IPv4len
+	case 0x2: // AF_INET6
+		ipLen = net.IPv6len
+	default: // AF_UNSPEC, AF_UNIX, 地址无意义
+		header.Local = true
+		return header, nil
+	}
+	if len(payload) < ipLen*2+4 {
+		return nil, errors.Wrap(ErrInvalidHeader, "v2 address block too short")
+	}
+	srcIP := net.IP(payload[:ipLen])
+	dstIP := net.IP(payload[ipLen : ipLen*2])
+	srcPort := binary.BigEndian.Uint16(payload[ipLen*2:])
+	dstPort := binary.BigEndian.Uint16(payload[ipLen*2+2:])
+	header.Source = &net.TCPAddr{IP: srcIP, Port: int(srcPort)}
+	header.Destination = &net.TCPAddr{IP: dstIP, Port: int(dstPort)}
+	return header, nil
+}
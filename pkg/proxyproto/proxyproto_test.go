@@ -0,0 +1,131 @@
+package proxyproto
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadHeader(t *testing.T) {
+	v2TCP4 := append(append([]byte{}, v2Signature...),
+		0x21, 0x11, 0x00, 0x0C, // PROXY, TCP4, len=12
+		192, 168, 0, 1, 10, 0, 0, 1, // src, dst
+		0xDC, 0x04, 0x1F, 0x90, // 56324, 8080
+	)
+	v2Local := append(append([]byte{}, v2Signature...), 0x20, 0x00, 0x00, 0x00)
+
+	tests := []struct {
+		name     string
+		input    []byte
+		want     *Header
+		wantRest []byte
+		wantErr  error
+	}{
+		{
+			name:     "v1 tcp4",
+			input:    []byte("PROXY TCP4 192.168.0.1 10.0.0.1 56324 8080\r\n\x7e\x00\x7e"),
+			want:     &Header{Version: 1, Source: tcpAddr("192.168.0.1:56324"), Destination: tcpAddr("10.0.0.1:8080")},
+			wantRest: []byte{0x7e, 0x00, 0x7e},
+		},
+		{
+			name:     "v1 tcp6",
+			input:    []byte("PROXY TCP6 2001:db8::1 2001:db8::2 56324 8080\r\n"),
+			want:     &Header{Version: 1, Source: tcpAddr("[2001:db8::1]:56324"), Destination: tcpAddr("[2001:db8::2]:8080")},
+			wantRest: []byte{},
+		},
+		{
+			name:     "v1 unknown",
+			input:    []byte("PROXY UNKNOWN\r\n"),
+			want:     &Header{Version: 1, Local: true},
+			wantRest: []byte{},
+		},
+		{
+			name:    "v1 malformed",
+			input:   []byte("PROXY TCP4 192.168.0.1\r\n"),
+			wantErr: ErrInvalidHeader,
+		},
+		{
+			name:     "v2 tcp4",
+			input:    append(v2TCP4, 0x7e),
+			want:     &Header{Version: 2, Source: tcpAddr("192.168.0.1:56324"), Destination: tcpAddr("10.0.0.1:8080")},
+			wantRest: []byte{0x7e},
+		},
+		{
+			name:     "v2 local",
+			input:    v2Local,
+			want:     &Header{Version: 2, Local: true},
+			wantRest: []byte{},
+		},
+		{
+			name:     "no header",
+			input:    []byte{0x7e, 0x01, 0x02, 0x7e},
+			want:     nil,
+			wantRest: []byte{0x7e, 0x01, 0x02, 0x7e},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := bufio.NewReader(bytes.NewReader(tt.input))
+			got, err := ReadHeader(r)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, normalize(got))
+			rest, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantRest, rest)
+		})
+	}
+}
+
+func TestWrap(t *testing.T) {
+	server, client := net.Pipe()
+	defer server.Close()
+	go func() {
+		_, _ = client.Write([]byte("PROXY TCP4 192.168.0.1 10.0.0.1 56324 8080\r\n\x7e\x00\x7e"))
+		client.Close()
+	}()
+
+	conn, err := Wrap(server, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.0.1:56324", conn.RemoteAddr().String())
+	assert.Equal(t, "10.0.0.1:8080", conn.LocalAddr().String())
+
+	rest, err := io.ReadAll(conn)
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x7e, 0x00, 0x7e}, rest)
+}
+
+func tcpAddr(s string) net.Addr {
+	addr, _ := net.ResolveTCPAddr("tcp", s)
+	return normalizeAddr(addr)
+}
+
+// 统一ip的字节表示，便于比较
+func normalize(h *Header) *Header {
+	if h == nil {
+		return nil
+	}
+	h.Source = normalizeAddr(h.Source)
+	h.Destination = normalizeAddr(h.Destination)
+	return h
+}
+
+func normalizeAddr(a net.Addr) net.Addr {
+	addr, ok := a.(*net.TCPAddr)
+	if !ok || addr == nil {
+		return nil
+	}
+	ip := addr.IP.To4()
+	if ip == nil {
+		ip = addr.IP.To16()
+	}
+	return &net.TCPAddr{IP: ip, Port: addr.Port}
+}
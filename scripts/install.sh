@@ -20,6 +20,17 @@ function install_gobindata() {
 	go install github.com/go-bindata/go-bindata/...@latest
 }
 
+# 版本与go.mod中的google.golang.org/protobuf、google.golang.org/grpc保持兼容
+function install_protocgen() {
+	protoc --version >/dev/null
+	if [[ $? != 0 ]]; then
+		echo "protoc not installed, see https://grpc.io/docs/protoc-installation/"
+		return 1
+	fi
+	go install google.golang.org/protobuf/cmd/protoc-gen-go@v1.31.0
+	go install google.golang.org/grpc/cmd/protoc-gen-go-grpc@v1.3.0
+}
+
 case "$1" in
 golangcilint)
 	install_golangcilint
@@ -29,6 +40,10 @@ gobindata)
 	install_gobindata
 	ret=0
 	;;
+protocgen)
+	install_protocgen
+	ret=$?
+	;;
 *)
 	echo "UnknownArgs"
 	ret=1